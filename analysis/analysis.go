@@ -0,0 +1,236 @@
+package analysis
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// SnakeStats summarizes how one snake did in one game
+type SnakeStats struct {
+	GameID      string  `json:"gameId"`
+	Ruleset     string  `json:"ruleset"`
+	Map         string  `json:"map"`
+	SnakeID     string  `json:"snakeId"`
+	Name        string  `json:"name"`
+	Author      string  `json:"author"`
+	Won         bool    `json:"won"`
+	Turns       int32   `json:"turns"`
+	FinalLength int     `json:"finalLength"`
+	MaxLength   int     `json:"maxLength"`
+	FoodEaten   int     `json:"foodEaten"`
+	MinHealth   int32   `json:"minHealth"`
+	DeathCause  string  `json:"deathCause,omitempty"`
+	DeathTurn   int32   `json:"deathTurn,omitempty"`
+	AvgLatency  float64 `json:"avgLatencyMs"`
+}
+
+// Game computes stats for every snake in game, in the order they appear in
+// the first frame
+func Game(game *bsgf.ViewGame) []SnakeStats {
+	if len(game.Frames) == 0 {
+		return nil
+	}
+	live := NewGameStats(&game.Game)
+	for f := range game.Frames {
+		live.Update(&game.Frames[f])
+	}
+	return live.Finalize()
+}
+
+// GameStats computes SnakeStats one frame at a time, for games that are
+// still being played. Snakes are the ones in the first frame passed to
+// Update.
+type GameStats struct {
+	stats        []SnakeStats
+	index        map[string]int
+	latencyTotal []float64
+	latencyCount []int
+	frames       int
+	last         bsgf.ViewFrame
+	settings     bsgf.ViewGameSettings
+	// food of the frame before, a head landing on one being a food eaten
+	prevFood map[bsgf.ViewCoord]bool
+}
+
+// NewGameStats starts stats for a game with the given settings
+func NewGameStats(settings *bsgf.ViewGameSettings) *GameStats {
+	return &GameStats{settings: *settings}
+}
+
+// Update adds the next frame of the game. frame isn't kept.
+func (g *GameStats) Update(frame *bsgf.ViewFrame) {
+	if h := bsgf.CurrentHooks(); h.OnFrameProcessed != nil {
+		start := time.Now()
+		defer func() {
+			h.OnFrameProcessed(bsgf.FrameStats{Stage: bsgf.FrameStageAnalysis, GameID: g.settings.ID, Turn: frame.Turn, Duration: time.Since(start)})
+		}()
+	}
+	if g.frames == 0 {
+		g.start(frame)
+	}
+	f := g.frames
+	g.frames++
+	// only who was eliminated when is needed to pick the winner
+	g.last.Turn = frame.Turn
+	g.last.Snakes = append(g.last.Snakes[:0], frame.Snakes...)
+	for i := range g.last.Snakes {
+		g.last.Snakes[i].Body = nil
+	}
+	for _, s := range frame.Snakes {
+		i, ok := g.index[s.ID]
+		if !ok {
+			continue
+		}
+		st := &g.stats[i]
+		if s.Death.Eliminated() {
+			if st.DeathCause == "" {
+				st.DeathCause = string(s.Death.Cause)
+				st.DeathTurn = s.Death.Turn
+			}
+			continue
+		}
+		st.Turns = frame.Turn
+		length := len(s.Body)
+		st.FinalLength = length
+		if length > st.MaxLength {
+			st.MaxLength = length
+		}
+		if f > 0 && len(s.Body) > 0 && g.prevFood[s.Body[0]] {
+			st.FoodEaten++
+		}
+		if s.Health < st.MinHealth {
+			st.MinHealth = s.Health
+		}
+		if f > 0 {
+			if ms, err := strconv.ParseFloat(s.Latency, 64); err == nil {
+				g.latencyTotal[i] += ms
+				g.latencyCount[i]++
+			}
+		}
+	}
+	clear(g.prevFood)
+	for _, p := range frame.Food {
+		g.prevFood[p] = true
+	}
+}
+
+func (g *GameStats) start(first *bsgf.ViewFrame) {
+	n := len(first.Snakes)
+	g.stats = make([]SnakeStats, n)
+	g.index = make(map[string]int, n)
+	g.latencyTotal = make([]float64, n)
+	g.latencyCount = make([]int, n)
+	g.prevFood = make(map[bsgf.ViewCoord]bool)
+	for i, s := range first.Snakes {
+		g.index[s.ID] = i
+		g.stats[i] = SnakeStats{
+			GameID:    g.settings.ID,
+			Ruleset:   g.settings.Ruleset.Name,
+			Map:       g.settings.Ruleset.Map,
+			SnakeID:   s.ID,
+			Name:      s.Name,
+			Author:    s.Author,
+			MinHealth: s.Health,
+		}
+	}
+}
+
+// Current returns the stats so far. Won is left false until Finalize.
+func (g *GameStats) Current() []SnakeStats {
+	stats := make([]SnakeStats, len(g.stats))
+	copy(stats, g.stats)
+	for i := range stats {
+		if g.latencyCount[i] > 0 {
+			stats[i].AvgLatency = g.latencyTotal[i] / float64(g.latencyCount[i])
+		}
+	}
+	return stats
+}
+
+// Finalize returns the stats once the game is over, with the winner set
+// from the last frame passed to Update.
+func (g *GameStats) Finalize() []SnakeStats {
+	if g.frames == 0 {
+		return nil
+	}
+	stats := g.Current()
+	final := bsgf.ViewGame{Frames: []bsgf.ViewFrame{g.last}}
+	winnerId := final.Winner()
+	for i := range stats {
+		stats[i].Won = stats[i].SnakeID == winnerId
+	}
+	return stats
+}
+
+// Winner returns the ID of the winning snake, or "" for draws
+//
+// Deprecated: use (*bsgf.ViewGame).Winner
+func Winner(game *bsgf.ViewGame) string {
+	return game.Winner()
+}
+
+// Summary aggregates SnakeStats that share a group key
+type Summary struct {
+	Key            string         `json:"key"`
+	Games          int            `json:"games"`
+	Wins           int            `json:"wins"`
+	WinRate        float64        `json:"winRate"`
+	AvgTurns       float64        `json:"avgTurns"`
+	AvgFinalLength float64        `json:"avgFinalLength"`
+	AvgFoodEaten   float64        `json:"avgFoodEaten"`
+	AvgLatency     float64        `json:"avgLatencyMs"`
+	Deaths         map[string]int `json:"deaths"`
+}
+
+// GroupBy returns the key a SnakeStats is aggregated under
+type GroupBy func(s *SnakeStats) string
+
+var (
+	BySnake   GroupBy = func(s *SnakeStats) string { return s.Name }
+	ByAuthor  GroupBy = func(s *SnakeStats) string { return s.Author }
+	ByRuleset GroupBy = func(s *SnakeStats) string { return s.Ruleset }
+	ByMap     GroupBy = func(s *SnakeStats) string { return s.Map }
+)
+
+// Summarize aggregates stats by key, sorted by key
+func Summarize(stats []SnakeStats, key GroupBy) []Summary {
+	groups := make(map[string]*Summary)
+	var keys []string
+	for i := range stats {
+		s := &stats[i]
+		k := key(s)
+		sum, ok := groups[k]
+		if !ok {
+			sum = &Summary{Key: k, Deaths: make(map[string]int)}
+			groups[k] = sum
+			keys = append(keys, k)
+		}
+		sum.Games++
+		if s.Won {
+			sum.Wins++
+		}
+		sum.AvgTurns += float64(s.Turns)
+		sum.AvgFinalLength += float64(s.FinalLength)
+		sum.AvgFoodEaten += float64(s.FoodEaten)
+		sum.AvgLatency += s.AvgLatency
+		if s.DeathCause != "" {
+			sum.Deaths[s.DeathCause]++
+		}
+	}
+	sort.Strings(keys)
+	result := make([]Summary, 0, len(keys))
+	for _, k := range keys {
+		sum := groups[k]
+		n := float64(sum.Games)
+		sum.WinRate = float64(sum.Wins) / n
+		sum.AvgTurns /= n
+		sum.AvgFinalLength /= n
+		sum.AvgFoodEaten /= n
+		sum.AvgLatency /= n
+		result = append(result, *sum)
+	}
+	return result
+}
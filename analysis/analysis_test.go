@@ -0,0 +1,132 @@
+package analysis_test
+
+import (
+	"context"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+)
+
+func play(t *testing.T, ruleset string) *bsgf.ViewGame {
+	t.Helper()
+	players := []harness.Player{
+		{Name: "Alpha", Snake: harness.NewRandomSnake(1)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(2)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: 11, Ruleset: bsgf.ViewRuleset{Name: ruleset}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestGameStats(t *testing.T) {
+	for _, ruleset := range []string{"standard", "constrictor"} {
+		t.Run(ruleset, func(t *testing.T) {
+			game := play(t, ruleset)
+			// a food is eaten when a head lands where there was food
+			eaten := make(map[string]int)
+			for i := 1; i < len(game.Frames); i++ {
+				food := make(map[bsgf.ViewCoord]bool)
+				for _, p := range game.Frames[i-1].Food {
+					food[p] = true
+				}
+				for _, s := range game.Frames[i].Snakes {
+					if !s.Death.Eliminated() && food[s.Body[0]] {
+						eaten[s.ID]++
+					}
+				}
+			}
+			stats := analysis.Game(game)
+			if len(stats) != 2 {
+				t.Fatalf("expected stats for 2 snakes, got %d", len(stats))
+			}
+			for _, st := range stats {
+				if st.FoodEaten != eaten[st.SnakeID] {
+					t.Errorf("%s: expected %d food eaten, got %d", st.Name, eaten[st.SnakeID], st.FoodEaten)
+				}
+				if st.Won != (st.SnakeID == game.Winner()) {
+					t.Errorf("%s: Won is %v", st.Name, st.Won)
+				}
+				// constrictor snakes grow every turn without food
+				if ruleset == "constrictor" && (st.FoodEaten != 0 || st.MaxLength <= 3) {
+					t.Errorf("%s: ate %d and reached length %d", st.Name, st.FoodEaten, st.MaxLength)
+				}
+			}
+		})
+	}
+}
+
+func snake(body ...bsgf.ViewCoord) bsgf.ViewSnake {
+	return bsgf.ViewSnake{Body: body}
+}
+
+func TestVoronoi(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int32
+		snakes        []bsgf.ViewSnake
+		want          []int
+	}{
+		{
+			"contested middle", 5, 1,
+			[]bsgf.ViewSnake{snake(bsgf.ViewCoord{X: 0, Y: 0}), snake(bsgf.ViewCoord{X: 4, Y: 0})},
+			[]int{0, 0, -1, 1, 1},
+		},
+		{
+			"even split", 4, 1,
+			[]bsgf.ViewSnake{snake(bsgf.ViewCoord{X: 0, Y: 0}), snake(bsgf.ViewCoord{X: 3, Y: 0})},
+			[]int{0, 0, 1, 1},
+		},
+		{
+			// the body walls off the right column, which only the second
+			// snake reaches
+			"around a body", 3, 3,
+			[]bsgf.ViewSnake{
+				snake(bsgf.ViewCoord{X: 0, Y: 0}),
+				snake(bsgf.ViewCoord{X: 2, Y: 2}, bsgf.ViewCoord{X: 1, Y: 2}, bsgf.ViewCoord{X: 1, Y: 1}, bsgf.ViewCoord{X: 1, Y: 0}),
+			},
+			[]int{0, -1, 1, 0, -1, 1, 0, -1, 1},
+		},
+		{
+			"stacked heads", 3, 1,
+			[]bsgf.ViewSnake{snake(bsgf.ViewCoord{X: 1, Y: 0}), snake(bsgf.ViewCoord{X: 1, Y: 0})},
+			[]int{-1, -1, -1},
+		},
+		{
+			"dead snakes own nothing", 3, 1,
+			[]bsgf.ViewSnake{
+				{Body: []bsgf.ViewCoord{{X: 2, Y: 0}}, Death: bsgf.ViewDeath{Cause: bsgf.CauseOutOfHealth, Turn: 1}},
+				snake(bsgf.ViewCoord{X: 0, Y: 0}),
+			},
+			[]int{1, 1, 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			frame := &bsgf.ViewFrame{Snakes: test.snakes}
+			got := analysis.Voronoi(test.width, test.height, frame)
+			if len(got) != len(test.want) {
+				t.Fatalf("expected %d cells, got %d", len(test.want), len(got))
+			}
+			counts := make([]int, len(test.snakes))
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("expected %v, got %v", test.want, got)
+				}
+				if got[i] >= 0 {
+					counts[got[i]]++
+				}
+			}
+			territory := analysis.Territory(test.width, test.height, frame)
+			for i := range counts {
+				if territory[i] != counts[i] {
+					t.Errorf("expected territory %v, got %v", counts, territory)
+					break
+				}
+			}
+		})
+	}
+}
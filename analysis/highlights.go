@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// How much each kind of moment adds to a turn's excitement
+const (
+	eliminationScore = 10
+	// heads next to each other, where a head to head could happen
+	headToHeadScore = 3
+	// eating with 10 or less health left
+	starvingScore = 4
+	// a swing of the whole board between snakes' territory
+	territorySwingScore = 20
+)
+
+// Moment is something exciting on one turn
+type Moment struct {
+	Turn    int32   `json:"turn"`
+	Score   float64 `json:"score"`
+	Message string  `json:"message"`
+}
+
+// Highlight is a range of turns worth a clip
+type Highlight struct {
+	From    int32    `json:"from"`
+	To      int32    `json:"to"`
+	Score   float64  `json:"score"`
+	Moments []Moment `json:"moments"`
+}
+
+type HighlightOptions struct {
+	// Before and After are how many turns to show around the moments of a
+	// clip. 0 means 6 before and 3 after.
+	Before, After int32
+	// Count is the most highlights to return. 0 means 3.
+	Count int
+}
+
+// Moments scores every turn of game for eliminations, close calls (heads
+// side by side, eating while nearly starved) and swings in territory
+func Moments(game *bsgf.ViewGame) []Moment {
+	var moments []Moment
+	width, height := game.Game.Width, game.Game.Height
+	cells := float64(width * height)
+	var prevTerritory []int
+	var prev *bsgf.ViewFrame
+	for i := range game.Frames {
+		frame := &game.Frames[i]
+		for k := range frame.Snakes {
+			s := &frame.Snakes[k]
+			if !s.Alive() {
+				if s.Death.Turn == frame.Turn {
+					moments = append(moments, Moment{Turn: frame.Turn, Score: eliminationScore, Message: fmt.Sprintf("%s eliminated (%s)", s.Name, s.Death.Cause)})
+				}
+				continue
+			}
+			if len(s.Body) == 0 {
+				continue
+			}
+			for j := k + 1; j < len(frame.Snakes); j++ {
+				o := &frame.Snakes[j]
+				if o.Alive() && len(o.Body) > 0 && s.Body[0].Manhattan(o.Body[0]) <= 2 {
+					moments = append(moments, Moment{Turn: frame.Turn, Score: headToHeadScore, Message: fmt.Sprintf("%s and %s head to head", s.Name, o.Name)})
+				}
+			}
+			if prev == nil {
+				continue
+			}
+			for _, p := range prev.Snakes {
+				if p.ID == s.ID && p.Health <= 10 && s.Health > p.Health {
+					moments = append(moments, Moment{Turn: frame.Turn, Score: starvingScore, Message: fmt.Sprintf("%s eats with %d health left", s.Name, p.Health)})
+				}
+			}
+		}
+		territory := Territory(width, height, frame)
+		if prevTerritory != nil && len(territory) == len(prevTerritory) && cells > 0 {
+			swing := 0
+			for k := range territory {
+				d := territory[k] - prevTerritory[k]
+				if d < 0 {
+					d = -d
+				}
+				swing += d
+			}
+			// each cell that changes hands is counted for both snakes
+			share := float64(swing) / 2 / cells
+			if share >= 0.1 {
+				moments = append(moments, Moment{Turn: frame.Turn, Score: share * territorySwingScore, Message: fmt.Sprintf("%.0f%% of the board changes hands", 100*share)})
+			}
+		}
+		prevTerritory = territory
+		prev = frame
+	}
+	return moments
+}
+
+// Highlights picks the most exciting, non overlapping turn ranges of game,
+// best first. Turns are frame indexes, ready for Slice or render.GIF.
+func Highlights(game *bsgf.ViewGame, opts HighlightOptions) []Highlight {
+	if opts.Before <= 0 && opts.After <= 0 {
+		opts.Before, opts.After = 6, 3
+	}
+	if opts.Count <= 0 {
+		opts.Count = 3
+	}
+	last := int32(len(game.Frames) - 1)
+	if last < 0 {
+		return nil
+	}
+	moments := Moments(game)
+	var candidates []Highlight
+	for _, m := range moments {
+		h := Highlight{From: m.Turn - opts.Before, To: m.Turn + opts.After}
+		if h.From < 0 {
+			h.From = 0
+		}
+		if h.To > last {
+			h.To = last
+		}
+		for _, o := range moments {
+			if o.Turn >= h.From && o.Turn <= h.To {
+				h.Score += o.Score
+				h.Moments = append(h.Moments, o)
+			}
+		}
+		candidates = append(candidates, h)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].From < candidates[j].From
+	})
+	var picked []Highlight
+	for _, h := range candidates {
+		if len(picked) == opts.Count {
+			break
+		}
+		overlaps := false
+		for _, p := range picked {
+			if h.From <= p.To && p.From <= h.To {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			picked = append(picked, h)
+		}
+	}
+	return picked
+}
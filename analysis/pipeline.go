@@ -0,0 +1,167 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+// Analyzer is one computation run over every game in a corpus. Analyze is
+// called from several goroutines at once and should only look at the game
+// it's given. Merge folds each result into the analyzer's totals and is
+// only ever called from one goroutine.
+type Analyzer interface {
+	Analyze(game *bsgf.ViewGame) (interface{}, error)
+	Merge(result interface{})
+}
+
+// Options for Run
+type Options struct {
+	// Workers is the number of games decoded and analyzed at once,
+	// defaulting to the number of CPUs
+	Workers int
+	// SkipErrors records games that fail to load or analyze in the report
+	// instead of stopping the run
+	SkipErrors bool
+}
+
+// GameError is a game that couldn't be loaded or analyzed
+type GameError struct {
+	ID  string
+	Err error
+}
+
+func (e *GameError) Error() string {
+	return fmt.Sprintf("game %s: %s", e.ID, e.Err)
+}
+
+func (e *GameError) Unwrap() error {
+	return e.Err
+}
+
+// Report is the outcome of a Run. Results are in the analyzers themselves.
+type Report struct {
+	Games  int
+	Failed []GameError
+}
+
+// Run loads every game in ids from s (every stored game when ids is nil)
+// and passes each through all the analyzers. Games are loaded and analyzed
+// across worker goroutines and dropped once analyzed, so memory depends on
+// the number of workers rather than the size of the corpus.
+func Run(ctx context.Context, s store.Store, ids []string, opts Options, analyzers ...Analyzer) (*Report, error) {
+	if ids == nil {
+		var err error
+		ids, err = s.List()
+		if err != nil {
+			return nil, err
+		}
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	type outcome struct {
+		id      string
+		results []interface{}
+		err     error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	work := make(chan string)
+	outcomes := make(chan outcome, workers)
+	go func() {
+		defer close(work)
+		for _, id := range ids {
+			select {
+			case work <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range work {
+				o := outcome{id: id}
+				o.results, o.err = analyze(s, id, analyzers)
+				select {
+				case outcomes <- o:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	report := &Report{}
+	for o := range outcomes {
+		if o.err != nil {
+			if !opts.SkipErrors {
+				cancel()
+				return nil, &GameError{ID: o.id, Err: o.err}
+			}
+			report.Failed = append(report.Failed, GameError{ID: o.id, Err: o.err})
+			continue
+		}
+		for i, a := range analyzers {
+			a.Merge(o.results[i])
+		}
+		report.Games++
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func analyze(s store.Store, id string, analyzers []Analyzer) ([]interface{}, error) {
+	game, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, len(analyzers))
+	for i, a := range analyzers {
+		results[i], err = a.Analyze(game)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// StatsAnalyzer collects SnakeStats for every snake in every game
+type StatsAnalyzer struct {
+	// Filter, when set, keeps only the stats it returns true for
+	Filter func(s *SnakeStats) bool
+	Stats  []SnakeStats
+}
+
+func (a *StatsAnalyzer) Analyze(game *bsgf.ViewGame) (interface{}, error) {
+	stats := Game(game)
+	if a.Filter == nil {
+		return stats, nil
+	}
+	kept := stats[:0]
+	for i := range stats {
+		if a.Filter(&stats[i]) {
+			kept = append(kept, stats[i])
+		}
+	}
+	return kept, nil
+}
+
+func (a *StatsAnalyzer) Merge(result interface{}) {
+	a.Stats = append(a.Stats, result.([]SnakeStats)...)
+}
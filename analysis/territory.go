@@ -0,0 +1,108 @@
+package analysis
+
+import (
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/bitboard"
+)
+
+// Voronoi assigns every cell to the live snake whose head can reach it first,
+// moving around snake bodies. The result is indexed by y*width+x and holds
+// the index of the owning snake in frame.Snakes, or -1 for cells that are
+// contested, blocked or unreachable.
+func Voronoi(width, height int32, frame *bsgf.ViewFrame) []int {
+	size := int(width * height)
+	owner := make([]int, size)
+	for i := range owner {
+		owner[i] = -1
+	}
+	if size == 0 {
+		return owner
+	}
+	open := bitboard.Full(width, height)
+	for _, s := range frame.Snakes {
+		if s.Death.Eliminated() {
+			continue
+		}
+		for _, c := range s.Body {
+			open.Clear(c)
+		}
+	}
+	// each snake spreads out from its head one step at a time. Heads that
+	// share a cell spread as one region that nobody owns.
+	var owners []int
+	var fronts []bitboard.Bitboard
+	heads := bitboard.New(width, height)
+	shared := -1
+	for i, s := range frame.Snakes {
+		if s.Death.Eliminated() || len(s.Body) == 0 || !s.Body[0].InBounds(width, height) {
+			continue
+		}
+		head := s.Body[0]
+		if heads.Has(head) {
+			for k := range fronts {
+				if fronts[k].Has(head) && owners[k] != -1 {
+					fronts[k].Clear(head)
+					if shared < 0 {
+						shared = len(fronts)
+						owners = append(owners, -1)
+						fronts = append(fronts, bitboard.New(width, height))
+					}
+					fronts[shared].Set(head)
+				}
+			}
+			continue
+		}
+		heads.Set(head)
+		owners = append(owners, i)
+		front := bitboard.New(width, height)
+		front.Set(head)
+		fronts = append(fronts, front)
+	}
+	for k := range fronts {
+		if owners[k] >= 0 {
+			fronts[k].Each(func(c bsgf.ViewCoord) { owner[c.Y*width+c.X] = owners[k] })
+		}
+	}
+	visited := heads
+	// cells reached by one region this step, and by more than one
+	once := bitboard.New(width, height)
+	twice := bitboard.New(width, height)
+	scratch := bitboard.New(width, height)
+	empty := bitboard.New(width, height)
+	for {
+		once.Assign(empty)
+		twice.Assign(empty)
+		for k := range fronts {
+			scratch.ExpandFrom(fronts[k])
+			fronts[k].Assign(scratch)
+			fronts[k].And(open)
+			fronts[k].AndNot(visited)
+			scratch.Assign(fronts[k])
+			scratch.And(once)
+			twice.Or(scratch)
+			once.Or(fronts[k])
+		}
+		if once.Empty() {
+			return owner
+		}
+		for k := range fronts {
+			// contested cells belong to nobody and stop spreading
+			fronts[k].AndNot(twice)
+			if owners[k] >= 0 {
+				fronts[k].Each(func(c bsgf.ViewCoord) { owner[c.Y*width+c.X] = owners[k] })
+			}
+		}
+		visited.Or(once)
+	}
+}
+
+// Territory counts the cells owned by each snake in frame.Snakes
+func Territory(width, height int32, frame *bsgf.ViewFrame) []int {
+	counts := make([]int, len(frame.Snakes))
+	for _, o := range Voronoi(width, height, frame) {
+		if o >= 0 {
+			counts[o]++
+		}
+	}
+	return counts
+}
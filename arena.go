@@ -0,0 +1,89 @@
+package battlesnakegameformat
+
+// Number of coords allocated at a time by a MoveArena
+const arenaChunkSize = 4096
+
+// MoveArena hands out the slices for translated move requests from a few
+// large allocations that are reused after Reset, cutting garbage in loops
+// that translate every turn of many games. States from an arena share its
+// memory and must not be used after the next Reset. The zero value is ready
+// to use. A MoveArena is not safe for concurrent use.
+type MoveArena struct {
+	chunks      [][]MoveCoord
+	chunk       int
+	snakeChunks [][]MoveBattlesnake
+	snakeChunk  int
+}
+
+// ToMove is (*ViewGame).ToMove using the arena's memory
+func (a *MoveArena) ToMove(game *ViewGame, turn int32, snakeId string) (*MoveGameState, error) {
+	return game.toMove(turn, snakeId, a)
+}
+
+// ToMoveAll is (*ViewGame).ToMoveAll using the arena's memory
+func (a *MoveArena) ToMoveAll(game *ViewGame, turn int32) ([]*MoveGameState, error) {
+	return game.toMoveAll(turn, a)
+}
+
+// Reset makes all the arena's memory available again, invalidating every
+// state it produced
+func (a *MoveArena) Reset() {
+	for i := range a.chunks {
+		a.chunks[i] = a.chunks[i][:0]
+	}
+	for i := range a.snakeChunks {
+		a.snakeChunks[i] = a.snakeChunks[i][:0]
+	}
+	a.chunk = 0
+	a.snakeChunk = 0
+}
+
+// coords returns an empty slice with room for n coords
+func (a *MoveArena) coords(n int) []MoveCoord {
+	for ; a.chunk < len(a.chunks); a.chunk++ {
+		c := a.chunks[a.chunk]
+		if cap(c)-len(c) >= n {
+			a.chunks[a.chunk] = c[:len(c)+n]
+			return c[len(c) : len(c) : len(c)+n]
+		}
+	}
+	size := arenaChunkSize
+	if n > size {
+		size = n
+	}
+	c := make([]MoveCoord, n, size)
+	a.chunks = append(a.chunks, c)
+	return c[0:0:n]
+}
+
+// snakes returns an empty slice with room for n snakes
+func (a *MoveArena) snakes(n int) []MoveBattlesnake {
+	if a == nil {
+		return make([]MoveBattlesnake, 0, n)
+	}
+	for ; a.snakeChunk < len(a.snakeChunks); a.snakeChunk++ {
+		c := a.snakeChunks[a.snakeChunk]
+		if cap(c)-len(c) >= n {
+			a.snakeChunks[a.snakeChunk] = c[:len(c)+n]
+			return c[len(c) : len(c) : len(c)+n]
+		}
+	}
+	size := arenaChunkSize / 16
+	if n > size {
+		size = n
+	}
+	c := make([]MoveBattlesnake, n, size)
+	a.snakeChunks = append(a.snakeChunks, c)
+	return c[0:0:n]
+}
+
+func (a *MoveArena) convertCoords(coords []ViewCoord) []MoveCoord {
+	if a == nil {
+		return convertCoords(coords)
+	}
+	result := a.coords(len(coords))
+	for _, c := range coords {
+		result = append(result, MoveCoord(c))
+	}
+	return result
+}
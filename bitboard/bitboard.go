@@ -0,0 +1,247 @@
+package bitboard
+
+import (
+	"math/bits"
+	"sync"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Bitboard is a set of cells on a board, one bit per cell indexed by
+// y*Width+x. Set operations work a word at a time, which makes flood fills
+// over the whole board cheap.
+type Bitboard struct {
+	Width, Height int32
+	words         []uint64
+}
+
+// New returns an empty bitboard
+func New(width, height int32) Bitboard {
+	return Bitboard{Width: width, Height: height, words: make([]uint64, (int(width*height)+63)/64)}
+}
+
+// Full returns a bitboard with every cell set
+func Full(width, height int32) Bitboard {
+	b := New(width, height)
+	for i := range b.words {
+		b.words[i] = ^uint64(0)
+	}
+	b.trim()
+	return b
+}
+
+// FromCoords returns a bitboard with the in bounds coords set
+func FromCoords(width, height int32, coords []bsgf.ViewCoord) Bitboard {
+	b := New(width, height)
+	for _, c := range coords {
+		b.Set(c)
+	}
+	return b
+}
+
+func (b *Bitboard) index(c bsgf.ViewCoord) (int, bool) {
+	if !c.InBounds(b.Width, b.Height) {
+		return 0, false
+	}
+	return int(c.Y*b.Width + c.X), true
+}
+
+// Has is true if c is set
+func (b *Bitboard) Has(c bsgf.ViewCoord) bool {
+	i, ok := b.index(c)
+	return ok && b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Set adds c, ignoring coords off the board
+func (b *Bitboard) Set(c bsgf.ViewCoord) {
+	if i, ok := b.index(c); ok {
+		b.words[i/64] |= 1 << (i % 64)
+	}
+}
+
+// Clear removes c
+func (b *Bitboard) Clear(c bsgf.ViewCoord) {
+	if i, ok := b.index(c); ok {
+		b.words[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// Count is the number of cells set
+func (b *Bitboard) Count() int {
+	n := 0
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Empty is true when no cells are set
+func (b *Bitboard) Empty() bool {
+	for _, w := range b.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy that shares no memory with b
+func (b Bitboard) Clone() Bitboard {
+	b.words = append([]uint64(nil), b.words...)
+	return b
+}
+
+// Or adds every cell in o to b
+func (b *Bitboard) Or(o Bitboard) {
+	for i := range b.words {
+		b.words[i] |= o.words[i]
+	}
+}
+
+// And keeps only the cells of b that are also in o
+func (b *Bitboard) And(o Bitboard) {
+	for i := range b.words {
+		b.words[i] &= o.words[i]
+	}
+}
+
+// AndNot removes every cell in o from b
+func (b *Bitboard) AndNot(o Bitboard) {
+	for i := range b.words {
+		b.words[i] &^= o.words[i]
+	}
+}
+
+// Coords lists the cells set, ordered by y then x
+func (b *Bitboard) Coords() []bsgf.ViewCoord {
+	coords := make([]bsgf.ViewCoord, 0, b.Count())
+	b.Each(func(c bsgf.ViewCoord) {
+		coords = append(coords, c)
+	})
+	return coords
+}
+
+// Each calls fn with every cell set, ordered by y then x
+func (b *Bitboard) Each(fn func(c bsgf.ViewCoord)) {
+	for wi, w := range b.words {
+		for w != 0 {
+			i := wi*64 + bits.TrailingZeros64(w)
+			fn(bsgf.ViewCoord{X: int32(i) % b.Width, Y: int32(i) / b.Width})
+			w &= w - 1
+		}
+	}
+}
+
+// Expand returns b plus every cell next to a cell in b, without wrapping
+// around the edges
+func (b Bitboard) Expand() Bitboard {
+	out := New(b.Width, b.Height)
+	out.ExpandFrom(b)
+	return out
+}
+
+// ExpandFrom sets b to src plus every cell next to a cell in src, reusing
+// b's memory. b and src must be different bitboards of the same size.
+func (b *Bitboard) ExpandFrom(src Bitboard) {
+	m := masksFor(src.Width, src.Height)
+	w := int(src.Width)
+	for i := range b.words {
+		v := src.words[i]
+		v |= shiftedWord(src.words, i, w) | shiftedWord(src.words, i, -w)
+		// moving right from the last column lands in the first column of
+		// the next row and moving left from the first column in the
+		// previous row
+		v |= shiftedWord(src.words, i, 1) & m.notFirst[i]
+		v |= shiftedWord(src.words, i, -1) & m.notLast[i]
+		b.words[i] = v
+	}
+	b.trim()
+}
+
+// Assign sets b to the same cells as o, reusing b's memory
+func (b *Bitboard) Assign(o Bitboard) {
+	copy(b.words, o.words)
+}
+
+// shiftedWord is word i of words shifted n bits towards higher indexes, or
+// lower ones when n is negative
+func shiftedWord(words []uint64, i, n int) uint64 {
+	if n < 0 {
+		n = -n
+		ws, bs := n/64, uint(n%64)
+		var v uint64
+		if i+ws < len(words) {
+			v = words[i+ws] >> bs
+		}
+		if bs > 0 && i+ws+1 < len(words) {
+			v |= words[i+ws+1] << (64 - bs)
+		}
+		return v
+	}
+	ws, bs := n/64, uint(n%64)
+	var v uint64
+	if i-ws >= 0 {
+		v = words[i-ws] << bs
+	}
+	if bs > 0 && i-ws-1 >= 0 {
+		v |= words[i-ws-1] >> (64 - bs)
+	}
+	return v
+}
+
+// Column masks used by ExpandFrom, cached per board size
+type edgeMasks struct {
+	notFirst, notLast []uint64
+}
+
+var masks sync.Map // [2]int32 to *edgeMasks
+
+func masksFor(width, height int32) *edgeMasks {
+	key := [2]int32{width, height}
+	if m, ok := masks.Load(key); ok {
+		return m.(*edgeMasks)
+	}
+	first := Full(width, height)
+	last := Full(width, height)
+	for y := int32(0); y < height; y++ {
+		first.Clear(bsgf.ViewCoord{X: 0, Y: y})
+		last.Clear(bsgf.ViewCoord{X: width - 1, Y: y})
+	}
+	m, _ := masks.LoadOrStore(key, &edgeMasks{notFirst: first.words, notLast: last.words})
+	return m.(*edgeMasks)
+}
+
+// trim clears the bits past the last cell
+func (b *Bitboard) trim() {
+	if len(b.words) == 0 {
+		return
+	}
+	if extra := len(b.words)*64 - int(b.Width*b.Height); extra > 0 {
+		b.words[len(b.words)-1] &= ^uint64(0) >> uint(extra)
+	}
+}
+
+// FloodFill returns every cell reachable from start by moving through open
+// cells, including start itself
+func FloodFill(start, open Bitboard) Bitboard {
+	filled := start.Clone()
+	next := New(start.Width, start.Height)
+	for {
+		next.ExpandFrom(filled)
+		next.And(open)
+		next.Or(start)
+		if next.equal(filled) {
+			return filled
+		}
+		filled, next = next, filled
+	}
+}
+
+func (b *Bitboard) equal(o Bitboard) bool {
+	for i := range b.words {
+		if b.words[i] != o.words[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package bitboard_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/bitboard"
+)
+
+// sizes include boards whose rows cross or end on a word boundary
+var sizes = [][2]int32{{7, 7}, {8, 8}, {11, 11}, {19, 19}, {25, 25}, {64, 1}, {1, 64}, {63, 3}, {65, 2}, {13, 5}}
+
+func randomBoard(rng *rand.Rand, width, height int32, density float64) bitboard.Bitboard {
+	b := bitboard.New(width, height)
+	for y := int32(0); y < height; y++ {
+		for x := int32(0); x < width; x++ {
+			if rng.Float64() < density {
+				b.Set(bsgf.ViewCoord{X: x, Y: y})
+			}
+		}
+	}
+	return b
+}
+
+func neighbours(c bsgf.ViewCoord) []bsgf.ViewCoord {
+	return []bsgf.ViewCoord{{X: c.X + 1, Y: c.Y}, {X: c.X - 1, Y: c.Y}, {X: c.X, Y: c.Y + 1}, {X: c.X, Y: c.Y - 1}}
+}
+
+func inBounds(c bsgf.ViewCoord, width, height int32) bool {
+	return c.X >= 0 && c.X < width && c.Y >= 0 && c.Y < height
+}
+
+func assertCells(t *testing.T, got bitboard.Bitboard, want map[bsgf.ViewCoord]bool) {
+	t.Helper()
+	for y := int32(0); y < got.Height; y++ {
+		for x := int32(0); x < got.Width; x++ {
+			c := bsgf.ViewCoord{X: x, Y: y}
+			if got.Has(c) != want[c] {
+				t.Fatalf("cell %v is %v, expected %v", c, got.Has(c), want[c])
+			}
+		}
+	}
+	if got.Count() != len(want) {
+		t.Fatalf("expected %d cells, got %d", len(want), got.Count())
+	}
+}
+
+func TestExpand(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range sizes {
+		width, height := size[0], size[1]
+		for _, density := range []float64{0, 0.05, 0.3, 1} {
+			b := randomBoard(rng, width, height, density)
+			want := make(map[bsgf.ViewCoord]bool)
+			b.Each(func(c bsgf.ViewCoord) {
+				want[c] = true
+				for _, n := range neighbours(c) {
+					if inBounds(n, width, height) {
+						want[n] = true
+					}
+				}
+			})
+			assertCells(t, b.Expand(), want)
+			// ExpandFrom into a board that already has cells
+			out := bitboard.Full(width, height)
+			out.ExpandFrom(b)
+			assertCells(t, out, want)
+		}
+	}
+}
+
+func TestFloodFill(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, size := range sizes {
+		width, height := size[0], size[1]
+		open := randomBoard(rng, width, height, 0.6)
+		start := bsgf.ViewCoord{X: width / 2, Y: height / 2}
+		want := map[bsgf.ViewCoord]bool{start: true}
+		queue := []bsgf.ViewCoord{start}
+		for len(queue) > 0 {
+			c := queue[0]
+			queue = queue[1:]
+			for _, n := range neighbours(c) {
+				if inBounds(n, width, height) && open.Has(n) && !want[n] {
+					want[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+		assertCells(t, bitboard.FloodFill(bitboard.FromCoords(width, height, []bsgf.ViewCoord{start}), open), want)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := bitboard.FromCoords(5, 5, []bsgf.ViewCoord{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 4, Y: 4}, {X: 5, Y: 0}})
+	b := bitboard.FromCoords(5, 5, []bsgf.ViewCoord{{X: 1, Y: 0}, {X: 2, Y: 2}})
+	if a.Count() != 3 {
+		t.Errorf("out of bounds coords are set, got %v", a.Coords())
+	}
+	or, and, andNot := a.Clone(), a.Clone(), a.Clone()
+	or.Or(b)
+	and.And(b)
+	andNot.AndNot(b)
+	for _, test := range []struct {
+		name string
+		b    bitboard.Bitboard
+		want []bsgf.ViewCoord
+	}{
+		{"Or", or, []bsgf.ViewCoord{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 2}, {X: 4, Y: 4}}},
+		{"And", and, []bsgf.ViewCoord{{X: 1, Y: 0}}},
+		{"AndNot", andNot, []bsgf.ViewCoord{{X: 0, Y: 0}, {X: 4, Y: 4}}},
+	} {
+		if got := test.b.Coords(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+	if a.Count() != 3 {
+		t.Error("Clone shares memory")
+	}
+	full := bitboard.Full(5, 5)
+	count := full.Count()
+	full.AndNot(bitboard.Full(5, 5))
+	if !full.Empty() || count != 25 {
+		t.Error("Full has cells off the board")
+	}
+}
+
+func TestBoardFrame(t *testing.T) {
+	frame := &bsgf.ViewFrame{
+		Turn:         3,
+		Food:         []bsgf.ViewCoord{{X: 1, Y: 1}, {X: 0, Y: 0}},
+		Hazards:      []bsgf.ViewCoord{{X: 2, Y: 0}},
+		HazardDamage: 30,
+		Snakes: []bsgf.ViewSnake{
+			{ID: "a", Health: 90, Body: []bsgf.ViewCoord{{X: 2, Y: 2}, {X: 2, Y: 3}, {X: 3, Y: 3}}},
+			{ID: "b", Body: []bsgf.ViewCoord{{X: 4, Y: 4}}, Death: bsgf.ViewDeath{Cause: bsgf.CauseWallCollision, Turn: 2}},
+		},
+	}
+	board := bitboard.FromFrame(5, 5, frame)
+	if open := board.Open(); open.Count() != 22 || open.Has(bsgf.ViewCoord{X: 2, Y: 3}) {
+		t.Errorf("expected the live snake's cells to be closed, got %v open", open.Count())
+	}
+	got := board.ToFrame()
+	// food comes back ordered by position
+	frame.Food = []bsgf.ViewCoord{{X: 0, Y: 0}, {X: 1, Y: 1}}
+	if !reflect.DeepEqual(got, frame) {
+		t.Errorf("expected %+v, got %+v", frame, got)
+	}
+}
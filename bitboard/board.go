@@ -0,0 +1,76 @@
+package bitboard
+
+import (
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Board is a frame with its food, hazards and snake bodies held as
+// bitboards for fast simulation and search. Snakes keep their ordered body
+// and metadata so the board converts back to the same frame, except that
+// food and hazards come back ordered by position and stacked hazards
+// become one.
+type Board struct {
+	Width, Height int32
+	Turn          int32
+	Food          Bitboard
+	Hazards       Bitboard
+	// HazardDamage is the frame's HazardDamage, 0 when the ruleset's applies
+	HazardDamage int32
+	// Occupied is every cell covered by a live snake
+	Occupied Bitboard
+	Snakes   []Snake
+}
+
+// Snake is a snake on a Board
+type Snake struct {
+	bsgf.ViewSnake
+	// Cells covered by the body, empty for eliminated snakes
+	Cells Bitboard
+}
+
+// FromFrame builds a board from a frame. The frame's slices are copied.
+func FromFrame(width, height int32, frame *bsgf.ViewFrame) *Board {
+	b := &Board{
+		Width:        width,
+		Height:       height,
+		Turn:         frame.Turn,
+		Food:         FromCoords(width, height, frame.Food),
+		Hazards:      FromCoords(width, height, frame.Hazards),
+		HazardDamage: frame.HazardDamage,
+		Occupied:     New(width, height),
+		Snakes:       make([]Snake, len(frame.Snakes)),
+	}
+	for i, s := range frame.Snakes {
+		s.Body = append([]bsgf.ViewCoord(nil), s.Body...)
+		b.Snakes[i] = Snake{ViewSnake: s, Cells: New(width, height)}
+		if s.Death.Eliminated() {
+			continue
+		}
+		b.Snakes[i].Cells = FromCoords(width, height, s.Body)
+		b.Occupied.Or(b.Snakes[i].Cells)
+	}
+	return b
+}
+
+// ToFrame converts the board back to a frame
+func (b *Board) ToFrame() *bsgf.ViewFrame {
+	frame := &bsgf.ViewFrame{
+		Turn:         b.Turn,
+		Food:         b.Food.Coords(),
+		Hazards:      b.Hazards.Coords(),
+		HazardDamage: b.HazardDamage,
+		Snakes:       make([]bsgf.ViewSnake, len(b.Snakes)),
+	}
+	for i, s := range b.Snakes {
+		frame.Snakes[i] = s.ViewSnake
+		frame.Snakes[i].Body = append([]bsgf.ViewCoord(nil), s.Body...)
+	}
+	return frame
+}
+
+// Open is every cell on the board not covered by a live snake
+func (b *Board) Open() Bitboard {
+	open := Full(b.Width, b.Height)
+	open.AndNot(b.Occupied)
+	return open
+}
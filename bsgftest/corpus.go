@@ -0,0 +1,154 @@
+package bsgftest
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+)
+
+var update = flag.Bool("bsgf.update", false, "rewrite corpus golden files with the current snake's moves")
+
+// Extension of archive files discovered by Run
+const Extension = ".bsgf"
+
+// Golden files sit next to each archive and record the expected move per turn
+const goldenExtension = ".golden.json"
+
+type Options struct {
+	// Snake is the ID or name of the recorded snake whose positions are
+	// replayed. Games without a matching snake are skipped.
+	Snake string
+}
+
+// Run registers a subtest for every game found in dir and a nested subtest
+// for every turn the chosen snake was alive. Moves are compared against the
+// game's golden file when it exists, otherwise against the move recorded in
+// the game. Passing -bsgf.update rewrites the golden files instead.
+func Run(t *testing.T, dir string, snake harness.Snake, opts Options) {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Extension))
+	if err != nil {
+		t.Fatalf("error listing corpus: %s", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no %s archives found in %s", Extension, dir)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		path := path
+		name := strings.TrimSuffix(filepath.Base(path), Extension)
+		t.Run(name, func(t *testing.T) {
+			runGame(t, path, snake, opts)
+		})
+	}
+}
+
+func runGame(t *testing.T, path string, snake harness.Snake, opts Options) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading archive: %s", err)
+	}
+	game, err := bsgf.Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snakeId := findSnakeId(game, opts.Snake)
+	if snakeId == "" {
+		t.Skipf("no snake matching %q in game %s", opts.Snake, game.Game.ID)
+	}
+	goldenPath := strings.TrimSuffix(path, Extension) + goldenExtension
+	golden, err := readGolden(goldenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := make(map[int32]bsgf.Direction, len(game.Frames))
+	for i := range game.Frames {
+		turn := game.Frames[i].Turn
+		expected, ok := golden[turn]
+		if !ok {
+			expected, err = game.MoveAt(turn, snakeId)
+			if err != nil {
+				// not alive on this turn, or the game ended
+				continue
+			}
+		}
+		t.Run(fmt.Sprintf("turn-%d", turn), func(t *testing.T) {
+			state, err := game.ToMove(turn, snakeId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			move, err := snake.Move(context.Background(), state)
+			if err != nil {
+				t.Fatalf("snake returned error: %s", err)
+			}
+			d, err := move.Direction()
+			if err != nil {
+				t.Fatalf("snake returned %s", err)
+			}
+			actual[turn] = d
+			if !*update && d != expected {
+				t.Errorf("expected move %q, got %q", expected, d)
+			}
+		})
+	}
+	if *update {
+		err = writeGolden(goldenPath, actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func findSnakeId(game *bsgf.ViewGame, snake string) string {
+	for _, s := range game.FirstFrame.Snakes {
+		if s.ID == snake || s.Name == snake {
+			return s.ID
+		}
+	}
+	if len(game.Frames) > 0 {
+		for _, s := range game.Frames[0].Snakes {
+			if s.ID == snake || s.Name == snake {
+				return s.ID
+			}
+		}
+	}
+	return ""
+}
+
+func readGolden(path string) (map[int32]bsgf.Direction, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading golden file: %w", err)
+	}
+	var golden map[int32]bsgf.Direction
+	err = json.Unmarshal(data, &golden)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling golden file %s: %w", path, err)
+	}
+	return golden, nil
+}
+
+func writeGolden(path string, moves map[int32]bsgf.Direction) error {
+	data, err := json.MarshalIndent(moves, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling golden file: %w", err)
+	}
+	err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing golden file: %w", err)
+	}
+	return nil
+}
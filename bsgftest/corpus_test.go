@@ -0,0 +1,71 @@
+package bsgftest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/bsgftest"
+)
+
+// replaySnake makes the moves recorded in the corpus games
+type replaySnake map[string]*bsgf.ViewGame
+
+func loadCorpus(t *testing.T, dir string) replaySnake {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+bsgftest.Extension))
+	if err != nil {
+		t.Fatal(err)
+	}
+	games := make(replaySnake)
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		game, err := bsgf.Decode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		games[game.Game.ID] = game
+	}
+	return games
+}
+
+func (s replaySnake) Move(ctx context.Context, state *bsgf.MoveGameState) (*bsgf.MoveBattlesnakeResponse, error) {
+	d, err := s[state.Game.ID].MoveAt(state.Turn, state.You.ID)
+	if err != nil {
+		return nil, err
+	}
+	return d.Response(), nil
+}
+
+// firstSafeSnake takes the first direction that stays on the board, which
+// is what testdata/golden records
+type firstSafeSnake struct{}
+
+func (firstSafeSnake) Move(ctx context.Context, state *bsgf.MoveGameState) (*bsgf.MoveBattlesnakeResponse, error) {
+	for _, d := range bsgf.Directions {
+		dx, dy := d.Delta()
+		c := bsgf.MoveCoord{X: state.You.Head.X + dx, Y: state.You.Head.Y + dy}
+		if c.InBounds(state.Board.Width, state.Board.Height) {
+			return d.Response(), nil
+		}
+	}
+	return bsgf.Directions[0].Response(), nil
+}
+
+func TestRecordedMoves(t *testing.T) {
+	dir := filepath.Join("testdata", "recorded")
+	for _, name := range []string{"Alpha", "Beta"} {
+		t.Run(name, func(t *testing.T) {
+			bsgftest.Run(t, dir, loadCorpus(t, dir), bsgftest.Options{Snake: name})
+		})
+	}
+}
+
+func TestGoldenMoves(t *testing.T) {
+	bsgftest.Run(t, filepath.Join("testdata", "golden"), firstSafeSnake{}, bsgftest.Options{Snake: "Alpha"})
+}
@@ -0,0 +1,147 @@
+package battlesnakegameformat
+
+import (
+	"fmt"
+	"slices"
+)
+
+// StateBuilder assembles a MoveGameState by hand, for testing snake logic
+// without recording a game first. Methods can be chained; problems are
+// reported by Build.
+type StateBuilder struct {
+	state MoveGameState
+	youId string
+	err   error
+}
+
+// NewStateBuilder starts an empty standard game on a width by height board.
+func NewStateBuilder(width, height int32) *StateBuilder {
+	return &StateBuilder{state: MoveGameState{
+		Game: MoveGame{
+			Ruleset: MoveRuleset{
+				Name: string(RulesetStandard),
+				Settings: MoveSettings{
+					FoodSpawnChance:     DefaultFoodSpawnChance,
+					MinimumFood:         DefaultMinimumFood,
+					HazardDamagePerTurn: DefaultHazardDamagePerTurn,
+				},
+			},
+			Timeout: 500,
+		},
+		Board: MoveBoard{
+			Width:   width,
+			Height:  height,
+			Food:    []MoveCoord{},
+			Hazards: []MoveCoord{},
+			Snakes:  []MoveBattlesnake{},
+		},
+	}}
+}
+
+// GameID sets the game ID.
+func (b *StateBuilder) GameID(id string) *StateBuilder {
+	b.state.Game.ID = id
+	return b
+}
+
+// Ruleset sets the ruleset name, keeping the current settings.
+func (b *StateBuilder) Ruleset(name RulesetName) *StateBuilder {
+	b.state.Game.Ruleset.Name = string(name)
+	return b
+}
+
+// Settings replaces the ruleset settings.
+func (b *StateBuilder) Settings(settings MoveSettings) *StateBuilder {
+	b.state.Game.Ruleset.Settings = settings
+	return b
+}
+
+// Timeout sets the move timeout in milliseconds.
+func (b *StateBuilder) Timeout(ms int32) *StateBuilder {
+	b.state.Game.Timeout = ms
+	return b
+}
+
+// Turn sets the turn number.
+func (b *StateBuilder) Turn(turn int32) *StateBuilder {
+	b.state.Turn = turn
+	return b
+}
+
+// Food adds food at each coordinate.
+func (b *StateBuilder) Food(coords ...MoveCoord) *StateBuilder {
+	b.state.Board.Food = append(b.state.Board.Food, coords...)
+	return b
+}
+
+// Hazards adds a hazard at each coordinate. Repeat a coordinate to stack
+// hazards.
+func (b *StateBuilder) Hazards(coords ...MoveCoord) *StateBuilder {
+	b.state.Board.Hazards = append(b.state.Board.Hazards, coords...)
+	return b
+}
+
+// Snake adds an opponent. The body is listed head first.
+func (b *StateBuilder) Snake(id string, health int32, body ...MoveCoord) *StateBuilder {
+	for _, s := range b.state.Board.Snakes {
+		if s.ID == id {
+			b.fail(fmt.Errorf("duplicate snake %s", id))
+			return b
+		}
+	}
+	if len(body) == 0 {
+		b.fail(fmt.Errorf("snake %s has an empty body", id))
+		return b
+	}
+	for _, c := range body {
+		if !c.InBounds(b.state.Board.Width, b.state.Board.Height) {
+			b.fail(fmt.Errorf("snake %s has a segment off the board at (%d,%d)", id, c.X, c.Y))
+			return b
+		}
+	}
+	b.state.Board.Snakes = append(b.state.Board.Snakes, MoveBattlesnake{
+		ID:      id,
+		Name:    id,
+		Health:  health,
+		Body:    slices.Clone(body),
+		Head:    body[0],
+		Length:  int32(len(body)),
+		Latency: "0",
+	})
+	return b
+}
+
+// You adds the snake the request is for.
+func (b *StateBuilder) You(id string, health int32, body ...MoveCoord) *StateBuilder {
+	if b.youId != "" {
+		b.fail(fmt.Errorf("you is already set to %s", b.youId))
+		return b
+	}
+	b.youId = id
+	return b.Snake(id, health, body...)
+}
+
+func (b *StateBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Build returns the assembled state, or the first problem found while
+// building it.
+func (b *StateBuilder) Build() (*MoveGameState, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.state.Board.Width <= 0 || b.state.Board.Height <= 0 {
+		return nil, fmt.Errorf("invalid board size %dx%d", b.state.Board.Width, b.state.Board.Height)
+	}
+	if b.youId == "" {
+		return nil, fmt.Errorf("%w: no snake set with You", ErrSnakeNotFound)
+	}
+	state := b.state.Clone()
+	you, _ := findMoveSnake(state.Board.Snakes, b.youId)
+	state.You = *you
+	state.You.Body = slices.Clone(you.Body)
+	return state, nil
+}
@@ -0,0 +1,409 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Chunked layout - a zip archive with the frames of one game split across
+// several files, so a reader can uncompress only the turns it needs, chunks
+// can be uncompressed in parallel and turns can be appended without
+// compressing the earlier ones again.
+//
+//	chunks.json         ChunkManifest
+//	frames/000000.json  json array of the first ChunkFrames frames
+//	frames/000001.json  and so on
+//	metadata.json       Metadata
+//
+// Every decoder reads both layouts. DecodeLazy only uncompresses a chunk
+// when one of its frames is accessed, and frame index entries name the chunk
+// their offsets are in.
+
+const chunkManifestFile = "chunks.json"
+
+// Version of the chunked layout written by EncodeChunked
+const ChunkedVersion = 1
+
+// DefaultChunkFrames is the number of frames per chunk when none is given
+const DefaultChunkFrames = 100
+
+// ChunkManifest is the game without its frames, and where to find them
+type ChunkManifest struct {
+	Version     int              `json:"version"`
+	ChunkFrames int              `json:"chunkFrames"`
+	Game        ViewGameSettings `json:"game"`
+	LastTurn    int32            `json:"lastTurn"`
+	Events      []ViewEvent      `json:"events,omitempty"`
+	Chunks      []ChunkEntry     `json:"chunks"`
+}
+
+// ChunkEntry is one file of frames, with Size and SHA256 of its json
+type ChunkEntry struct {
+	File      string `json:"file"`
+	FirstTurn int32  `json:"firstTurn"`
+	LastTurn  int32  `json:"lastTurn"`
+	Frames    int    `json:"frames"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+}
+
+// EncodeChunked compresses game into the chunked layout with framesPerChunk
+// frames in each file, DefaultChunkFrames when it's 0. Unknown fields from
+// DecodeKeepUnknown aren't kept.
+func EncodeChunked(game *ViewGame, buf *bytes.Buffer, framesPerChunk int) error {
+	if framesPerChunk <= 0 {
+		framesPerChunk = DefaultChunkFrames
+	}
+	manifest := ChunkManifest{
+		Version:     ChunkedVersion,
+		ChunkFrames: framesPerChunk,
+		Game:        game.Game,
+		LastTurn:    game.LastTurn,
+		Events:      game.Events,
+	}
+	w := zip.NewWriter(buf)
+	err := writeChunks(w, &manifest, game.Frames)
+	if err != nil {
+		return err
+	}
+	return closeChunked(w, &manifest, newMetadata(game).stamp())
+}
+
+// AppendChunked writes archive to buf with frames added as new chunks. The
+// chunks already in archive are copied still compressed. settings replaces
+// the game's settings when it isn't nil, to mark the game complete once the
+// last turns are in.
+func AppendChunked(archive []byte, settings *ViewGameSettings, frames []ViewFrame, buf *bytes.Buffer) error {
+	manifest, files, err := openChunked(archive)
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		manifest.Game = *settings
+	}
+	w := zip.NewWriter(buf)
+	for _, entry := range manifest.Chunks {
+		err = w.Copy(files[entry.File])
+		if err != nil {
+			return fmt.Errorf("error copying %s: %w", entry.File, err)
+		}
+	}
+	err = writeChunks(w, manifest, frames)
+	if err != nil {
+		return err
+	}
+	var last *ViewFrame
+	if len(frames) > 0 {
+		last = &frames[len(frames)-1]
+	} else if n := len(manifest.Chunks); n > 0 {
+		chunk, err := readChunk(files, &manifest.Chunks[n-1])
+		if err != nil {
+			return err
+		}
+		if len(chunk) > 0 {
+			last = &chunk[len(chunk)-1]
+		}
+	}
+	return closeChunked(w, manifest, gameMetadata(&manifest.Game, manifest.LastTurn, last).stamp())
+}
+
+// writeChunks adds frames to w as chunks after the ones in manifest
+func writeChunks(w *zip.Writer, manifest *ChunkManifest, frames []ViewFrame) error {
+	for start := 0; start < len(frames); start += manifest.ChunkFrames {
+		end := start + manifest.ChunkFrames
+		if end > len(frames) {
+			end = len(frames)
+		}
+		contents, err := marshalJSON(frames[start:end])
+		if err != nil {
+			return fmt.Errorf("error marshaling frames to json: %w", err)
+		}
+		sum := sha256.Sum256(contents)
+		entry := ChunkEntry{
+			File:      fmt.Sprintf("frames/%06d.json", len(manifest.Chunks)),
+			FirstTurn: frames[start].Turn,
+			LastTurn:  frames[end-1].Turn,
+			Frames:    end - start,
+			Size:      int64(len(contents)),
+			SHA256:    hex.EncodeToString(sum[:]),
+		}
+		f, err := w.Create(entry.File)
+		if err != nil {
+			return fmt.Errorf("error adding file to zip archive: %w", err)
+		}
+		_, err = f.Write(contents)
+		if err != nil {
+			return fmt.Errorf("error writing contents to zip archive: %w", err)
+		}
+		manifest.Chunks = append(manifest.Chunks, entry)
+		if entry.LastTurn > manifest.LastTurn {
+			manifest.LastTurn = entry.LastTurn
+		}
+	}
+	return nil
+}
+
+func closeChunked(w *zip.Writer, manifest *ChunkManifest, m *Metadata) error {
+	contents, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling chunk manifest to json: %w", err)
+	}
+	f, err := w.Create(chunkManifestFile)
+	if err != nil {
+		return fmt.Errorf("error adding file to zip archive: %w", err)
+	}
+	_, err = f.Write(contents)
+	if err != nil {
+		return fmt.Errorf("error writing contents to zip archive: %w", err)
+	}
+	err = writeMetadata(w, m)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("error closing zip archive: %w", err)
+	}
+	return nil
+}
+
+// IsChunked reports whether data is an archive in the chunked layout
+func IsChunked(data []byte) bool {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	return err == nil && isChunkedZip(r)
+}
+
+func isChunkedZip(r *zip.Reader) bool {
+	for _, f := range r.File {
+		if f.Name == chunkManifestFile {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeChunkManifest reads only the manifest of a chunked archive
+func DecodeChunkManifest(data []byte) (*ChunkManifest, error) {
+	manifest, _, err := openChunked(data)
+	return manifest, err
+}
+
+// DecodeChunked uncompresses a chunked archive, spreading the chunks over
+// opts.Workers goroutines and checking each against the manifest
+func DecodeChunked(data []byte, opts ParallelOptions) (*ViewGame, error) {
+	start := time.Now()
+	game, err := decodeChunked(data, opts)
+	reportDecode(DecodeFormatArchive, start, len(data), game, err)
+	return game, err
+}
+
+func decodeChunked(data []byte, opts ParallelOptions) (*ViewGame, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	return decodeChunkedZip(r, opts)
+}
+
+func decodeChunkedZip(r *zip.Reader, opts ParallelOptions) (*ViewGame, error) {
+	manifest, files, err := chunkManifest(r)
+	if err != nil {
+		return nil, err
+	}
+	game := &ViewGame{Game: manifest.Game, LastTurn: manifest.LastTurn, Events: manifest.Events}
+	opts.Ordered = true
+	decode := func(i int) (*ViewGame, error) {
+		frames, err := readChunk(files, &manifest.Chunks[i])
+		if err != nil {
+			return nil, err
+		}
+		return &ViewGame{Frames: frames}, nil
+	}
+	err = DecodeParallel(len(manifest.Chunks), decode, opts, func(i int, chunk *ViewGame) error {
+		game.Frames = append(game.Frames, chunk.Frames...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(game.Frames) > 0 {
+		game.FirstFrame = game.Frames[0]
+	}
+	Intern(game)
+	return game, nil
+}
+
+// ReadChunkedFrames returns the frames for turns from..to of a chunked
+// archive, only uncompressing the chunks that hold them
+func ReadChunkedFrames(data []byte, from, to int32) ([]ViewFrame, error) {
+	manifest, files, err := openChunked(data)
+	if err != nil {
+		return nil, err
+	}
+	var frames []ViewFrame
+	for i := range manifest.Chunks {
+		entry := &manifest.Chunks[i]
+		if entry.LastTurn < from || entry.FirstTurn > to {
+			continue
+		}
+		chunk, err := readChunk(files, entry)
+		if err != nil {
+			return nil, err
+		}
+		for j := range chunk {
+			if chunk[j].Turn >= from && chunk[j].Turn <= to {
+				internFrame(&chunk[j])
+				frames = append(frames, chunk[j])
+			}
+		}
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("%w: no frames for turns %d-%d", ErrFrameNotFound, from, to)
+	}
+	return frames, nil
+}
+
+// chunkedInfo is DecodeInfo for a chunked archive, uncompressing only the
+// first and last chunks
+func chunkedInfo(r *zip.Reader) (*ArchiveInfo, error) {
+	manifest, files, err := chunkManifest(r)
+	if err != nil {
+		return nil, err
+	}
+	info := ArchiveInfo{Version: FormatVersion, Game: manifest.Game, LastTurn: manifest.LastTurn}
+	info.Metadata, err = readMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+	if info.Metadata == nil {
+		info.Version = 1
+	}
+	for _, f := range r.File {
+		info.Files = append(info.Files, ArchiveFile{
+			Name:             f.Name,
+			CompressedSize:   f.CompressedSize64,
+			UncompressedSize: f.UncompressedSize64,
+		})
+	}
+	for i, entry := range manifest.Chunks {
+		info.FrameCount += entry.Frames
+		info.Sections = append(info.Sections, ArchiveSection{Name: entry.File, Size: entry.Size})
+		if i != 0 && i != len(manifest.Chunks)-1 {
+			continue
+		}
+		frames, err := readChunk(files, &manifest.Chunks[i])
+		if err != nil {
+			return nil, err
+		}
+		if len(frames) == 0 {
+			continue
+		}
+		if i == 0 {
+			info.FirstFrame = frames[0]
+		}
+		info.LastFrame = frames[len(frames)-1]
+	}
+	return &info, nil
+}
+
+func openChunked(data []byte) (*ChunkManifest, map[string]*zip.File, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	return chunkManifest(r)
+}
+
+// chunkManifest reads the manifest of r and checks that its chunks are there
+func chunkManifest(r *zip.Reader) (*ChunkManifest, map[string]*zip.File, error) {
+	files := zipFiles(r)
+	f, ok := files[chunkManifestFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no %s found in archive", ErrCorruptArchive, chunkManifestFile)
+	}
+	contents, err := readZipFile(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest, err := parseChunkManifest(contents, files)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manifest, files, nil
+}
+
+// zipFiles are the files of r by name
+func zipFiles(r *zip.Reader) map[string]*zip.File {
+	files := make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+	return files
+}
+
+func parseChunkManifest(contents []byte, files map[string]*zip.File) (*ChunkManifest, error) {
+	var manifest ChunkManifest
+	err := json.Unmarshal(contents, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling chunk manifest: %w", ErrCorruptArchive, err)
+	}
+	if manifest.Version > ChunkedVersion {
+		return nil, fmt.Errorf("%w: chunked layout version %d is newer than %d", ErrUnsupportedFormat, manifest.Version, ChunkedVersion)
+	}
+	if manifest.ChunkFrames <= 0 {
+		manifest.ChunkFrames = DefaultChunkFrames
+	}
+	for _, entry := range manifest.Chunks {
+		if files[entry.File] == nil {
+			return nil, fmt.Errorf("%w: manifest lists missing file %s", ErrCorruptArchive, entry.File)
+		}
+		if entry.Frames < 0 {
+			return nil, fmt.Errorf("%w: manifest gives %s %d frames", ErrCorruptArchive, entry.File, entry.Frames)
+		}
+	}
+	return &manifest, nil
+}
+
+func readChunk(files map[string]*zip.File, entry *ChunkEntry) ([]ViewFrame, error) {
+	contents, err := readZipFile(files[entry.File])
+	if err != nil {
+		return nil, err
+	}
+	var frames []ViewFrame
+	err = decodeChunk(entry, contents, &frames)
+	if err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
+// checkChunk checks the json of a chunk has the size and checksum of its entry
+func checkChunk(entry *ChunkEntry, contents []byte) error {
+	sum := sha256.Sum256(contents)
+	if int64(len(contents)) != entry.Size || hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return fmt.Errorf("%w: %s doesn't match the manifest", ErrCorruptArchive, entry.File)
+	}
+	return nil
+}
+
+// decodeChunk checks the json of a chunk against its entry and decodes it
+// into frames, reusing the capacity frames already has
+func decodeChunk(entry *ChunkEntry, contents []byte, frames *[]ViewFrame) error {
+	err := checkChunk(entry, contents)
+	if err != nil {
+		return err
+	}
+	err = unmarshalJSON(contents, frames)
+	if err != nil {
+		return fmt.Errorf("%w: error unmarshalling %s: %w", ErrCorruptArchive, entry.File, err)
+	}
+	if len(*frames) != entry.Frames {
+		return fmt.Errorf("%w: %s has %d frames, manifest says %d", ErrCorruptArchive, entry.File, len(*frames), entry.Frames)
+	}
+	return nil
+}
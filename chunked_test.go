@@ -0,0 +1,142 @@
+package battlesnakegameformat_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// rewriteZip copies the archive in data with the file named name passed
+// through edit
+func rewriteZip(t *testing.T, data []byte, name string, edit func([]byte) []byte) []byte {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	found := false
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Name == name {
+			contents = edit(contents)
+			found = true
+		}
+		out, err := w.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.Write(contents)
+	}
+	if !found {
+		t.Fatalf("no %s in archive", name)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestChunkedManifest(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	data := encodeChunked(t, game, 4)
+	if !bsgf.IsChunked(data) || bsgf.IsChunked(encode(t, game)) {
+		t.Fatal("IsChunked doesn't tell the layouts apart")
+	}
+	manifest, err := bsgf.DecodeChunkManifest(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (len(game.Frames) + 3) / 4; len(manifest.Chunks) != want {
+		t.Errorf("expected %d chunks, got %d", want, len(manifest.Chunks))
+	}
+	frames := 0
+	for i, entry := range manifest.Chunks {
+		if entry.FirstTurn != game.Frames[frames].Turn {
+			t.Errorf("chunk %d starts on turn %d, expected %d", i, entry.FirstTurn, game.Frames[frames].Turn)
+		}
+		frames += entry.Frames
+	}
+	if frames != len(game.Frames) || manifest.LastTurn != game.LastTurn {
+		t.Errorf("manifest has %d frames to turn %d, expected %d to turn %d", frames, manifest.LastTurn, len(game.Frames), game.LastTurn)
+	}
+}
+
+func TestAppendChunked(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	half := len(game.Frames) / 2
+	partial := *game
+	partial.Frames = game.Frames[:half]
+	partial.LastTurn = game.Frames[half-1].Turn
+	partial.Game.Status = "running"
+	data := encodeChunked(t, &partial, 3)
+	var buf bytes.Buffer
+	err := bsgf.AppendChunked(data, &game.Game, game.Frames[half:], &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := bsgf.Decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameGame(t, game, got)
+}
+
+func TestReadChunkedFrames(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	data := encodeChunked(t, game, 4)
+	frames, err := bsgf.ReadChunkedFrames(data, 5, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 5 {
+		t.Fatalf("expected turns 5 to 9, got %d frames", len(frames))
+	}
+	for i := range frames {
+		if diffs := bsgf.DiffFrames(&game.Frames[5+i], &frames[i]); len(diffs) > 0 {
+			t.Errorf("turn %d: %v", frames[i].Turn, diffs)
+		}
+	}
+	_, err = bsgf.ReadChunkedFrames(data, game.LastTurn+1, game.LastTurn+5)
+	if !errors.Is(err, bsgf.ErrFrameNotFound) {
+		t.Errorf("expected ErrFrameNotFound past the end, got %v", err)
+	}
+}
+
+func TestChunkedCorruptChunk(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	data := rewriteZip(t, encodeChunked(t, game, 4), "frames/000001.json", func(contents []byte) []byte {
+		return bytes.Replace(contents, []byte(`"Health":`), []byte(`"Health": `), 1)
+	})
+	for _, d := range decoders {
+		t.Run(d.name, func(t *testing.T) {
+			_, err := d.decode(data)
+			if !errors.Is(err, bsgf.ErrCorruptArchive) {
+				t.Errorf("expected ErrCorruptArchive for a chunk that doesn't match the manifest, got %v", err)
+			}
+		})
+	}
+	t.Run("LazyFrame", func(t *testing.T) {
+		lazy, err := bsgf.DecodeLazy(data)
+		if err != nil {
+			t.Fatalf("the first chunk is fine, got %v", err)
+		}
+		_, err = lazy.Frame(4)
+		if !errors.Is(err, bsgf.ErrCorruptArchive) {
+			t.Errorf("expected ErrCorruptArchive, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package battlesnakegameformat
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of game that shares no slices with it.
+func (game *ViewGame) Clone() *ViewGame {
+	c := *game
+	c.Game.Ruleset.Settings = maps.Clone(game.Game.Ruleset.Settings)
+	c.Game.Ruleset.Unset = slices.Clone(game.Game.Ruleset.Unset)
+	c.FirstFrame = *game.FirstFrame.Clone()
+	c.Events = slices.Clone(game.Events)
+	c.Unknown = cloneUnknown(game.Unknown)
+	if game.Frames != nil {
+		c.Frames = make([]ViewFrame, len(game.Frames))
+		for i := range game.Frames {
+			c.Frames[i] = *game.Frames[i].Clone()
+		}
+	}
+	return &c
+}
+
+// Clone returns a deep copy of frame that shares no slices with it.
+func (frame *ViewFrame) Clone() *ViewFrame {
+	c := *frame
+	c.Food = slices.Clone(frame.Food)
+	c.Hazards = slices.Clone(frame.Hazards)
+	c.Snakes = slices.Clone(frame.Snakes)
+	for i := range c.Snakes {
+		c.Snakes[i].Body = slices.Clone(c.Snakes[i].Body)
+	}
+	return &c
+}
+
+// Clone returns a deep copy of state that shares no slices with it.
+func (state *MoveGameState) Clone() *MoveGameState {
+	c := *state
+	c.Game.Ruleset.Settings.Extra = maps.Clone(state.Game.Ruleset.Settings.Extra)
+	c.Board.Food = slices.Clone(state.Board.Food)
+	c.Board.Hazards = slices.Clone(state.Board.Hazards)
+	c.Board.Snakes = slices.Clone(state.Board.Snakes)
+	for i := range c.Board.Snakes {
+		c.Board.Snakes[i].Body = slices.Clone(c.Board.Snakes[i].Body)
+	}
+	c.You.Body = slices.Clone(state.You.Body)
+	return &c
+}
+
+func cloneUnknown(unknown map[string]map[string]json.RawMessage) map[string]map[string]json.RawMessage {
+	if unknown == nil {
+		return nil
+	}
+	c := make(map[string]map[string]json.RawMessage, len(unknown))
+	for path, fields := range unknown {
+		c[path] = maps.Clone(fields)
+	}
+	return c
+}
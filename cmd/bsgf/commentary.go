@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/overlay"
+)
+
+func runCommentary(args []string) error {
+	flags := flag.NewFlagSet("commentary", flag.ExitOnError)
+	live := flags.Bool("live", false, "follow a live game, writing comments as turns arrive")
+	start := flags.String("start", "", "for archives, stamp turn 0 with this RFC 3339 time and later turns -turn-time apart")
+	turnTime := flags.Duration("turn-time", 500*time.Millisecond, "time between turns when stamping archives")
+	spectatorDelay := flags.Duration("spectator-delay", 0, "with -live, write each turn this long after it arrives")
+	out := flags.String("o", "", "json lines file to write (defaults to stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf commentary [flags] game.bsgf|game-id")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one archive or game ID")
+	}
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	if *live {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		var c *overlay.Commentator
+		var writeErr error
+		onFrame, finish := spectate(*spectatorDelay, func(settings *bsgf.ViewGameSettings, frame *bsgf.ViewFrame) {
+			if c == nil {
+				c = overlay.NewCommentator(settings)
+			}
+			if writeErr == nil {
+				writeErr = overlay.WriteComments(bw, c.Frame(frame)...)
+			}
+			if writeErr == nil {
+				writeErr = bw.Flush()
+			}
+		})
+		_, err := newEngineClient().Record(ctx, args[0], onFrame)
+		finish(ctx)
+		if writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+
+	game, err := loadGame(args[0])
+	if err != nil {
+		return err
+	}
+	var clock func(turn int32) *time.Time
+	if *start != "" {
+		at, err := time.Parse(time.RFC3339, *start)
+		if err != nil {
+			return err
+		}
+		clock = overlay.TurnClock(at, *turnTime)
+	}
+	err = overlay.WriteComments(bw, overlay.Commentary(game, clock)...)
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	to := flags.String("to", "bsgf", "format to convert to (bsgf, json, jsonl, rules, proto)")
+	out := flags.String("o", "", "output file, or output directory when converting a directory")
+	inPlace := flags.Bool("in-place", false, "replace each input with its converted file")
+	normalize := flags.Bool("normalize", false, "sort food, hazards and snakes into a canonical order")
+	chunk := flags.Int("chunk", 0, "split bsgf archives into files of this many frames")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf convert [flags] file-or-dir ...")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("no input files given")
+	}
+	target, err := formatByName(*to)
+	if err != nil {
+		return err
+	}
+	// without -to, a single output file is written in the format of its
+	// extension
+	if !flagSet(flags, "to") && *out != "" && len(args) == 1 && !isDir(args[0]) {
+		target, err = formatFor(*out)
+		if err != nil {
+			return err
+		}
+	}
+	if *chunk > 0 {
+		if target.name != "bsgf" {
+			return errors.New("-chunk only applies to -to bsgf")
+		}
+		target = chunkedFormat(*chunk)
+	}
+	if *inPlace && *out != "" {
+		return errors.New("-o and -in-place can't be used together")
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return err
+	}
+	singleFile := len(inputs) == 1 && len(args) == 1 && !isDir(args[0])
+	for _, input := range inputs {
+		var output string
+		switch {
+		case *inPlace || *out == "":
+			output = trimFormatExt(input) + target.ext
+		case singleFile:
+			output = *out
+		default:
+			base := filepath.Base(input)
+			output = filepath.Join(*out, trimFormatExt(base)+target.ext)
+		}
+		if output == input && !*inPlace {
+			return fmt.Errorf("%s is already in %s format, use -in-place to rewrite it", input, target.name)
+		}
+		err = convertFile(input, output, target, *normalize)
+		if err != nil {
+			return err
+		}
+		if *inPlace && output != input {
+			err = os.Remove(input)
+			if err != nil {
+				return fmt.Errorf("error removing %s: %w", input, err)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "%s -> %s\n", input, output)
+	}
+	return nil
+}
+
+func convertFile(input, output string, target *format, normalize bool) error {
+	game, err := readGame(input)
+	if err != nil {
+		return err
+	}
+	if normalize {
+		bsgf.Normalize(game)
+	}
+	return writeGame(output, game, target)
+}
+
+// chunkedFormat writes bsgf archives with frames split across files
+func chunkedFormat(framesPerChunk int) *format {
+	f := format{name: "bsgf", ext: ".bsgf", decode: bsgf.Decode}
+	f.encode = func(game *bsgf.ViewGame, w io.Writer) error {
+		var buf bytes.Buffer
+		err := bsgf.EncodeChunked(game, &buf, framesPerChunk)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(buf.Bytes())
+		return err
+	}
+	return &f
+}
+
+// writeGame encodes game in the target format and writes it to path
+func writeGame(path string, game *bsgf.ViewGame, target *format) error {
+	var buf bytes.Buffer
+	err := target.encode(game, &buf)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// expandInputs replaces directories with the game files they contain
+func expandInputs(paths []string) ([]string, error) {
+	var inputs []string
+	for _, path := range paths {
+		if !isDir(path) {
+			inputs = append(inputs, path)
+			continue
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			if formatByExt(e.Name()) != nil {
+				inputs = append(inputs, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+	return inputs, nil
+}
+
+// flagSet reports whether the flag name was given on the command line
+func flagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place so a failed write never leaves a truncated file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	err = tmp.Chmod(0644)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
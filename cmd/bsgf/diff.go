@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	turns := flags.String("turns", "", "compare two turns of one game, e.g. 10,11")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf diff a.bsgf b.bsgf")
+		fmt.Fprintln(flags.Output(), "       bsgf diff -turns A,B game.bsgf")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	var diffs []bsgf.Difference
+	switch {
+	case *turns != "" && len(args) == 1:
+		game, err := readGame(args[0])
+		if err != nil {
+			return err
+		}
+		a, b, err := parseTurnPair(*turns, len(game.Frames))
+		if err != nil {
+			return err
+		}
+		diffs = bsgf.DiffFrames(&game.Frames[a], &game.Frames[b])
+	case *turns == "" && len(args) == 2:
+		a, err := readGame(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := readGame(args[1])
+		if err != nil {
+			return err
+		}
+		diffs = bsgf.DiffGames(a, b)
+	default:
+		flags.Usage()
+		return errors.New("expected two games, or one game with -turns")
+	}
+	for _, d := range diffs {
+		fmt.Fprintf(os.Stdout, "%s\n  - %s\n  + %s\n", d.Path, d.A, d.B)
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("found %d differences", len(diffs))
+	}
+	return nil
+}
+
+func parseTurnPair(s string, frames int) (int, int, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected two turns separated by a comma, got %q", s)
+	}
+	var turns [2]int
+	for i, p := range parts {
+		turn, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || turn < 0 || turn >= frames {
+			return 0, 0, fmt.Errorf("no turn %q in game", p)
+		}
+		turns[i] = turn
+	}
+	return turns[0], turns[1], nil
+}
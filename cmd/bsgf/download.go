@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jlafayette/battlesnake-game-format-go/engine"
+	"github.com/jlafayette/battlesnake-game-format-go/notify"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+func runDownload(args []string) error {
+	flags := flag.NewFlagSet("download", flag.ExitOnError)
+	out := flags.String("o", ".", "directory to store downloaded games in")
+	idFile := flags.String("f", "", "file with one game ID per line (- for stdin)")
+	concurrency := flags.Int("concurrency", 4, "number of games to download at once")
+	retries := flags.Int("retries", 3, "times to retry a failed request")
+	force := flags.Bool("force", false, "download games that are already in the output directory")
+	strict := flags.Bool("strict", false, "fail on engine responses with unknown or missing fields")
+	webhook := flags.String("webhook", "", "post a json event to this url when the downloads are done")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf download [flags] [game-id ...]")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	ids := args
+	if *idFile != "" {
+		fileIds, err := readIds(*idFile)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, fileIds...)
+	}
+	if len(ids) == 0 {
+		flags.Usage()
+		return errors.New("no game IDs given")
+	}
+	dir, err := store.NewDir(*out)
+	if err != nil {
+		return err
+	}
+	client := newEngineClient()
+	client.Retries = *retries
+	client.Strict = *strict
+	stored, failed := download(context.Background(), client, dir, ids, *concurrency, *force)
+	sendEvent(newNotifier(*webhook), notify.Event{
+		Type:    notify.EventDownloaded,
+		GameIDs: stored,
+		Failed:  failed,
+		Message: fmt.Sprintf("downloaded %d of %d games", len(stored), len(ids)),
+	})
+	if failed > 0 {
+		return fmt.Errorf("%d of %d games failed to download", failed, len(ids))
+	}
+	return nil
+}
+
+// download fetches ids into s, reporting progress on stderr, and returns the
+// games that are now stored and the number that failed
+func download(ctx context.Context, client *engine.Client, s store.Store, ids []string, concurrency int, force bool) ([]string, int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	work := make(chan string)
+	var mu sync.Mutex
+	failed := 0
+	stored := []string{}
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range work {
+				err := downloadOne(ctx, client, s, id, force)
+				mu.Lock()
+				if err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "%s: %s\n", id, err)
+				} else {
+					stored = append(stored, id)
+					fmt.Fprintf(os.Stderr, "%s: ok\n", id)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, id := range ids {
+		work <- id
+	}
+	close(work)
+	wg.Wait()
+	return stored, failed
+}
+
+func downloadOne(ctx context.Context, client *engine.Client, s store.Store, id string, force bool) error {
+	if !force {
+		ok, err := s.Has(id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	game, err := client.Game(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.Put(game)
+}
+
+// readIds reads one game ID per line, ignoring blank lines and # comments
+func readIds(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening ID file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading ID file: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"image/png"
+	"os"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/render"
+)
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	turn := flags.Int("turn", 0, "turn to export")
+	outFormat := flags.String("format", "json", "json for the raw frame, move for the /move payload, png for an image")
+	snake := flags.String("snake", "", "snake name or ID the move payload is for (defaults to the first snake alive)")
+	cellSize := flags.Int("cell", render.DefaultCellSize, "cell size in pixels for png output")
+	out := flags.String("o", "", "file to write (defaults to stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf export [flags] game.bsgf|game-id")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one archive or game ID")
+	}
+	game, err := loadGame(args[0])
+	if err != nil {
+		return err
+	}
+	if *turn < 0 || *turn >= len(game.Frames) {
+		return fmt.Errorf("no turn %d in game with %d frames", *turn, len(game.Frames))
+	}
+	frame := &game.Frames[*turn]
+	var buf bytes.Buffer
+	switch *outFormat {
+	case "json":
+		err = writeIndentedJSON(&buf, frame)
+	case "move":
+		err = exportMove(&buf, game, frame, *snake)
+	case "png":
+		err = png.Encode(&buf, render.Frame(game.Game.Width, game.Game.Height, frame, render.Options{CellSize: *cellSize}))
+	default:
+		return fmt.Errorf("unknown export format %q", *outFormat)
+	}
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return writeFileAtomic(*out, buf.Bytes())
+}
+
+func exportMove(buf *bytes.Buffer, game *bsgf.ViewGame, frame *bsgf.ViewFrame, snake string) error {
+	snakeId, err := lookupSnakeId(frame, snake)
+	if err != nil {
+		return err
+	}
+	state, err := game.ToMove(frame.Turn, snakeId)
+	if err != nil {
+		return err
+	}
+	return writeIndentedJSON(buf, state)
+}
+
+// lookupSnakeId finds a live snake by name or ID, or the first live snake
+// when snake is empty
+func lookupSnakeId(frame *bsgf.ViewFrame, snake string) (string, error) {
+	for _, s := range frame.Snakes {
+		if s.Death.Eliminated() {
+			continue
+		}
+		if snake == "" || s.ID == snake || s.Name == snake {
+			return s.ID, nil
+		}
+	}
+	if snake == "" {
+		return "", fmt.Errorf("no snakes alive on turn %d", frame.Turn)
+	}
+	return "", fmt.Errorf("no live snake %q on turn %d", snake, frame.Turn)
+}
+
+func writeIndentedJSON(buf *bytes.Buffer, v interface{}) error {
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jlafayette/battlesnake-game-format-go/dataset"
+)
+
+func runFeatures(args []string) error {
+	flags := flag.NewFlagSet("features", flag.ExitOnError)
+	list := flags.String("f", "health,length,territory,food_distance", "comma separated features to extract, or list to print the available ones")
+	out := flags.String("o", "", "csv file to write (defaults to stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf features [flags] file-or-dir ...")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if *list == "list" {
+		for _, name := range dataset.FeatureNames() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("no games given")
+	}
+	var names []string
+	for _, name := range strings.Split(*list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	b, err := dataset.NewFeatureBuilder(names...)
+	if err != nil {
+		return err
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	fw := dataset.NewFeatureWriter(b, w)
+	for _, input := range inputs {
+		games, err := readGames(input)
+		if err != nil {
+			return err
+		}
+		for _, game := range games {
+			err = fw.Write(b.Rows(game))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return fw.Flush()
+}
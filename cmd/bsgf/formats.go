@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/rpc"
+	"github.com/jlafayette/battlesnake-game-format-go/rpc/bsgfpb"
+	"google.golang.org/protobuf/proto"
+)
+
+type format struct {
+	name   string
+	ext    string
+	encode func(game *bsgf.ViewGame, w io.Writer) error
+	decode func(data []byte) (*bsgf.ViewGame, error)
+}
+
+var formats = []format{
+	{"bsgf", ".bsgf", encodeArchive, bsgf.Decode},
+	{"json", ".json", encodeJSON, decodeJSON},
+	{"jsonl", ".jsonl", bsgf.EncodeJSONL, bsgf.DecodeJSONL},
+	// output of the official rules cli, which has no extension of its own
+	{"rules", ".rules.jsonl", bsgf.EncodeRulesCLI, bsgf.DecodeRulesCLI},
+	// the rpc package's protobuf message, detected by extension only
+	{"proto", ".bsgf.pb", encodeProto, decodeProto},
+}
+
+// Games from the legacy v0 API can only be read, so they aren't one of the
+// formats to convert to
+var legacyFormat = format{"v0", ".json", nil, bsgf.DecodeLegacyV0}
+
+// Hand-written positions are read only as well
+var positionFormat = format{"position", ".yaml", nil, bsgf.DecodePosition}
+
+func formatByName(name string) (*format, error) {
+	for i := range formats {
+		if formats[i].name == name {
+			return &formats[i], nil
+		}
+	}
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.name
+	}
+	return nil, fmt.Errorf("unknown format %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// formatFor picks the format to write based on the file extension,
+// defaulting to the zip archive
+func formatFor(path string) (*format, error) {
+	if f := formatByExt(path); f != nil {
+		return f, nil
+	}
+	return formatByName("bsgf")
+}
+
+// formatByExt is the format whose extension path ends with, the longest
+// one winning so game.rules.jsonl isn't taken for jsonl
+func formatByExt(path string) *format {
+	var match *format
+	for i := range formats {
+		f := &formats[i]
+		if strings.HasSuffix(path, f.ext) && (match == nil || len(f.ext) > len(match.ext)) {
+			match = f
+		}
+	}
+	return match
+}
+
+// trimFormatExt strips the extension of path, the whole of a format's
+// extension such as .rules.jsonl when it has one
+func trimFormatExt(path string) string {
+	if f := formatByExt(path); f != nil {
+		return strings.TrimSuffix(path, f.ext)
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path))
+}
+
+// sniffFormat detects the format of data, falling back on the file extension
+func sniffFormat(path string, data []byte) (*format, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return formatByName("bsgf")
+	}
+	if isRulesCLI(data) {
+		return formatByName("rules")
+	}
+	if bsgf.IsLegacyV0(data) {
+		return &legacyFormat, nil
+	}
+	if bsgf.IsPosition(data) {
+		return &positionFormat, nil
+	}
+	if f := formatByExt(path); f != nil {
+		return f, nil
+	}
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if bytes.Contains(trimmed, []byte("\n")) && !bytes.Contains(trimmed, []byte(`"Frames"`)) {
+			return formatByName("jsonl")
+		}
+		return formatByName("json")
+	}
+	return nil, fmt.Errorf("%w: unable to detect format of %s", bsgf.ErrUnsupportedFormat, path)
+}
+
+// readGame decodes a game file in any supported format
+func readGame(path string) (*bsgf.ViewGame, error) {
+	games, err := readGames(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(games) != 1 {
+		return nil, fmt.Errorf("%s: expected 1 game, found %d", path, len(games))
+	}
+	return games[0], nil
+}
+
+// readGames decodes every game in a file, which may be a multi-game container
+func readGames(path string) ([]*bsgf.ViewGame, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bsgf.IsMulti(data) {
+		var games []*bsgf.ViewGame
+		err = bsgf.DecodeMultiParallel(data, bsgf.ParallelOptions{Ordered: true}, func(game *bsgf.ViewGame) error {
+			games = append(games, game)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return games, nil
+	}
+	f, err := sniffFormat(path, data)
+	if err != nil {
+		return nil, err
+	}
+	game, err := f.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return []*bsgf.ViewGame{game}, nil
+}
+
+// isRulesCLI is true when the first line of data is a rules cli game line
+func isRulesCLI(data []byte) bool {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	var header struct {
+		ID      *string          `json:"id"`
+		Ruleset *json.RawMessage `json:"ruleset"`
+	}
+	return json.Unmarshal(line, &header) == nil && header.ID != nil && header.Ruleset != nil
+}
+
+func encodeArchive(game *bsgf.ViewGame, w io.Writer) error {
+	var buf bytes.Buffer
+	err := bsgf.Encode(game, &buf)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func encodeJSON(game *bsgf.ViewGame, w io.Writer) error {
+	return json.NewEncoder(w).Encode(game)
+}
+
+func decodeJSON(data []byte) (*bsgf.ViewGame, error) {
+	var game bsgf.ViewGame
+	err := json.Unmarshal(data, &game)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling game: %w", err)
+	}
+	return &game, nil
+}
+
+func encodeProto(game *bsgf.ViewGame, w io.Writer) error {
+	data, err := proto.Marshal(rpc.GameToProto(game))
+	if err != nil {
+		return fmt.Errorf("error marshalling game to protobuf: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func decodeProto(data []byte) (*bsgf.ViewGame, error) {
+	var msg bsgfpb.Game
+	err := proto.Unmarshal(data, &msg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling protobuf game: %w", bsgf.ErrCorruptArchive, err)
+	}
+	return rpc.GameFromProto(&msg)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+)
+
+func TestFormatByExt(t *testing.T) {
+	tests := []struct {
+		path    string
+		format  string
+		trimmed string
+	}{
+		{"g.bsgf", "bsgf", "g"},
+		{"g.json", "json", "g"},
+		{"g.jsonl", "jsonl", "g"},
+		{"dir/g.rules.jsonl", "rules", "dir/g"},
+		{"g.bsgf.pb", "proto", "g"},
+		{"g.txt", "", "g"},
+	}
+	for _, test := range tests {
+		f := formatByExt(test.path)
+		name := ""
+		if f != nil {
+			name = f.name
+		}
+		if name != test.format {
+			t.Errorf("%s: expected format %q, got %q", test.path, test.format, name)
+		}
+		if trimmed := trimFormatExt(test.path); trimmed != test.trimmed {
+			t.Errorf("%s: expected %q trimmed, got %q", test.path, test.trimmed, trimmed)
+		}
+	}
+	f, err := formatFor("g.txt")
+	if err != nil || f.name != "bsgf" {
+		t.Errorf("expected bsgf for an unknown extension, got %v, %v", f, err)
+	}
+}
+
+func TestFormatsRoundTrip(t *testing.T) {
+	game, err := harness.Play(context.Background(), []harness.Player{
+		{Name: "Alpha", Snake: harness.NewRandomSnake(1)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(2)},
+	}, harness.PlayOptions{Seed: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := f.encode(game, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// proto is only known by its extension
+			path := "game"
+			if f.name == "proto" {
+				path += f.ext
+			}
+			sniffed, err := sniffFormat(path, buf.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sniffed.name != f.name {
+				t.Errorf("sniffed as %s", sniffed.name)
+			}
+			got, err := f.decode(buf.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the rules cli drops eliminated snakes, see the root package
+			// for what it keeps
+			if f.name == "rules" {
+				if got.LastTurn != game.LastTurn || got.Winner() != game.Winner() {
+					t.Errorf("expected turn %d won by %q, got turn %d won by %q", game.LastTurn, game.Winner(), got.LastTurn, got.Winner())
+				}
+				return
+			}
+			for _, d := range bsgf.DiffGames(game, got) {
+				t.Error(d)
+			}
+		})
+	}
+}
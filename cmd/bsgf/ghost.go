@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+)
+
+func runGhost(args []string) error {
+	flags := flag.NewFlagSet("ghost", flag.ExitOnError)
+	url := flags.String("url", "", "snake server to ask for the ghost's moves")
+	at := flags.String("at", "", "x,y cell the ghost starts on, stacked three deep")
+	id := flags.String("id", "ghost", "snake ID for the ghost")
+	name := flags.String("name", "Ghost", "name for the ghost")
+	color := flags.String("color", "#888888", "color for the ghost")
+	out := flags.String("o", "", "file to write the game with the ghost to")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf ghost -url http://localhost:8000 -at 1,1 -o ghost.bsgf game.bsgf|game-id")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 || *url == "" || *at == "" || *out == "" {
+		flags.Usage()
+		return errors.New("expected one archive or game ID, -url, -at and -o")
+	}
+	var start bsgf.ViewCoord
+	_, err := fmt.Sscanf(*at, "%d,%d", &start.X, &start.Y)
+	if err != nil {
+		return fmt.Errorf("invalid -at %q, expected x,y", *at)
+	}
+	target, err := formatFor(*out)
+	if err != nil {
+		return err
+	}
+	game, err := loadGame(args[0])
+	if err != nil {
+		return err
+	}
+	ghost := bsgf.ViewSnake{
+		ID:    *id,
+		Name:  *name,
+		URL:   *url,
+		Color: *color,
+		Body:  []bsgf.ViewCoord{start, start, start},
+	}
+	move := harness.GhostMover(context.Background(), &harness.HTTPSnake{URL: *url})
+	withGhost, err := game.InjectGhost(ghost, bsgf.GhostOptions{Move: move})
+	if err != nil {
+		return err
+	}
+	err = writeGame(*out, withGhost, target)
+	if err != nil {
+		return err
+	}
+	last, _ := withGhost.FinalFrame()
+	s := &last.Snakes[len(last.Snakes)-1]
+	fmt.Fprintf(os.Stderr, "%s: %s\n", s.Name, outcome(s))
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+	"github.com/jlafayette/battlesnake-game-format-go/render"
+)
+
+func runHighlights(args []string) error {
+	flags := flag.NewFlagSet("highlights", flag.ExitOnError)
+	count := flags.Int("n", 3, "most clips to extract")
+	before := flags.Int("before", 6, "turns to show before the first moment of a clip")
+	after := flags.Int("after", 3, "turns to show after the last moment of a clip")
+	dir := flags.String("o", ".", "directory to write clips to")
+	withGIF := flags.Bool("gif", false, "also render each clip as an animated gif")
+	themeName := flags.String("theme", "dark", "gif color theme (dark, light)")
+	delay := flags.Duration("delay", 150*time.Millisecond, "time between gif frames")
+	branding := brandingFlags(flags)
+	ext := flags.String("ext", ".bsgf", "file extension, and so format, of the trimmed clips")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf highlights [flags] game.bsgf|game-id")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one archive or game ID")
+	}
+	target, err := formatFor(*ext)
+	if err != nil {
+		return err
+	}
+	theme, err := render.ThemeByName(*themeName)
+	if err != nil {
+		return err
+	}
+	opts := render.Options{Theme: theme}
+	opts.Branding, err = branding()
+	if err != nil {
+		return err
+	}
+	game, err := loadGame(args[0])
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(*dir, 0755)
+	if err != nil {
+		return err
+	}
+	highlights := analysis.Highlights(game, analysis.HighlightOptions{Before: int32(*before), After: int32(*after), Count: *count})
+	if len(highlights) == 0 {
+		fmt.Fprintln(os.Stderr, "nothing exciting happened")
+		return nil
+	}
+	for i, h := range highlights {
+		clip, err := game.Slice(h.From, h.To)
+		if err != nil {
+			return err
+		}
+		base := filepath.Join(*dir, fmt.Sprintf("%s-highlight-%d", game.Game.ID, i+1))
+		err = writeGame(base+*ext, clip, target)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d. turns %d-%d (score %.1f) %s\n", i+1, h.From, h.To, h.Score, base+*ext)
+		for _, m := range h.Moments {
+			fmt.Printf("   turn %d: %s\n", m.Turn, m.Message)
+		}
+		if !*withGIF {
+			continue
+		}
+		anim, err := render.GIF(game, int(h.From), int(h.To), *delay, opts)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		err = gif.EncodeAll(&buf, anim)
+		if err != nil {
+			return err
+		}
+		err = writeFileAtomic(base+".gif", buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func runIndex(args []string) error {
+	flags := flag.NewFlagSet("index", flag.ExitOnError)
+	check := flags.Bool("check", false, "report missing or stale indexes instead of writing them")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf index [flags] game.bsgf ...")
+		fmt.Fprintf(flags.Output(), "writes a frame index next to each archive as game.bsgf%s\n", bsgf.FrameIndexExtension)
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("no archives given")
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return err
+	}
+	stale := 0
+	for _, path := range inputs {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if *check {
+			if !indexCurrent(path, data) {
+				fmt.Printf("%s: index missing or stale\n", path)
+				stale++
+			}
+			continue
+		}
+		ix, err := bsgf.BuildFrameIndex(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		out, err := json.Marshal(ix)
+		if err != nil {
+			return err
+		}
+		err = writeFileAtomic(path+bsgf.FrameIndexExtension, out)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "indexed %d frames of %s\n", len(ix.Frames), path)
+	}
+	if stale > 0 {
+		return fmt.Errorf("%d of %d archives need indexing", stale, len(inputs))
+	}
+	return nil
+}
+
+// indexCurrent is true if path has an index built from data
+func indexCurrent(path string, data []byte) bool {
+	raw, err := ioutil.ReadFile(path + bsgf.FrameIndexExtension)
+	if err != nil {
+		return false
+	}
+	ix, err := bsgf.DecodeFrameIndex(raw)
+	return err == nil && ix.Matches(data)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func runInspect(args []string) error {
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf inspect game.bsgf ...")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("no archives given")
+	}
+	for i, path := range args {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		info, err := bsgf.DecodeInfo(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		printInfo(os.Stdout, path, int64(len(data)), info)
+	}
+	return nil
+}
+
+func printInfo(out io.Writer, path string, size int64, info *bsgf.ArchiveInfo) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	settings := &info.Game
+	ruleset := &settings.Ruleset
+	fmt.Fprintf(w, "File\t%s (%d bytes, format version %d)\n", path, size, info.Version)
+	fmt.Fprintf(w, "Game\t%s\n", settings.ID)
+	fmt.Fprintf(w, "Status\t%s\n", settings.Status)
+	fmt.Fprintf(w, "Ruleset\t%s\n", ruleset.Name)
+	if ruleset.MapAuthor != "" {
+		fmt.Fprintf(w, "Map\t%s by %s\n", ruleset.Map, ruleset.MapAuthor)
+	} else {
+		fmt.Fprintf(w, "Map\t%s\n", ruleset.Map)
+	}
+	fmt.Fprintf(w, "Board\t%dx%d\n", settings.Width, settings.Height)
+	fmt.Fprintf(w, "Timeout\t%dms\n", settings.Timeout)
+	setting := func(key string, v int32) string {
+		if !ruleset.IsSet(key) {
+			return "unset"
+		}
+		return strconv.Itoa(int(v))
+	}
+	fmt.Fprintf(w, "Settings\tfoodSpawnChance=%s minimumFood=%s damagePerTurn=%s\n",
+		setting("foodSpawnChance", ruleset.FoodSpawnChance), setting("minimumFood", ruleset.MinimumFood), setting("damagePerTurn", ruleset.DamagePerTurn))
+	fmt.Fprintf(w, "Turns\t%d (%d frames)\n", info.LastTurn, info.FrameCount)
+	if m := info.Metadata; m != nil {
+		if m.Encoder != "" {
+			fmt.Fprintf(w, "Encoder\t%s\n", m.Encoder)
+		}
+		if m.Created != nil {
+			fmt.Fprintf(w, "Created\t%s\n", m.Created.Format(time.RFC3339))
+		}
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nSnakes")
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, s := range info.LastFrame.Snakes {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", s.ID, s.Name, s.Author, outcome(&s))
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nFiles")
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, f := range info.Files {
+		fmt.Fprintf(w, "  %s\t%d bytes compressed\t%d bytes uncompressed\t\n", f.Name, f.CompressedSize, f.UncompressedSize)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nSections")
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, s := range info.Sections {
+		fmt.Fprintf(w, "  %s\t%d bytes\t\n", s.Name, s.Size)
+	}
+	w.Flush()
+}
+
+func outcome(s *bsgf.ViewSnake) string {
+	if !s.Death.Eliminated() {
+		return fmt.Sprintf("alive at end, length %d", len(s.Body))
+	}
+	if s.Death.EliminatedBy != "" {
+		return fmt.Sprintf("died turn %d, %s by %s", s.Death.Turn, s.Death.Cause, s.Death.EliminatedBy)
+	}
+	return fmt.Sprintf("died turn %d, %s", s.Death.Turn, s.Death.Cause)
+}
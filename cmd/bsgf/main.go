@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jlafayette/battlesnake-game-format-go/engine"
+	"github.com/jlafayette/battlesnake-game-format-go/notify"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"download", "fetch games from the engine into encoded archives", runDownload},
+	{"convert", "convert archives between formats", runConvert},
+	{"inspect", "print settings, snakes and sizes of archives", runInspect},
+	{"index", "write frame indexes next to archives for fast lookups", runIndex},
+	{"stats", "report per-snake statistics over games", runStats},
+	{"play", "step through a game in the terminal", runPlay},
+	{"diff", "compare two games, or two turns of one game", runDiff},
+	{"validate", "check archives for structural and replay problems", runValidate},
+	{"pack", "merge games into a multi-game container", runPack},
+	{"unpack", "split containers back into single-game archives", runUnpack},
+	{"export", "write one turn as json, a /move payload or a png", runExport},
+	{"features", "extract per-frame features of every snake into a csv", runFeatures},
+	{"split", "assign games to train, validation and test splits", runSplit},
+	{"tables", "write frames, snakes and stats of games as parquet tables", runTables},
+	{"search", "find stored games by snake, ruleset, map or death cause", runSearch},
+	{"record", "follow a live game and store it when it ends", runRecord},
+	{"overlay", "write per-turn overlay data for casting a game", runOverlay},
+	{"commentary", "write eliminations, food, hazards and lead changes as events", runCommentary},
+	{"render", "draw games as png, svg or animated gif", runRender},
+	{"serve", "serve a directory of games over the engine api", runServe},
+	{"trim", "cut a range of turns into a smaller clip", runTrim},
+	{"highlights", "find the most exciting turns and cut them into clips", runHighlights},
+	{"ghost", "play a snake server as a ghost through a recorded game", runGhost},
+	{"simulate", "play a seeded game between local snakes and store it", runSimulate},
+	{"tournament", "seed, schedule and record groups and brackets of matches", runTournament},
+	{"pipeline", "download, validate and store games, then report stats", runPipeline},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bsgf %s: %s\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if name != "help" && name != "-h" && name != "--help" {
+		fmt.Fprintf(os.Stderr, "bsgf: unknown command %q\n", name)
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: bsgf <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// parseArgs parses flags that come before or after positional arguments and
+// returns the positional arguments
+func parseArgs(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newNotifier posts events to url, signed with BSGF_WEBHOOK_SECRET when
+// that's set. It returns nil when url is empty.
+func newNotifier(url string) notify.Notifier {
+	if url == "" {
+		return nil
+	}
+	w := notify.NewWebhook(url)
+	w.Secret = os.Getenv("BSGF_WEBHOOK_SECRET")
+	return w
+}
+
+// sendEvent notifies n, only warning when that fails since the work the
+// event is about is already done
+func sendEvent(n notify.Notifier, e notify.Event) {
+	err := notify.Send(context.Background(), n, e)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", err)
+	}
+}
+
+// newEngineClient talks to the public engine unless BSGF_ENGINE_URL points
+// somewhere else, such as a local bsgf serve
+func newEngineClient() *engine.Client {
+	client := engine.NewClient()
+	if url := os.Getenv("BSGF_ENGINE_URL"); url != "" {
+		client.BaseURL = url
+	}
+	return client
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/overlay"
+)
+
+func runOverlay(args []string) error {
+	flags := flag.NewFlagSet("overlay", flag.ExitOnError)
+	live := flags.Bool("live", false, "follow a live game, writing each turn as it arrives")
+	latency := flags.Float64("latency", 0, "warn about snakes slower than this many ms (defaults to 80% of the timeout)")
+	feed := flags.Int("feed", 5, "eliminations to keep in the kill feed")
+	delay := flags.Duration("delay", 0, "wait this long between turns of an archived game, to replay it at casting speed")
+	spectatorDelay := flags.Duration("spectator-delay", 0, "with -live, write each turn this long after it arrives")
+	out := flags.String("o", "", "json lines file to write (defaults to stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf overlay [flags] game.bsgf|game-id")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one archive or game ID")
+	}
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+	opts := overlay.Options{LatencyWarning: *latency, FeedSize: *feed}
+
+	if *live {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		var b *overlay.Builder
+		var writeErr error
+		onFrame, finish := spectate(*spectatorDelay, func(settings *bsgf.ViewGameSettings, frame *bsgf.ViewFrame) {
+			if b == nil {
+				b = overlay.NewBuilder(settings, opts)
+			}
+			if writeErr == nil {
+				writeErr = overlay.Write(bw, b.Frame(frame))
+			}
+			if writeErr == nil {
+				writeErr = bw.Flush()
+			}
+		})
+		_, err := newEngineClient().Record(ctx, args[0], onFrame)
+		finish(ctx)
+		if writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+
+	game, err := loadGame(args[0])
+	if err != nil {
+		return err
+	}
+	b := overlay.NewBuilder(&game.Game, opts)
+	for i := range game.Frames {
+		if i > 0 && *delay > 0 {
+			time.Sleep(*delay)
+		}
+		err = overlay.Write(bw, b.Frame(&game.Frames[i]))
+		if err == nil && *delay > 0 {
+			err = bw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
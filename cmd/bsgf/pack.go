@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+func runPack(args []string) error {
+	flags := flag.NewFlagSet("pack", flag.ExitOnError)
+	out := flags.String("o", "", "container file to write")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf pack -o games.zip file-or-dir ...")
+		fmt.Fprintln(flags.Output(), "inputs can be single games or other containers, which are merged")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if *out == "" || len(args) == 0 {
+		flags.Usage()
+		return errors.New("need an output file and at least one input")
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return err
+	}
+	var games []*bsgf.ViewGame
+	for _, input := range inputs {
+		g, err := readGames(input)
+		if err != nil {
+			return err
+		}
+		games = append(games, g...)
+	}
+	var buf bytes.Buffer
+	err = bsgf.EncodeMulti(games, &buf)
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomic(*out, buf.Bytes())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "packed %d games into %s\n", len(games), *out)
+	return nil
+}
+
+func runUnpack(args []string) error {
+	flags := flag.NewFlagSet("unpack", flag.ExitOnError)
+	out := flags.String("o", ".", "directory to write games to")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf unpack [-o dir] games.zip ...")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("no containers given")
+	}
+	dir, err := store.NewDir(*out)
+	if err != nil {
+		return err
+	}
+	for _, input := range args {
+		data, err := ioutil.ReadFile(input)
+		if err != nil {
+			return err
+		}
+		games, err := bsgf.DecodeMulti(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+		for _, game := range games {
+			err = dir.Put(game)
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "unpacked %d games from %s\n", len(games), input)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+	"github.com/jlafayette/battlesnake-game-format-go/engine"
+	"github.com/jlafayette/battlesnake-game-format-go/notify"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+	"github.com/jlafayette/battlesnake-game-format-go/validate"
+)
+
+// Progress of a pipeline run, saved after every game so an interrupted run
+// can pick up where it left off
+const pipelineStateFile = ".pipeline-state.json"
+
+const (
+	statusStored  = "stored"
+	statusInvalid = "invalid"
+	statusFailed  = "failed"
+)
+
+type pipelineEntry struct {
+	Status   string             `json:"status"`
+	Error    string             `json:"error,omitempty"`
+	Findings []validate.Finding `json:"findings,omitempty"`
+}
+
+type pipelineState struct {
+	path  string
+	mu    sync.Mutex
+	Games map[string]*pipelineEntry `json:"games"`
+}
+
+func loadPipelineState(dir string) (*pipelineState, error) {
+	state := &pipelineState{path: filepath.Join(dir, pipelineStateFile), Games: make(map[string]*pipelineEntry)}
+	data, err := ioutil.ReadFile(state.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", state.path, err)
+	}
+	return state, nil
+}
+
+func (s *pipelineState) set(id string, entry *pipelineEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Games[id] = entry
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+func (s *pipelineState) get(id string) *pipelineEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Games[id]
+}
+
+func runPipeline(args []string) error {
+	flags := flag.NewFlagSet("pipeline", flag.ExitOnError)
+	out := flags.String("o", ".", "directory to store games in")
+	idFile := flags.String("f", "-", "file with one game ID per line (- for stdin)")
+	concurrency := flags.Int("concurrency", 4, "number of games to process at once")
+	retries := flags.Int("retries", 3, "times to retry a failed request")
+	storeInvalid := flags.Bool("store-invalid", false, "store games that fail the replay checks (structurally broken games are never stored)")
+	recheck := flags.Bool("recheck", false, "process games already marked stored or invalid by a previous run")
+	groupBy := flags.String("group-by", "snake", "aggregate stats by snake, author, ruleset or map")
+	outFormat := flags.String("format", "table", "stats output format: table, csv or json")
+	webhook := flags.String("webhook", "", "post json events to this url when games are invalid and when the run is done")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf pipeline [flags] [game-id ...]")
+		fmt.Fprintln(flags.Output(), "downloads, validates and stores games, then prints stats for every stored game")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	key, ok := groupings[*groupBy]
+	if !ok {
+		return fmt.Errorf("unknown grouping %q", *groupBy)
+	}
+	ids := args
+	if len(ids) == 0 || *idFile != "-" {
+		fileIds, err := readIds(*idFile)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, fileIds...)
+	}
+	if len(ids) == 0 {
+		return errors.New("no game IDs given")
+	}
+	dir, err := store.NewDir(*out)
+	if err != nil {
+		return err
+	}
+	state, err := loadPipelineState(*out)
+	if err != nil {
+		return err
+	}
+	client := newEngineClient()
+	client.Retries = *retries
+
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
+	ctx := context.Background()
+	work := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range work {
+				entry := processGame(ctx, client, dir, id, *storeInvalid)
+				fmt.Fprintf(os.Stderr, "%s: %s %s\n", id, entry.Status, entry.Error)
+				err := state.set(id, entry)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "error saving pipeline state: %s\n", err)
+				}
+			}
+		}()
+	}
+	for _, id := range ids {
+		if prev := state.get(id); prev != nil && prev.Status != statusFailed && !*recheck {
+			continue
+		}
+		work <- id
+	}
+	close(work)
+	wg.Wait()
+
+	counts := make(map[string]int)
+	stored := []string{}
+	invalid := []string{}
+	findings := 0
+	for _, id := range ids {
+		entry := state.get(id)
+		if entry == nil {
+			continue
+		}
+		counts[entry.Status]++
+		if entry.Status == statusStored {
+			stored = append(stored, id)
+		}
+		if len(entry.Findings) > 0 {
+			invalid = append(invalid, id)
+			findings += len(entry.Findings)
+		}
+	}
+	notifier := newNotifier(*webhook)
+	if len(invalid) > 0 {
+		sendEvent(notifier, notify.Event{
+			Type:     notify.EventInvalid,
+			GameIDs:  invalid,
+			Findings: findings,
+			Message:  fmt.Sprintf("%d validation findings in %d games", findings, len(invalid)),
+		})
+	}
+	sendEvent(notifier, notify.Event{
+		Type:    notify.EventDownloaded,
+		GameIDs: stored,
+		Failed:  counts[statusFailed],
+		Message: fmt.Sprintf("%d stored, %d invalid, %d failed", counts[statusStored], counts[statusInvalid], counts[statusFailed]),
+	})
+	fmt.Fprintf(os.Stderr, "%d stored, %d invalid, %d failed\n", counts[statusStored], counts[statusInvalid], counts[statusFailed])
+	var stats analysis.StatsAnalyzer
+	_, err = analysis.Run(ctx, dir, stored, analysis.Options{Workers: *concurrency}, &stats)
+	if err != nil {
+		return err
+	}
+	err = writeSummaries(os.Stdout, *outFormat, *groupBy, analysis.Summarize(stats.Stats, key))
+	if err != nil {
+		return err
+	}
+	if counts[statusFailed] > 0 {
+		return fmt.Errorf("%d games failed, run again to retry them", counts[statusFailed])
+	}
+	return nil
+}
+
+func processGame(ctx context.Context, client *engine.Client, s store.Store, id string, storeInvalid bool) *pipelineEntry {
+	game, err := client.Game(ctx, id)
+	if err != nil {
+		return &pipelineEntry{Status: statusFailed, Error: err.Error()}
+	}
+	entry := &pipelineEntry{Status: statusStored, Findings: validate.Game(game)}
+	if len(entry.Findings) > 0 {
+		entry.Status = statusInvalid
+		entry.Error = fmt.Sprintf("%d validation findings", len(entry.Findings))
+		if !storeInvalid {
+			return entry
+		}
+	}
+	err = s.Put(game)
+	if err != nil {
+		return &pipelineEntry{Status: statusFailed, Error: err.Error()}
+	}
+	return entry
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/render"
+)
+
+func runPlay(args []string) error {
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	delay := flags.Duration("delay", 200*time.Millisecond, "time between frames when playing automatically")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf play [flags] game.bsgf|game-id")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one archive or game ID")
+	}
+	game, err := loadGame(args[0])
+	if err != nil {
+		return err
+	}
+	if len(game.Frames) == 0 {
+		return errors.New("game has no frames")
+	}
+	return play(os.Stdin, os.Stdout, game, *delay)
+}
+
+// loadGame reads a game file, or downloads the game when arg isn't a file
+func loadGame(arg string) (*bsgf.ViewGame, error) {
+	if _, err := os.Stat(arg); err == nil {
+		return readGame(arg)
+	}
+	fmt.Fprintf(os.Stderr, "fetching %s from the engine\n", arg)
+	return newEngineClient().Game(context.Background(), arg)
+}
+
+const playHelp = "enter/n next, p previous, <turn> jump, a autoplay, q quit"
+
+func play(in io.Reader, out io.Writer, game *bsgf.ViewGame, delay time.Duration) error {
+	lines := bufio.NewScanner(in)
+	i := 0
+	for {
+		drawFrame(out, game, i)
+		fmt.Fprintf(out, "[%s] > ", playHelp)
+		if !lines.Scan() {
+			fmt.Fprintln(out)
+			return lines.Err()
+		}
+		input := strings.TrimSpace(lines.Text())
+		switch input {
+		case "", "n":
+			if i < len(game.Frames)-1 {
+				i++
+			}
+		case "p":
+			if i > 0 {
+				i--
+			}
+		case "a":
+			for ; i < len(game.Frames)-1; i++ {
+				drawFrame(out, game, i)
+				time.Sleep(delay)
+			}
+		case "q":
+			return nil
+		default:
+			turn, err := strconv.Atoi(input)
+			if err != nil || turn < 0 || turn >= len(game.Frames) {
+				fmt.Fprintf(out, "no turn %q\n", input)
+				time.Sleep(time.Second)
+				continue
+			}
+			i = turn
+		}
+	}
+}
+
+func drawFrame(out io.Writer, game *bsgf.ViewGame, i int) {
+	frame := &game.Frames[i]
+	// clear the terminal and move the cursor home
+	fmt.Fprint(out, "\033[H\033[2J")
+	fmt.Fprintf(out, "%s  %s  turn %d/%d\n\n", game.Game.ID, game.Game.Ruleset.Name, frame.Turn, game.LastTurn)
+	fmt.Fprint(out, render.ASCII(game.Game.Width, game.Game.Height, frame))
+	fmt.Fprintln(out)
+	fmt.Fprint(out, render.Legend(frame))
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+	"github.com/jlafayette/battlesnake-game-format-go/engine"
+	"github.com/jlafayette/battlesnake-game-format-go/notify"
+	"github.com/jlafayette/battlesnake-game-format-go/render"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+func runRecord(args []string) error {
+	flags := flag.NewFlagSet("record", flag.ExitOnError)
+	out := flags.String("o", ".", "directory to store the finished game in")
+	quiet := flags.Bool("quiet", false, "don't draw frames as they arrive")
+	keepPartial := flags.Bool("keep-partial", false, "store the frames recorded so far when interrupted")
+	metricsAddr := flags.String("metrics", "", "serve prometheus metrics at /metrics on this address while recording")
+	webhook := flags.String("webhook", "", "post a json event to this url when the game is stored")
+	spectatorDelay := flags.Duration("spectator-delay", 0, "draw frames this long after they arrive; the game is still stored as soon as it ends")
+	spectatorDir := flags.String("spectator-dir", "", "also store the game here once the spectator delay has passed, for a public bsgf serve")
+	local := flags.Bool("local", false, "the game is played on a local engine, give it a local- ID so it isn't mistaken for an engine download")
+	builds := flags.String("builds", "", "build identifiers of local snakes as name=build,..., added to their URL and author")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf record [flags] game-id")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one game ID")
+	}
+	dir, err := store.NewDir(*out)
+	if err != nil {
+		return err
+	}
+	startMetrics(*metricsAddr)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var onFrame engine.FrameFunc
+	if !*quiet {
+		var live *analysis.GameStats
+		onFrame = func(settings *bsgf.ViewGameSettings, frame *bsgf.ViewFrame) {
+			if live == nil {
+				live = analysis.NewGameStats(settings)
+			}
+			live.Update(frame)
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("%s  %s  turn %d (recording)\n\n", settings.ID, settings.Ruleset.Name, frame.Turn)
+			fmt.Print(render.ASCII(settings.Width, settings.Height, frame))
+			fmt.Println()
+			fmt.Print(render.Legend(frame))
+			fmt.Println()
+			for _, s := range live.Current() {
+				fmt.Printf("%-20s length %-3d max %-3d food %-3d latency %.0fms\n", s.Name, s.FinalLength, s.MaxLength, s.FoodEaten, s.AvgLatency)
+			}
+		}
+	}
+	onFrame, finish := spectate(*spectatorDelay, onFrame)
+	client := newEngineClient()
+	client.Local = *local
+	client.Builds, err = parseBuilds(*builds)
+	if err != nil {
+		return err
+	}
+	if len(client.Builds) > 0 && !client.Local {
+		return errors.New("-builds only applies to -local games")
+	}
+	game, err := client.Record(ctx, args[0], onFrame)
+	if err != nil {
+		if game == nil || !*keepPartial {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "interrupted, storing %d frames\n", len(game.Frames))
+	}
+	err = dir.Put(game)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "recorded %s (%d turns)\n", game.Game.ID, game.LastTurn)
+	sendEvent(newNotifier(*webhook), notify.Event{
+		Type:    notify.EventRecorded,
+		GameIDs: []string{game.Game.ID},
+		Message: fmt.Sprintf("recorded %s (%d turns)", game.Game.ID, game.LastTurn),
+	})
+	finish(ctx)
+	if *spectatorDir != "" && ctx.Err() == nil {
+		public, err := store.NewDir(*spectatorDir)
+		if err != nil {
+			return err
+		}
+		return public.Put(game)
+	}
+	return nil
+}
+
+// parseBuilds reads name=build pairs separated by commas
+func parseBuilds(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	builds := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		name, build, ok := strings.Cut(pair, "=")
+		if !ok || name == "" || build == "" {
+			return nil, fmt.Errorf("invalid build %q, expected name=build", pair)
+		}
+		builds[name] = build
+	}
+	return builds, nil
+}
+
+// spectate puts a delay buffer in front of onFrame when delay is set. The
+// returned finish waits for the buffered frames to be passed on, or drops
+// them when ctx is done.
+func spectate(delay time.Duration, onFrame engine.FrameFunc) (engine.FrameFunc, func(ctx context.Context)) {
+	if delay <= 0 {
+		return onFrame, func(context.Context) {}
+	}
+	if onFrame == nil {
+		onFrame = func(*bsgf.ViewGameSettings, *bsgf.ViewFrame) {}
+	}
+	d := engine.NewDelayBuffer(delay, onFrame)
+	return d.Frame, func(ctx context.Context) {
+		if ctx.Err() != nil {
+			d.Stop()
+			return
+		}
+		fmt.Fprintf(os.Stderr, "waiting for %d delayed frames\n", d.Pending())
+		d.Close()
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"image/gif"
+	"image/png"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jlafayette/battlesnake-game-format-go/render"
+)
+
+func runRender(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	pngOut := flags.String("png", "", "write the -from frame as a png")
+	svgOut := flags.String("svg", "", "write the -from frame as an svg")
+	gifOut := flags.String("gif", "", "write frames -from to -to as an animated gif")
+	mp4Out := flags.String("mp4", "", "not supported yet")
+	from := flags.Int("from", 0, "first turn to render")
+	to := flags.Int("to", -1, "last turn to render (defaults to the end of the game)")
+	overlays := flags.String("overlay", "", "comma separated overlays to draw (voronoi)")
+	themeName := flags.String("theme", "dark", "color theme (dark, light)")
+	cellSize := flags.Int("cell", render.DefaultCellSize, "cell size in pixels")
+	delay := flags.Duration("delay", 150*time.Millisecond, "time between gif frames")
+	substeps := flags.Int("substeps", 1, "images per turn in gifs, interpolating movement between turns")
+	branding := brandingFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf render game.bsgf|game-id [flags]")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one archive or game ID")
+	}
+	if *mp4Out != "" {
+		return errors.New("mp4 output isn't supported, render a gif instead")
+	}
+	if *pngOut == "" && *svgOut == "" && *gifOut == "" {
+		return errors.New("nothing to render, pass -png, -svg or -gif")
+	}
+	theme, err := render.ThemeByName(*themeName)
+	if err != nil {
+		return err
+	}
+	opts := render.Options{CellSize: *cellSize, Theme: theme, Substeps: *substeps}
+	opts.Branding, err = branding()
+	if err != nil {
+		return err
+	}
+	if *overlays != "" {
+		for _, name := range strings.Split(*overlays, ",") {
+			o, err := render.ParseOverlay(strings.TrimSpace(name))
+			if err != nil {
+				return err
+			}
+			opts.Overlays = append(opts.Overlays, o)
+		}
+	}
+	game, err := loadGame(args[0])
+	if err != nil {
+		return err
+	}
+	if *to < 0 || *to >= len(game.Frames) {
+		*to = len(game.Frames) - 1
+	}
+	if *from < 0 || *from > *to {
+		return fmt.Errorf("invalid turn range %d-%d", *from, *to)
+	}
+	frame := &game.Frames[*from]
+	if *pngOut != "" {
+		var buf bytes.Buffer
+		err = png.Encode(&buf, render.Frame(game.Game.Width, game.Game.Height, frame, opts))
+		if err != nil {
+			return err
+		}
+		err = writeFileAtomic(*pngOut, buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	if *svgOut != "" {
+		var buf bytes.Buffer
+		err = render.SVG(&buf, game.Game.Width, game.Game.Height, frame, opts)
+		if err != nil {
+			return err
+		}
+		err = writeFileAtomic(*svgOut, buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	if *gifOut != "" {
+		anim, err := render.GIF(game, *from, *to, *delay, opts)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		err = gif.EncodeAll(&buf, anim)
+		if err != nil {
+			return err
+		}
+		err = writeFileAtomic(*gifOut, buf.Bytes())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "rendered turns %d-%d to %s\n", *from, *to, *gifOut)
+	}
+	return nil
+}
+
+// brandingFlags adds the flags for render.Branding to flags, returning a
+// function to call after parsing. It returns nil when no branding was asked
+// for.
+func brandingFlags(flags *flag.FlagSet) func() (*render.Branding, error) {
+	watermark := flags.String("watermark", "", "png image to draw over the bottom right of the board")
+	opacity := flags.Float64("watermark-opacity", 0, "opacity of the watermark, between 0 and 1 (defaults to opaque)")
+	banner := flags.String("banner", "", "text for a banner across the top")
+	title := flags.String("title", "", "event title for a lower third below the board")
+	lowerThird := flags.Bool("lower-third", false, "list snake names and colors below the board")
+	return func() (*render.Branding, error) {
+		if *watermark == "" && *banner == "" && *title == "" && !*lowerThird {
+			return nil, nil
+		}
+		b := &render.Branding{Opacity: *opacity, Banner: *banner, Title: *title, LowerThird: *lowerThird}
+		if *watermark != "" {
+			f, err := os.Open(*watermark)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			b.Watermark, err = png.Decode(f)
+			if err != nil {
+				return nil, fmt.Errorf("error reading watermark %s: %w", *watermark, err)
+			}
+		}
+		return b, nil
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+func runSearch(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	var q store.Query
+	flags.StringVar(&q.Snake, "snake", "", "snake name or ID")
+	flags.StringVar(&q.Ruleset, "ruleset", "", "ruleset name")
+	flags.StringVar(&q.Map, "map", "", "map name")
+	flags.StringVar(&q.DiedTo, "died-to", "", "death cause, e.g. head-collision")
+	paths := flags.Bool("paths", false, "print archive paths instead of game IDs")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf search [flags] [dir ...]")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	dirs := args
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	for _, path := range dirs {
+		dir := &store.Dir{Path: path}
+		matches, err := dir.Search(q)
+		if err != nil {
+			return err
+		}
+		for _, m := range matches {
+			if *paths {
+				fmt.Println(m.Path)
+			} else {
+				fmt.Println(m.ID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/metrics"
+	"github.com/jlafayette/battlesnake-game-format-go/rpc"
+	"github.com/jlafayette/battlesnake-game-format-go/rpc/bsgfpb"
+	"github.com/jlafayette/battlesnake-game-format-go/server"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+	"google.golang.org/grpc"
+)
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	grpcAddr := flags.String("grpc", "", "also serve the gRPC GameService on this address")
+	metricsAddr := flags.String("metrics", "", "serve prometheus metrics at /metrics on this address")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf serve [flags] dir")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one directory of games")
+	}
+	if !isDir(args[0]) {
+		return fmt.Errorf("%s is not a directory", args[0])
+	}
+	dir := &store.Dir{Path: args[0]}
+	srv := server.New(dir)
+	if m := startMetrics(*metricsAddr); m != nil {
+		srv.OnGameServed = m.GameServed
+	}
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			return err
+		}
+		g := grpc.NewServer()
+		bsgfpb.RegisterGameServiceServer(g, rpc.New(dir))
+		fmt.Fprintf(os.Stderr, "serving gRPC on %s\n", *grpcAddr)
+		go func() {
+			err := g.Serve(lis)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "gRPC server stopped: %s\n", err)
+			}
+		}()
+	}
+	fmt.Fprintf(os.Stderr, "serving %s on http://%s/games\n", args[0], *addr)
+	return http.ListenAndServe(*addr, srv)
+}
+
+// startMetrics serves prometheus metrics on addr in the background and
+// hooks them up to every decoder and client, doing nothing when addr is
+// empty
+func startMetrics(addr string) *metrics.Metrics {
+	if addr == "" {
+		return nil
+	}
+	m := metrics.New()
+	bsgf.SetHooks(m.Hooks())
+	fmt.Fprintf(os.Stderr, "serving metrics on http://%s/metrics\n", addr)
+	go func() {
+		err := m.Serve(addr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "metrics server stopped: %s\n", err)
+		}
+	}()
+	return m
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+func runSimulate(args []string) error {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	out := flags.String("o", ".", "directory to store the game in")
+	seed := flags.Int64("seed", 0, "seed for food, hazards, starting points and move order, the same seed plays the same game again")
+	ruleset := flags.String("ruleset", "standard", "standard, solo, royale, constrictor, wrapped or wrapped_constrictor")
+	width := flags.Int("width", 11, "board width")
+	height := flags.Int("height", 11, "board height")
+	random := flags.Int("random", 0, "add this many snakes that move at random, seeded from -seed")
+	maxTurns := flags.Int("max-turns", 0, "stop the game after this many turns, 0 for no limit")
+	timeout := flags.Duration("timeout", 0, "move timeout, 500ms when 0")
+	builds := flags.String("builds", "", "build identifiers of the snakes as name=build,..., added to their URL and author")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf simulate [flags] [name=url ...]")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	buildOf, err := parseBuilds(*builds)
+	if err != nil {
+		return err
+	}
+	var players []harness.Player
+	for _, arg := range args {
+		name, url, ok := strings.Cut(arg, "=")
+		if !ok || name == "" || url == "" {
+			return fmt.Errorf("invalid snake %q, expected name=url", arg)
+		}
+		players = append(players, harness.Player{Name: name, URL: url, Snake: &harness.HTTPSnake{URL: url}})
+	}
+	for i := 0; i < *random; i++ {
+		players = append(players, harness.Player{
+			Name:  "random-" + strconv.Itoa(i+1),
+			Snake: harness.NewRandomSnake(*seed + int64(i) + 1),
+		})
+	}
+	if len(players) == 0 {
+		flags.Usage()
+		return errors.New("no snakes given, pass name=url or -random n")
+	}
+	for i := range players {
+		players[i].Build = buildOf[players[i].Name]
+	}
+	dir, err := store.NewDir(*out)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	game, err := harness.Play(ctx, players, harness.PlayOptions{
+		Seed:     *seed,
+		Ruleset:  bsgf.ViewRuleset{Name: *ruleset},
+		Width:    int32(*width),
+		Height:   int32(*height),
+		Timeout:  *timeout,
+		MaxTurns: int32(*maxTurns),
+	})
+	if err != nil {
+		return err
+	}
+	err = dir.Put(game)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "played %s with seed %d (%d turns, won by %s)\n", game.Game.ID, *seed, game.LastTurn, winnerName(game))
+	return nil
+}
+
+// winnerName is the name of the game's winner, or "nobody"
+func winnerName(game *bsgf.ViewGame) string {
+	if game.Winner() == "" {
+		return "nobody"
+	}
+	return game.Placements()[0].Name
+}
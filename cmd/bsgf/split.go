@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jlafayette/battlesnake-game-format-go/dataset"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+func runSplit(args []string) error {
+	flags := flag.NewFlagSet("split", flag.ExitOnError)
+	out := flags.String("o", "splits", "directory to write the manifest and split lists into")
+	val := flags.Float64("val", 0.1, "fraction of games in the validation split")
+	test := flags.Float64("test", 0.1, "fraction of games in the test split")
+	seed := flags.String("seed", "", "seed for assigning games to splits")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf split [flags] dir")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one directory of games")
+	}
+	if !isDir(args[0]) {
+		return fmt.Errorf("%s is not a directory", args[0])
+	}
+	m, err := dataset.BuildStoreSplits(&store.Dir{Path: args[0]}, dataset.SplitOptions{Val: *val, Test: *test, Seed: *seed})
+	if err != nil {
+		return err
+	}
+	err = m.Write(*out)
+	if err != nil {
+		return err
+	}
+	for _, split := range dataset.Splits {
+		fmt.Fprintf(os.Stderr, "%-5s %d games\n", split, len(m.Splits[split]))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+)
+
+var groupings = map[string]analysis.GroupBy{
+	"snake":   analysis.BySnake,
+	"author":  analysis.ByAuthor,
+	"ruleset": analysis.ByRuleset,
+	"map":     analysis.ByMap,
+}
+
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	groupBy := flags.String("group-by", "snake", "aggregate by snake, author, ruleset or map, or game for one row per snake per game")
+	snake := flags.String("snake", "", "only include snakes with this name or ID")
+	outFormat := flags.String("format", "table", "output format: table, csv or json")
+	workers := flags.Int("workers", 0, "games to decode at once (defaults to the number of CPUs)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf stats [flags] file-or-dir ...")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("no games given")
+	}
+	key, ok := groupings[*groupBy]
+	if !ok && *groupBy != "game" {
+		return fmt.Errorf("unknown grouping %q", *groupBy)
+	}
+	if *outFormat != "table" && *outFormat != "csv" && *outFormat != "json" {
+		return fmt.Errorf("unknown output format %q", *outFormat)
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return err
+	}
+	var stats []analysis.SnakeStats
+	decode := func(i int) (*bsgf.ViewGame, error) {
+		return readGame(inputs[i])
+	}
+	opts := bsgf.ParallelOptions{Workers: *workers, Ordered: true}
+	err = bsgf.DecodeParallel(len(inputs), decode, opts, func(i int, game *bsgf.ViewGame) error {
+		for _, s := range analysis.Game(game) {
+			if *snake == "" || s.Name == *snake || s.SnakeID == *snake {
+				stats = append(stats, s)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if *groupBy == "game" {
+		return writeSnakeStats(os.Stdout, *outFormat, stats)
+	}
+	return writeSummaries(os.Stdout, *outFormat, *groupBy, analysis.Summarize(stats, key))
+}
+
+func writeSnakeStats(out io.Writer, outFormat string, stats []analysis.SnakeStats) error {
+	if outFormat == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	header := []string{"game", "snake", "name", "author", "won", "turns", "length", "maxLength", "food", "minHealth", "death", "deathTurn", "latencyMs"}
+	rows := make([][]string, 0, len(stats))
+	for _, s := range stats {
+		rows = append(rows, []string{
+			s.GameID,
+			s.SnakeID,
+			s.Name,
+			s.Author,
+			strconv.FormatBool(s.Won),
+			strconv.Itoa(int(s.Turns)),
+			strconv.Itoa(s.FinalLength),
+			strconv.Itoa(s.MaxLength),
+			strconv.Itoa(s.FoodEaten),
+			strconv.Itoa(int(s.MinHealth)),
+			s.DeathCause,
+			strconv.Itoa(int(s.DeathTurn)),
+			strconv.FormatFloat(s.AvgLatency, 'f', 1, 64),
+		})
+	}
+	return writeRows(out, outFormat, header, rows)
+}
+
+func writeSummaries(out io.Writer, outFormat string, groupBy string, summaries []analysis.Summary) error {
+	if outFormat == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summaries)
+	}
+	header := []string{groupBy, "games", "wins", "winRate", "turns", "length", "food", "latencyMs", "deaths"}
+	rows := make([][]string, 0, len(summaries))
+	for _, s := range summaries {
+		causes := make([]string, 0, len(s.Deaths))
+		for cause, n := range s.Deaths {
+			causes = append(causes, fmt.Sprintf("%s=%d", cause, n))
+		}
+		sort.Strings(causes)
+		rows = append(rows, []string{
+			s.Key,
+			strconv.Itoa(s.Games),
+			strconv.Itoa(s.Wins),
+			strconv.FormatFloat(s.WinRate, 'f', 3, 64),
+			strconv.FormatFloat(s.AvgTurns, 'f', 1, 64),
+			strconv.FormatFloat(s.AvgFinalLength, 'f', 1, 64),
+			strconv.FormatFloat(s.AvgFoodEaten, 'f', 1, 64),
+			strconv.FormatFloat(s.AvgLatency, 'f', 1, 64),
+			strings.Join(causes, " "),
+		})
+	}
+	return writeRows(out, outFormat, header, rows)
+}
+
+func writeRows(out io.Writer, outFormat string, header []string, rows [][]string) error {
+	if outFormat == "csv" {
+		w := csv.NewWriter(out)
+		w.Write(header)
+		w.WriteAll(rows)
+		return w.Error()
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
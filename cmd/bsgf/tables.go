@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/columnar"
+)
+
+func runTables(args []string) error {
+	flags := flag.NewFlagSet("tables", flag.ExitOnError)
+	outFormat := flags.String("format", "parquet", "table format: parquet, or arrow for arrow ipc files")
+	out := flags.String("o", ".", "directory to write frames, snakes and stats tables into")
+	features := flags.Bool("features", false, "add territory, reachable and food distance columns to the snakes table")
+	stats := flags.Bool("stats", false, "also write per-game snake stats")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf tables [flags] file-or-dir ...")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("no games given")
+	}
+	ext, ok := map[string]string{"parquet": ".parquet", "arrow": ".arrow"}[*outFormat]
+	if !ok {
+		return fmt.Errorf("unknown table format %q", *outFormat)
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(*out, 0755)
+	if err != nil {
+		return err
+	}
+	names := []string{"frames", "snakes"}
+	if *stats {
+		names = append(names, "stats")
+	}
+	files := make([]*os.File, 3)
+	for i, name := range names {
+		files[i], err = os.Create(filepath.Join(*out, name+ext))
+		if err != nil {
+			return err
+		}
+		defer files[i].Close()
+	}
+	// a nil *os.File isn't a nil io.Writer
+	var statsFile io.Writer
+	if *stats {
+		statsFile = files[2]
+	}
+	opts := columnar.Options{Features: *features}
+	var w interface {
+		Add(game *bsgf.ViewGame) error
+		Close() error
+	}
+	if *outFormat == "arrow" {
+		w, err = columnar.NewArrowWriter(files[0], files[1], statsFile, opts)
+		if err != nil {
+			return err
+		}
+	} else {
+		w = columnar.NewParquetWriter(files[0], files[1], statsFile, opts)
+	}
+	count := 0
+	for _, input := range inputs {
+		games, err := readGames(input)
+		if err != nil {
+			return err
+		}
+		for _, game := range games {
+			err = w.Add(game)
+			if err != nil {
+				return err
+			}
+			count++
+		}
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	for _, f := range files[:len(names)] {
+		err = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d games to %s\n", count, *out)
+	return nil
+}
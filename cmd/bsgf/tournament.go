@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+	"github.com/jlafayette/battlesnake-game-format-go/tournament"
+)
+
+const tournamentUsage = `usage:
+  bsgf tournament new [-name n] [-groups n] [-bracket] [-best-of n] -entries entries.json tournament.json
+  bsgf tournament schedule [-start time] [-slot 10m] [-parallel n] tournament.json
+  bsgf tournament record tournament.json match-id game.bsgf|game-id ...
+  bsgf tournament play [-seed n] [-games dir] [-ruleset name] [-builds name=build,...] tournament.json match-id
+  bsgf tournament show tournament.json
+  bsgf tournament standings [-stage "group A"] tournament.json
+  bsgf tournament advance [-top n] [-best-of n] tournament.json
+  bsgf tournament telemetry [-games dir] [-format table|json] tournament.json`
+
+func runTournament(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, tournamentUsage)
+		return errors.New("expected new, schedule, record, play, show, standings, advance or telemetry")
+	}
+	switch args[0] {
+	case "new":
+		return tournamentNew(args[1:])
+	case "schedule":
+		return tournamentSchedule(args[1:])
+	case "record":
+		return tournamentRecord(args[1:])
+	case "play":
+		return tournamentPlay(args[1:])
+	case "show":
+		return tournamentShow(args[1:])
+	case "standings":
+		return tournamentStandings(args[1:])
+	case "advance":
+		return tournamentAdvance(args[1:])
+	case "telemetry":
+		return tournamentTelemetry(args[1:])
+	}
+	fmt.Fprintln(os.Stderr, tournamentUsage)
+	return fmt.Errorf("unknown tournament command %q", args[0])
+}
+
+func tournamentNew(args []string) error {
+	flags := flag.NewFlagSet("tournament new", flag.ExitOnError)
+	name := flags.String("name", "", "name of the tournament")
+	entriesPath := flags.String("entries", "", "json list of entries, each with id, name, optional url and seed")
+	groups := flags.Int("groups", 0, "round robin groups to seed entries into")
+	bracket := flags.Bool("bracket", false, "seed every entry into a single elimination bracket")
+	bestOf := flags.Int("best-of", 1, "games per match")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf tournament new [flags] tournament.json")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 || *entriesPath == "" {
+		flags.Usage()
+		return errors.New("expected -entries and one tournament file")
+	}
+	data, err := ioutil.ReadFile(*entriesPath)
+	if err != nil {
+		return err
+	}
+	var entries []tournament.Entry
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return fmt.Errorf("error reading entries: %w", err)
+	}
+	t, err := tournament.New(*name, entries)
+	if err != nil {
+		return err
+	}
+	if *groups > 0 {
+		err = t.AddGroups(*groups, *bestOf)
+		if err != nil {
+			return err
+		}
+	}
+	if *bracket {
+		ids := make([]string, len(t.Entries))
+		for i, e := range t.Entries {
+			ids[i] = e.ID
+		}
+		err = t.AddBracket(ids, *bestOf)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d entries, %d matches\n", len(t.Entries), len(t.Matches))
+	return t.Save(args[0])
+}
+
+func tournamentSchedule(args []string) error {
+	flags := flag.NewFlagSet("tournament schedule", flag.ExitOnError)
+	start := flags.String("start", "", "start of the first slot, in RFC 3339 (defaults to now)")
+	slot := flags.Duration("slot", 10*time.Minute, "length of each slot")
+	parallel := flags.Int("parallel", 1, "matches played at the same time")
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one tournament file")
+	}
+	t, err := tournament.Load(args[0])
+	if err != nil {
+		return err
+	}
+	at := time.Now().UTC().Truncate(time.Minute)
+	if *start != "" {
+		at, err = time.Parse(time.RFC3339, *start)
+		if err != nil {
+			return err
+		}
+	}
+	t.Schedule(at, *slot, *parallel)
+	return t.Save(args[0])
+}
+
+func tournamentRecord(args []string) error {
+	if len(args) < 3 {
+		fmt.Fprintln(os.Stderr, tournamentUsage)
+		return errors.New("expected a tournament file, a match ID and games")
+	}
+	t, err := tournament.Load(args[0])
+	if err != nil {
+		return err
+	}
+	for _, arg := range args[2:] {
+		game, err := loadGame(arg)
+		if err != nil {
+			return err
+		}
+		err = t.Record(args[1], game)
+		if err != nil {
+			return err
+		}
+	}
+	m, err := t.Match(args[1])
+	if err != nil {
+		return err
+	}
+	if m.Done() {
+		fmt.Fprintf(os.Stderr, "%s won by %s\n", m.ID, m.Winner)
+	}
+	return t.Save(args[0])
+}
+
+// tournamentPlay plays the games of a match locally until it has a winner.
+// Game n of the match is played with seed+n, so any game can be played
+// again from the tournament file.
+func tournamentPlay(args []string) error {
+	flags := flag.NewFlagSet("tournament play", flag.ExitOnError)
+	seed := flags.Int64("seed", 0, "seed of the match's first game, later games add their number")
+	gamesDir := flags.String("games", ".", "directory to store the games in")
+	ruleset := flags.String("ruleset", "standard", "ruleset to play")
+	builds := flags.String("builds", "", "build identifiers of the entries as name=build,..., added to their URL and author")
+	maxTurns := flags.Int("max-turns", 0, "stop each game after this many turns, 0 for no limit")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf tournament play [flags] tournament.json match-id")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+	if len(args) != 2 {
+		flags.Usage()
+		return errors.New("expected a tournament file and a match ID")
+	}
+	buildOf, err := parseBuilds(*builds)
+	if err != nil {
+		return err
+	}
+	t, err := tournament.Load(args[0])
+	if err != nil {
+		return err
+	}
+	m, err := t.Match(args[1])
+	if err != nil {
+		return err
+	}
+	if !m.Ready() {
+		return fmt.Errorf("%w: %s", tournament.ErrMatchNotReady, m.ID)
+	}
+	var players []harness.Player
+	for _, id := range m.Entries {
+		e, err := t.Entry(id)
+		if err != nil {
+			return err
+		}
+		if e.URL == "" {
+			return fmt.Errorf("entry %s has no url to play", e.ID)
+		}
+		players = append(players, harness.Player{Name: e.Name, URL: e.URL, Build: buildOf[e.Name], Snake: &harness.HTTPSnake{URL: e.URL}})
+	}
+	dir, err := store.NewDir(*gamesDir)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	for m.Ready() {
+		n := int64(len(m.GameIDs))
+		game, err := harness.Play(ctx, players, harness.PlayOptions{
+			Seed:     *seed + n,
+			Ruleset:  bsgf.ViewRuleset{Name: *ruleset},
+			MaxTurns: int32(*maxTurns),
+		})
+		if err != nil {
+			return err
+		}
+		err = dir.Put(game)
+		if err != nil {
+			return err
+		}
+		err = t.Record(m.ID, game)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "played %s with seed %d (won by %s)\n", game.Game.ID, *seed+n, winnerName(game))
+		// save after every game so an interrupted match keeps what was played
+		err = t.Save(args[0])
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%s won by %s\n", m.ID, m.Winner)
+	return nil
+}
+
+func tournamentShow(args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, tournamentUsage)
+		return errors.New("expected one tournament file")
+	}
+	t, err := tournament.Load(args[0])
+	if err != nil {
+		return err
+	}
+	if t.Name != "" {
+		fmt.Println(t.Name)
+	}
+	for _, m := range t.Matches {
+		entries := make([]string, len(m.Entries))
+		for i, id := range m.Entries {
+			entries[i] = id
+			if id == "" {
+				entries[i] = "?"
+			}
+		}
+		status := "ready"
+		switch {
+		case m.Bye:
+			status = "bye"
+		case m.Done():
+			status = "won by " + m.Winner
+		case !m.Ready():
+			status = "waiting"
+		}
+		at := ""
+		if m.Time != nil {
+			at = m.Time.Format(time.RFC3339)
+		}
+		fmt.Printf("%-16s %-10s %-30s %-20s %d/%d games  %s\n", m.ID, m.Stage, strings.Join(entries, " vs "), at, len(m.GameIDs), m.BestOf, status)
+	}
+	return nil
+}
+
+func tournamentStandings(args []string) error {
+	flags := flag.NewFlagSet("tournament standings", flag.ExitOnError)
+	stage := flags.String("stage", "", "only count matches in this stage, like \"group A\" or bracket")
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one tournament file")
+	}
+	t, err := tournament.Load(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-4s %-20s %7s %5s %5s %5s %6s %4s %9s\n", "rank", "entry", "matches", "won", "games", "won", "points", "h2h", "avg place")
+	for _, st := range t.Standings(*stage) {
+		name := st.Participant
+		if e, err := t.Entry(st.Participant); err == nil && e.Name != "" {
+			name = e.Name
+		}
+		fmt.Printf("%-4d %-20s %7d %5d %5d %5d %6d %4d %9.2f\n", st.Rank, name, st.Matches, st.MatchWins, st.Games, st.GameWins, st.Points, st.HeadToHead, st.AvgPlace)
+	}
+	return nil
+}
+
+func tournamentAdvance(args []string) error {
+	flags := flag.NewFlagSet("tournament advance", flag.ExitOnError)
+	top := flags.Int("top", 2, "entries from each group that go on to the bracket")
+	bestOf := flags.Int("best-of", 1, "games per bracket match")
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one tournament file")
+	}
+	t, err := tournament.Load(args[0])
+	if err != nil {
+		return err
+	}
+	err = t.Advance(*top, *bestOf)
+	if err != nil {
+		return err
+	}
+	return t.Save(args[0])
+}
+
+func tournamentTelemetry(args []string) error {
+	flags := flag.NewFlagSet("tournament telemetry", flag.ExitOnError)
+	games := flags.String("games", ".", "directory the tournament's games are stored in")
+	format := flags.String("format", "table", "table, or json with the evidence for each entry")
+	risk := flags.Float64("risk", 0.8, "fraction of the timeout that counts as a timeout risk")
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		flags.Usage()
+		return errors.New("expected one tournament file")
+	}
+	t, err := tournament.Load(args[0])
+	if err != nil {
+		return err
+	}
+	report, err := t.Telemetry(&store.Dir{Path: *games}, tournament.TelemetryOptions{Risk: *risk})
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "json":
+		var buf bytes.Buffer
+		err = writeIndentedJSON(&buf, report)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	case "table":
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	fmt.Printf("%-20s %5s %6s %8s %8s %8s %5s %8s %11s %6s  %s\n", "entry", "games", "turns", "avg ms", "p95 ms", "max ms", "risk", "timeouts", "no response", "outage", "flags")
+	for _, e := range report {
+		var flagged []string
+		if e.Slow {
+			flagged = append(flagged, "slow")
+		}
+		if e.Flaky {
+			flagged = append(flagged, "flaky")
+		}
+		fmt.Printf("%-20s %5d %6d %8.1f %8.1f %8.1f %5d %8d %11d %6d  %s\n", e.Name, e.Games, e.Turns, e.AvgLatency, e.P95Latency, e.MaxLatency, e.RiskTurns, e.Timeouts, e.NoResponses, e.LongestOutage, strings.Join(flagged, ","))
+	}
+	return nil
+}
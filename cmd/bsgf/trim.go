@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func runTrim(args []string) error {
+	flags := flag.NewFlagSet("trim", flag.ExitOnError)
+	from := flags.Int("from", 0, "first turn to keep")
+	to := flags.Int("to", -1, "last turn to keep (defaults to the end of the game)")
+	out := flags.String("o", "", "file to write the clip to")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf trim game.bsgf|game-id --from N --to M -o clip.bsgf")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 || *out == "" {
+		flags.Usage()
+		return errors.New("expected one archive or game ID and an output file")
+	}
+	target, err := formatFor(*out)
+	if err != nil {
+		return err
+	}
+	game, err := loadGame(args[0])
+	if err != nil {
+		return err
+	}
+	if *to < 0 {
+		*to = len(game.Frames) - 1
+	}
+	clip, err := game.Slice(int32(*from), int32(*to))
+	if err != nil {
+		return err
+	}
+	err = writeGame(*out, clip, target)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote turns %d-%d to %s\n", *from, *to, *out)
+	return nil
+}
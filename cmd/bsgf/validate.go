@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/notify"
+	"github.com/jlafayette/battlesnake-game-format-go/validate"
+)
+
+type fileFinding struct {
+	File string `json:"file"`
+	validate.Finding
+}
+
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	findingsPath := flags.String("findings", "", "write all findings to this file as json")
+	structureOnly := flags.Bool("structure-only", false, "skip the replay consistency checks")
+	webhook := flags.String("webhook", "", "post a json event to this url when anything is found")
+	schema := flags.String("schema", "", "check json payloads against a schema instead (move, response, game, frames, frame or a schema kind)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bsgf validate [flags] file-or-dir ...")
+		fmt.Fprintln(flags.Output(), "       bsgf validate -schema kind payload.json|- ...")
+		flags.PrintDefaults()
+	}
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("no games given")
+	}
+	if *schema != "" {
+		kind, ok := bsgf.ParseSchemaKind(*schema)
+		if !ok {
+			return fmt.Errorf("unknown schema %q", *schema)
+		}
+		return validatePayloads(kind, args, *findingsPath, newNotifier(*webhook))
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return err
+	}
+	findings := []fileFinding{}
+	for _, input := range inputs {
+		game, err := readGame(input)
+		if err != nil {
+			// an archive that can't be read is a finding, not a reason to stop
+			findings = append(findings, fileFinding{
+				File:    input,
+				Finding: validate.Finding{Turn: -1, Check: "decode", Message: err.Error()},
+			})
+			fmt.Printf("%s: %s\n", input, err)
+			continue
+		}
+		var results []validate.Finding
+		if *structureOnly {
+			results = validate.Structure(game)
+		} else {
+			results = validate.Game(game)
+		}
+		for _, f := range results {
+			findings = append(findings, fileFinding{File: input, Finding: f})
+			fmt.Printf("%s: %s\n", input, f)
+		}
+	}
+	return reportFindings(findings, len(inputs), *findingsPath, newNotifier(*webhook))
+}
+
+// validatePayloads checks every json value in each file against the schema
+// for kind, so a log with one request per line can be checked in one go.
+// A path of - reads stdin.
+func validatePayloads(kind bsgf.SchemaKind, paths []string, findingsPath string, n notify.Notifier) error {
+	findings := []fileFinding{}
+	for _, path := range paths {
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(path)
+		}
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for i := 0; ; i++ {
+			var payload json.RawMessage
+			err = dec.Decode(&payload)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				findings = append(findings, fileFinding{File: path, Finding: validate.Finding{Turn: -1, Check: "schema", Message: "invalid json: " + err.Error()}})
+				fmt.Printf("%s: invalid json: %s\n", path, err)
+				break
+			}
+			err = bsgf.ValidateJSON(payload, kind)
+			var schemaErr *bsgf.SchemaError
+			if errors.As(err, &schemaErr) {
+				for _, problem := range schemaErr.Problems {
+					findings = append(findings, fileFinding{File: path, Finding: validate.Finding{Turn: -1, Check: "schema", Message: problem}})
+					fmt.Printf("%s: payload %d: %s\n", path, i+1, problem)
+				}
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+	return reportFindings(findings, len(paths), findingsPath, n)
+}
+
+func reportFindings(findings []fileFinding, files int, findingsPath string, n notify.Notifier) error {
+	if findingsPath != "" {
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		err = writeFileAtomic(findingsPath, append(data, '\n'))
+		if err != nil {
+			return err
+		}
+	}
+	if len(findings) > 0 {
+		var ids []string
+		seen := make(map[string]bool)
+		for _, f := range findings {
+			if f.GameID != "" && !seen[f.GameID] {
+				seen[f.GameID] = true
+				ids = append(ids, f.GameID)
+			}
+		}
+		sendEvent(n, notify.Event{
+			Type:     notify.EventInvalid,
+			GameIDs:  ids,
+			Findings: len(findings),
+			Message:  fmt.Sprintf("%d findings in %d files", len(findings), files),
+		})
+		return fmt.Errorf("%d findings in %d files", len(findings), files)
+	}
+	fmt.Fprintf(os.Stderr, "%d files ok\n", files)
+	return nil
+}
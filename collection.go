@@ -0,0 +1,183 @@
+package battlesnakegameformat
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// GameCollection is an in-memory set of games keyed by game ID, safe for
+// any number of concurrent readers alongside Add and Remove. Games are
+// shared with callers rather than copied, so a game mustn't be changed once
+// added; Clone it, edit the copy and Add that instead. Query results are
+// sorted by game ID.
+type GameCollection struct {
+	mu    sync.RWMutex
+	games map[string]*ViewGame
+	// game IDs by snake ID, lower case snake name and ruleset
+	bySnake   map[string]map[string]bool
+	byRuleset map[string]map[string]bool
+}
+
+func NewGameCollection(games ...*ViewGame) *GameCollection {
+	c := &GameCollection{
+		games:     make(map[string]*ViewGame, len(games)),
+		bySnake:   make(map[string]map[string]bool),
+		byRuleset: make(map[string]map[string]bool),
+	}
+	for _, game := range games {
+		c.Add(game)
+	}
+	return c
+}
+
+// Add puts game in the collection, replacing any game with the same ID
+func (c *GameCollection) Add(game *ViewGame) {
+	keys := snakeKeys(game)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := game.Game.ID
+	if old, ok := c.games[id]; ok {
+		c.unindex(old)
+	}
+	c.games[id] = game
+	for _, k := range keys {
+		addKey(c.bySnake, k, id)
+	}
+	addKey(c.byRuleset, string(game.Game.Ruleset.RulesetName()), id)
+}
+
+// Remove takes the game with id out of the collection, reporting whether
+// it was there
+func (c *GameCollection) Remove(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	game, ok := c.games[id]
+	if ok {
+		c.unindex(game)
+		delete(c.games, id)
+	}
+	return ok
+}
+
+func (c *GameCollection) unindex(game *ViewGame) {
+	id := game.Game.ID
+	for _, k := range snakeKeys(game) {
+		removeKey(c.bySnake, k, id)
+	}
+	removeKey(c.byRuleset, string(game.Game.Ruleset.RulesetName()), id)
+}
+
+func (c *GameCollection) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.games)
+}
+
+func (c *GameCollection) ByID(id string) (*ViewGame, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	game, ok := c.games[id]
+	return game, ok
+}
+
+// BySnake returns the games a snake played in, matching its ID or, case
+// insensitively, its name
+func (c *GameCollection) BySnake(snake string) []*ViewGame {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ids := make(map[string]bool)
+	for id := range c.bySnake[snake] {
+		ids[id] = true
+	}
+	for id := range c.bySnake[strings.ToLower(snake)] {
+		ids[id] = true
+	}
+	return c.lookup(ids)
+}
+
+func (c *GameCollection) ByRuleset(name RulesetName) []*ViewGame {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lookup(c.byRuleset[string(name)])
+}
+
+// All returns every game in the collection
+func (c *GameCollection) All() []*ViewGame {
+	return c.Filter(nil)
+}
+
+// Filter returns the games keep returns true for, or every game when keep
+// is nil. keep is called without the collection locked, so it may use the
+// collection itself.
+func (c *GameCollection) Filter(keep func(game *ViewGame) bool) []*ViewGame {
+	c.mu.RLock()
+	games := make([]*ViewGame, 0, len(c.games))
+	for _, game := range c.games {
+		games = append(games, game)
+	}
+	c.mu.RUnlock()
+	sortGames(games)
+	if keep == nil {
+		return games
+	}
+	kept := games[:0]
+	for _, game := range games {
+		if keep(game) {
+			kept = append(kept, game)
+		}
+	}
+	return kept
+}
+
+// lookup returns the games with ids, the read lock being held
+func (c *GameCollection) lookup(ids map[string]bool) []*ViewGame {
+	games := make([]*ViewGame, 0, len(ids))
+	for id := range ids {
+		games = append(games, c.games[id])
+	}
+	sortGames(games)
+	return games
+}
+
+func sortGames(games []*ViewGame) {
+	sort.Slice(games, func(i, j int) bool { return games[i].Game.ID < games[j].Game.ID })
+}
+
+// snakeKeys are the IDs and lower case names of the snakes in the first
+// and final frames of game
+func snakeKeys(game *ViewGame) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(frame *ViewFrame) {
+		for i := range frame.Snakes {
+			for _, k := range []string{frame.Snakes[i].ID, strings.ToLower(frame.Snakes[i].Name)} {
+				if k != "" && !seen[k] {
+					seen[k] = true
+					keys = append(keys, k)
+				}
+			}
+		}
+	}
+	add(&game.FirstFrame)
+	if last, err := game.FinalFrame(); err == nil {
+		add(last)
+	}
+	return keys
+}
+
+func addKey(index map[string]map[string]bool, key, id string) {
+	ids, ok := index[key]
+	if !ok {
+		ids = make(map[string]bool)
+		index[key] = ids
+	}
+	ids[id] = true
+}
+
+func removeKey(index map[string]map[string]bool, key, id string) {
+	delete(index[key], id)
+	if len(index[key]) == 0 {
+		delete(index, key)
+	}
+}
@@ -0,0 +1,267 @@
+package columnar
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+)
+
+var coordType = arrow.StructOf(
+	arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Int32},
+	arrow.Field{Name: "y", Type: arrow.PrimitiveTypes.Int32},
+)
+
+func column(name string, t arrow.DataType) arrow.Field {
+	return arrow.Field{Name: name, Type: t}
+}
+
+func nullable(name string, t arrow.DataType) arrow.Field {
+	return arrow.Field{Name: name, Type: t, Nullable: true}
+}
+
+// Arrow schemas of the tables, with the same columns as the parquet files
+var (
+	FrameSchema = arrow.NewSchema([]arrow.Field{
+		column("game_id", arrow.BinaryTypes.String),
+		column("turn", arrow.PrimitiveTypes.Int32),
+		column("ruleset", arrow.BinaryTypes.String),
+		column("map", arrow.BinaryTypes.String),
+		column("width", arrow.PrimitiveTypes.Int32),
+		column("height", arrow.PrimitiveTypes.Int32),
+		column("snakes_alive", arrow.PrimitiveTypes.Int32),
+		column("hazard_damage", arrow.PrimitiveTypes.Int32),
+		column("food", arrow.ListOf(coordType)),
+		column("hazards", arrow.ListOf(coordType)),
+	}, nil)
+	SnakeSchema = arrow.NewSchema([]arrow.Field{
+		column("game_id", arrow.BinaryTypes.String),
+		column("turn", arrow.PrimitiveTypes.Int32),
+		column("snake_id", arrow.BinaryTypes.String),
+		column("name", arrow.BinaryTypes.String),
+		column("squad", arrow.BinaryTypes.String),
+		column("alive", arrow.FixedWidthTypes.Boolean),
+		column("health", arrow.PrimitiveTypes.Int32),
+		column("length", arrow.PrimitiveTypes.Int32),
+		nullable("head_x", arrow.PrimitiveTypes.Int32),
+		nullable("head_y", arrow.PrimitiveTypes.Int32),
+		column("body", arrow.ListOf(coordType)),
+		nullable("latency", arrow.PrimitiveTypes.Int32),
+		column("shout", arrow.BinaryTypes.String),
+		column("move", arrow.BinaryTypes.String),
+		column("death_cause", arrow.BinaryTypes.String),
+		nullable("death_turn", arrow.PrimitiveTypes.Int32),
+		column("eliminated_by", arrow.BinaryTypes.String),
+		nullable("territory", arrow.PrimitiveTypes.Int32),
+		nullable("reachable", arrow.PrimitiveTypes.Int32),
+		nullable("food_distance", arrow.PrimitiveTypes.Int32),
+	}, nil)
+	StatsSchema = arrow.NewSchema([]arrow.Field{
+		column("game_id", arrow.BinaryTypes.String),
+		column("ruleset", arrow.BinaryTypes.String),
+		column("map", arrow.BinaryTypes.String),
+		column("snake_id", arrow.BinaryTypes.String),
+		column("name", arrow.BinaryTypes.String),
+		column("author", arrow.BinaryTypes.String),
+		column("won", arrow.FixedWidthTypes.Boolean),
+		column("turns", arrow.PrimitiveTypes.Int32),
+		column("final_length", arrow.PrimitiveTypes.Int32),
+		column("max_length", arrow.PrimitiveTypes.Int32),
+		column("food_eaten", arrow.PrimitiveTypes.Int32),
+		column("min_health", arrow.PrimitiveTypes.Int32),
+		column("death_cause", arrow.BinaryTypes.String),
+		nullable("death_turn", arrow.PrimitiveTypes.Int32),
+		column("avg_latency_ms", arrow.PrimitiveTypes.Float64),
+	}, nil)
+)
+
+// record fills in one column per field of b's schema, in order
+type record struct {
+	b *array.RecordBuilder
+	i int
+}
+
+func (r *record) next() array.Builder {
+	f := r.b.Field(r.i)
+	r.i++
+	return f
+}
+
+func (r *record) str(v string)   { r.next().(*array.StringBuilder).Append(v) }
+func (r *record) i32(v int32)    { r.next().(*array.Int32Builder).Append(v) }
+func (r *record) boolean(v bool) { r.next().(*array.BooleanBuilder).Append(v) }
+func (r *record) f64(v float64)  { r.next().(*array.Float64Builder).Append(v) }
+
+func (r *record) i32p(v *int32) {
+	b := r.next().(*array.Int32Builder)
+	if v == nil {
+		b.AppendNull()
+		return
+	}
+	b.Append(*v)
+}
+
+func (r *record) coords(coords []Coord) {
+	b := r.next().(*array.ListBuilder)
+	b.Append(true)
+	s := b.ValueBuilder().(*array.StructBuilder)
+	x := s.FieldBuilder(0).(*array.Int32Builder)
+	y := s.FieldBuilder(1).(*array.Int32Builder)
+	for _, c := range coords {
+		s.Append(true)
+		x.Append(c.X)
+		y.Append(c.Y)
+	}
+}
+
+// FrameRecord builds a record batch of FrameSchema. Release it when done.
+func FrameRecord(mem memory.Allocator, rows []FrameRow) arrow.Record {
+	b := array.NewRecordBuilder(mem, FrameSchema)
+	defer b.Release()
+	for _, row := range rows {
+		r := record{b: b}
+		r.str(row.GameID)
+		r.i32(row.Turn)
+		r.str(row.Ruleset)
+		r.str(row.Map)
+		r.i32(row.Width)
+		r.i32(row.Height)
+		r.i32(row.SnakesAlive)
+		r.i32(row.HazardDamage)
+		r.coords(row.Food)
+		r.coords(row.Hazards)
+	}
+	return b.NewRecord()
+}
+
+// SnakeRecord builds a record batch of SnakeSchema. Release it when done.
+func SnakeRecord(mem memory.Allocator, rows []SnakeRow) arrow.Record {
+	b := array.NewRecordBuilder(mem, SnakeSchema)
+	defer b.Release()
+	for _, row := range rows {
+		r := record{b: b}
+		r.str(row.GameID)
+		r.i32(row.Turn)
+		r.str(row.SnakeID)
+		r.str(row.Name)
+		r.str(row.Squad)
+		r.boolean(row.Alive)
+		r.i32(row.Health)
+		r.i32(row.Length)
+		r.i32p(row.HeadX)
+		r.i32p(row.HeadY)
+		r.coords(row.Body)
+		r.i32p(row.Latency)
+		r.str(row.Shout)
+		r.str(row.Move)
+		r.str(row.DeathCause)
+		r.i32p(row.DeathTurn)
+		r.str(row.EliminatedBy)
+		r.i32p(row.Territory)
+		r.i32p(row.Reachable)
+		r.i32p(row.FoodDistance)
+	}
+	return b.NewRecord()
+}
+
+// StatsRecord builds a record batch of StatsSchema. Release it when done.
+func StatsRecord(mem memory.Allocator, rows []StatsRow) arrow.Record {
+	b := array.NewRecordBuilder(mem, StatsSchema)
+	defer b.Release()
+	for _, row := range rows {
+		r := record{b: b}
+		r.str(row.GameID)
+		r.str(row.Ruleset)
+		r.str(row.Map)
+		r.str(row.SnakeID)
+		r.str(row.Name)
+		r.str(row.Author)
+		r.boolean(row.Won)
+		r.i32(row.Turns)
+		r.i32(row.FinalLength)
+		r.i32(row.MaxLength)
+		r.i32(row.FoodEaten)
+		r.i32(row.MinHealth)
+		r.str(row.DeathCause)
+		r.i32p(row.DeathTurn)
+		r.f64(row.AvgLatency)
+	}
+	return b.NewRecord()
+}
+
+// ArrowWriter writes the same tables as ParquetWriter as Arrow IPC files
+// (Feather v2), with one record batch per game. Readers can memory map the
+// files instead of copying them. Files given as nil are skipped.
+type ArrowWriter struct {
+	opts   Options
+	mem    memory.Allocator
+	frames *ipc.FileWriter
+	snakes *ipc.FileWriter
+	stats  *ipc.FileWriter
+}
+
+func NewArrowWriter(frames, snakes, stats io.Writer, opts Options) (*ArrowWriter, error) {
+	a := &ArrowWriter{opts: opts, mem: memory.DefaultAllocator}
+	var err error
+	for _, f := range []struct {
+		out    io.Writer
+		schema *arrow.Schema
+		w      **ipc.FileWriter
+	}{{frames, FrameSchema, &a.frames}, {snakes, SnakeSchema, &a.snakes}, {stats, StatsSchema, &a.stats}} {
+		if f.out == nil {
+			continue
+		}
+		*f.w, err = ipc.NewFileWriter(f.out, ipc.WithSchema(f.schema), ipc.WithAllocator(a.mem))
+		if err != nil {
+			return nil, fmt.Errorf("error starting arrow file: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// Add writes the record batches for game
+func (a *ArrowWriter) Add(game *bsgf.ViewGame) error {
+	if a.frames != nil {
+		err := a.write(a.frames, FrameRecord(a.mem, FrameRows(game)))
+		if err != nil {
+			return fmt.Errorf("error writing frames of game %s: %w", game.Game.ID, err)
+		}
+	}
+	if a.snakes != nil {
+		err := a.write(a.snakes, SnakeRecord(a.mem, SnakeRows(game, a.opts)))
+		if err != nil {
+			return fmt.Errorf("error writing snakes of game %s: %w", game.Game.ID, err)
+		}
+	}
+	if a.stats != nil {
+		err := a.write(a.stats, StatsRecord(a.mem, StatsRows(analysis.Game(game))))
+		if err != nil {
+			return fmt.Errorf("error writing stats of game %s: %w", game.Game.ID, err)
+		}
+	}
+	return nil
+}
+
+func (a *ArrowWriter) write(w *ipc.FileWriter, rec arrow.Record) error {
+	defer rec.Release()
+	return w.Write(rec)
+}
+
+// Close writes the footers. The files aren't readable until it's called.
+func (a *ArrowWriter) Close() error {
+	for _, w := range []*ipc.FileWriter{a.frames, a.snakes, a.stats} {
+		if w == nil {
+			continue
+		}
+		err := w.Close()
+		if err != nil {
+			return fmt.Errorf("error closing arrow file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package columnar_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/columnar"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+	"github.com/parquet-go/parquet-go"
+)
+
+func play(t *testing.T) *bsgf.ViewGame {
+	t.Helper()
+	players := []harness.Player{
+		{Name: "Alpha", Snake: harness.NewRandomSnake(1)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(2)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: 1, Ruleset: bsgf.ViewRuleset{Name: "royale"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestRows(t *testing.T) {
+	game := play(t)
+	frames := columnar.FrameRows(game)
+	if len(frames) != len(game.Frames) {
+		t.Fatalf("expected %d frame rows, got %d", len(game.Frames), len(frames))
+	}
+	last := frames[len(frames)-1]
+	if last.Turn != game.LastTurn || last.SnakesAlive != 1 || len(last.Hazards) != len(game.Frames[len(game.Frames)-1].Hazards) {
+		t.Errorf("unexpected last frame row %+v", last)
+	}
+	snakes := columnar.SnakeRows(game, columnar.Options{Features: true})
+	if len(snakes) != 2*len(game.Frames) {
+		t.Fatalf("expected %d snake rows, got %d", 2*len(game.Frames), len(snakes))
+	}
+	for _, row := range snakes {
+		move, err := game.MoveAt(row.Turn, row.SnakeID)
+		if err != nil {
+			move = ""
+		}
+		if row.Move != string(move) {
+			t.Errorf("turn %d: %s moved %q, row has %q", row.Turn, row.SnakeID, move, row.Move)
+		}
+		if row.Alive != (row.Territory != nil) || (row.Alive && row.HeadX == nil) {
+			t.Errorf("turn %d: %s alive %v with territory %v and head %v", row.Turn, row.SnakeID, row.Alive, row.Territory, row.HeadX)
+		}
+		if !row.Alive && (row.DeathCause == "" || row.DeathTurn == nil || *row.DeathTurn > row.Turn) {
+			t.Errorf("turn %d: %s is eliminated without a death", row.Turn, row.SnakeID)
+		}
+	}
+}
+
+func TestParquet(t *testing.T) {
+	game := play(t)
+	var frames, snakes, stats bytes.Buffer
+	w := columnar.NewParquetWriter(&frames, &snakes, &stats, columnar.Options{})
+	if err := w.Add(game); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	frameRows, err := parquet.Read[columnar.FrameRow](bytes.NewReader(frames.Bytes()), int64(frames.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	snakeRows, err := parquet.Read[columnar.SnakeRow](bytes.NewReader(snakes.Bytes()), int64(snakes.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statsRows, err := parquet.Read[columnar.StatsRow](bytes.NewReader(stats.Bytes()), int64(stats.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := columnar.SnakeRows(game, columnar.Options{})
+	if len(frameRows) != len(game.Frames) || len(snakeRows) != len(want) || len(statsRows) != 2 {
+		t.Fatalf("read %d frames, %d snakes and %d stats", len(frameRows), len(snakeRows), len(statsRows))
+	}
+	for i := range want {
+		if snakeRows[i].Turn != want[i].Turn || snakeRows[i].Move != want[i].Move || len(snakeRows[i].Body) != len(want[i].Body) {
+			t.Errorf("row %d is %+v, expected %+v", i, snakeRows[i], want[i])
+		}
+	}
+}
+
+func TestArrow(t *testing.T) {
+	game := play(t)
+	var frames, snakes bytes.Buffer
+	w, err := columnar.NewArrowWriter(&frames, &snakes, nil, columnar.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range []*bsgf.ViewGame{game, game} {
+		if err := w.Add(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name string
+		data []byte
+		rows int64
+	}{
+		{"frames", frames.Bytes(), int64(2 * len(game.Frames))},
+		{"snakes", snakes.Bytes(), int64(4 * len(game.Frames))},
+	} {
+		r, err := ipc.NewFileReader(bytes.NewReader(test.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := int64(0)
+		for i := 0; i < r.NumRecords(); i++ {
+			rec, err := r.Record(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows += rec.NumRows()
+		}
+		if r.NumRecords() != 2 || rows != test.rows {
+			t.Errorf("%s: expected 2 batches with %d rows, got %d with %d", test.name, test.rows, r.NumRecords(), rows)
+		}
+		r.Close()
+	}
+}
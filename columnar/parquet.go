@@ -0,0 +1,81 @@
+package columnar
+
+import (
+	"fmt"
+	"io"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+	"github.com/parquet-go/parquet-go"
+)
+
+// ParquetWriter writes games to up to three parquet files: frames with a
+// FrameRow per frame, snakes with a SnakeRow per snake per frame, and stats
+// with a StatsRow per snake per game. Files given as nil are skipped. Rows
+// are written as each game is added, so a corpus doesn't need to fit in
+// memory. Columns are snappy compressed.
+type ParquetWriter struct {
+	opts   Options
+	frames *parquet.GenericWriter[FrameRow]
+	snakes *parquet.GenericWriter[SnakeRow]
+	stats  *parquet.GenericWriter[StatsRow]
+}
+
+func NewParquetWriter(frames, snakes, stats io.Writer, opts Options) *ParquetWriter {
+	p := &ParquetWriter{opts: opts}
+	compression := parquet.Compression(&parquet.Snappy)
+	if frames != nil {
+		p.frames = parquet.NewGenericWriter[FrameRow](frames, compression)
+	}
+	if snakes != nil {
+		p.snakes = parquet.NewGenericWriter[SnakeRow](snakes, compression)
+	}
+	if stats != nil {
+		p.stats = parquet.NewGenericWriter[StatsRow](stats, compression)
+	}
+	return p
+}
+
+// Add writes the rows for game
+func (p *ParquetWriter) Add(game *bsgf.ViewGame) error {
+	if p.frames != nil {
+		_, err := p.frames.Write(FrameRows(game))
+		if err != nil {
+			return fmt.Errorf("error writing frames of game %s: %w", game.Game.ID, err)
+		}
+	}
+	if p.snakes != nil {
+		_, err := p.snakes.Write(SnakeRows(game, p.opts))
+		if err != nil {
+			return fmt.Errorf("error writing snakes of game %s: %w", game.Game.ID, err)
+		}
+	}
+	if p.stats != nil {
+		_, err := p.stats.Write(StatsRows(analysis.Game(game)))
+		if err != nil {
+			return fmt.Errorf("error writing stats of game %s: %w", game.Game.ID, err)
+		}
+	}
+	return nil
+}
+
+// Close writes the footers. The files aren't readable until it's called.
+func (p *ParquetWriter) Close() error {
+	var closers []io.Closer
+	if p.frames != nil {
+		closers = append(closers, p.frames)
+	}
+	if p.snakes != nil {
+		closers = append(closers, p.snakes)
+	}
+	if p.stats != nil {
+		closers = append(closers, p.stats)
+	}
+	for _, c := range closers {
+		err := c.Close()
+		if err != nil {
+			return fmt.Errorf("error closing parquet file: %w", err)
+		}
+	}
+	return nil
+}
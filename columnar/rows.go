@@ -0,0 +1,235 @@
+// Package columnar flattens games into tables, one row per frame and one
+// row per snake per frame, and writes them in columnar formats for loading
+// whole corpora into DuckDB, Spark or Pandas. Column names are snake_case
+// and the same in every format.
+package columnar
+
+import (
+	"strconv"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+	"github.com/jlafayette/battlesnake-game-format-go/bitboard"
+)
+
+// Coord is a board position in a row
+type Coord struct {
+	X int32 `parquet:"x"`
+	Y int32 `parquet:"y"`
+}
+
+// FrameRow is one frame of a game. The key is (game_id, turn).
+type FrameRow struct {
+	GameID  string `parquet:"game_id,dict"`
+	Turn    int32  `parquet:"turn"`
+	Ruleset string `parquet:"ruleset,dict"`
+	Map     string `parquet:"map,dict"`
+	Width   int32  `parquet:"width"`
+	Height  int32  `parquet:"height"`
+	// Snakes not yet eliminated on this turn
+	SnakesAlive  int32   `parquet:"snakes_alive"`
+	HazardDamage int32   `parquet:"hazard_damage"`
+	Food         []Coord `parquet:"food,list"`
+	Hazards      []Coord `parquet:"hazards,list"`
+}
+
+// SnakeRow is one snake on one frame, including snakes that have already
+// been eliminated. The key is (game_id, turn, snake_id).
+type SnakeRow struct {
+	GameID  string  `parquet:"game_id,dict"`
+	Turn    int32   `parquet:"turn"`
+	SnakeID string  `parquet:"snake_id,dict"`
+	Name    string  `parquet:"name,dict"`
+	Squad   string  `parquet:"squad,dict"`
+	Alive   bool    `parquet:"alive"`
+	Health  int32   `parquet:"health"`
+	Length  int32   `parquet:"length"`
+	HeadX   *int32  `parquet:"head_x"`
+	HeadY   *int32  `parquet:"head_y"`
+	Body    []Coord `parquet:"body,list"`
+	// Latency in milliseconds, null when it wasn't recorded or isn't a
+	// number
+	Latency *int32 `parquet:"latency"`
+	Shout   string `parquet:"shout"`
+	// Move made from this turn to the next, empty on the last turn and
+	// after the snake is eliminated
+	Move         string `parquet:"move,dict"`
+	DeathCause   string `parquet:"death_cause,dict"`
+	DeathTurn    *int32 `parquet:"death_turn"`
+	EliminatedBy string `parquet:"eliminated_by,dict"`
+
+	// Features, only filled in with Options.Features and null for
+	// eliminated snakes. Territory is the number of cells the snake reaches
+	// first (see analysis.Territory), Reachable the number of open cells
+	// its head can reach and FoodDistance the Manhattan distance to the
+	// nearest food.
+	Territory    *int32 `parquet:"territory"`
+	Reachable    *int32 `parquet:"reachable"`
+	FoodDistance *int32 `parquet:"food_distance"`
+}
+
+// StatsRow is the analysis.SnakeStats for one snake in one game
+type StatsRow struct {
+	GameID      string  `parquet:"game_id,dict"`
+	Ruleset     string  `parquet:"ruleset,dict"`
+	Map         string  `parquet:"map,dict"`
+	SnakeID     string  `parquet:"snake_id,dict"`
+	Name        string  `parquet:"name,dict"`
+	Author      string  `parquet:"author,dict"`
+	Won         bool    `parquet:"won"`
+	Turns       int32   `parquet:"turns"`
+	FinalLength int32   `parquet:"final_length"`
+	MaxLength   int32   `parquet:"max_length"`
+	FoodEaten   int32   `parquet:"food_eaten"`
+	MinHealth   int32   `parquet:"min_health"`
+	DeathCause  string  `parquet:"death_cause,dict"`
+	DeathTurn   *int32  `parquet:"death_turn"`
+	AvgLatency  float64 `parquet:"avg_latency_ms"`
+}
+
+type Options struct {
+	// Features fills in the derived feature columns of SnakeRow, which
+	// takes a few flood fills per frame
+	Features bool
+}
+
+// FrameRows flattens every frame of game
+func FrameRows(game *bsgf.ViewGame) []FrameRow {
+	rows := make([]FrameRow, len(game.Frames))
+	for i := range game.Frames {
+		frame := &game.Frames[i]
+		row := FrameRow{
+			GameID:       game.Game.ID,
+			Turn:         frame.Turn,
+			Ruleset:      game.Game.Ruleset.Name,
+			Map:          game.Game.Ruleset.Map,
+			Width:        game.Game.Width,
+			Height:       game.Game.Height,
+			HazardDamage: frame.HazardDamage,
+			Food:         coords(frame.Food),
+			Hazards:      coords(frame.Hazards),
+		}
+		for _, s := range frame.Snakes {
+			if !s.Death.Eliminated() {
+				row.SnakesAlive++
+			}
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// SnakeRows flattens every snake on every frame of game
+func SnakeRows(game *bsgf.ViewGame, opts Options) []SnakeRow {
+	var rows []SnakeRow
+	width, height := game.Game.Width, game.Game.Height
+	for i := range game.Frames {
+		frame := &game.Frames[i]
+		var territory []int
+		var board *bitboard.Board
+		if opts.Features {
+			territory = analysis.Territory(width, height, frame)
+			board = bitboard.FromFrame(width, height, frame)
+		}
+		for k := range frame.Snakes {
+			s := &frame.Snakes[k]
+			row := SnakeRow{
+				GameID:       game.Game.ID,
+				Turn:         frame.Turn,
+				SnakeID:      s.ID,
+				Name:         s.Name,
+				Squad:        s.Squad,
+				Alive:        !s.Death.Eliminated(),
+				Health:       s.Health,
+				Length:       int32(len(s.Body)),
+				Body:         coords(s.Body),
+				Shout:        s.Shout,
+				DeathCause:   string(s.Death.Cause),
+				EliminatedBy: s.Death.EliminatedBy,
+			}
+			if len(s.Body) > 0 {
+				row.HeadX = int32p(s.Body[0].X)
+				row.HeadY = int32p(s.Body[0].Y)
+			}
+			if latency, err := strconv.Atoi(s.Latency); err == nil {
+				row.Latency = int32p(int32(latency))
+			}
+			if s.Death.Eliminated() {
+				row.DeathTurn = int32p(s.Death.Turn)
+			} else if i+1 < len(game.Frames) {
+				if move, err := game.MoveAt(frame.Turn, s.ID); err == nil {
+					row.Move = string(move)
+				}
+			}
+			if opts.Features && row.Alive && len(s.Body) > 0 {
+				row.Territory = int32p(int32(territory[k]))
+				row.Reachable = int32p(reachable(board, s.Body[0]))
+				if d, ok := foodDistance(s.Body[0], frame.Food); ok {
+					row.FoodDistance = int32p(d)
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// StatsRows converts stats from analysis.Game or a StatsAnalyzer
+func StatsRows(stats []analysis.SnakeStats) []StatsRow {
+	rows := make([]StatsRow, len(stats))
+	for i, s := range stats {
+		rows[i] = StatsRow{
+			GameID:      s.GameID,
+			Ruleset:     s.Ruleset,
+			Map:         s.Map,
+			SnakeID:     s.SnakeID,
+			Name:        s.Name,
+			Author:      s.Author,
+			Won:         s.Won,
+			Turns:       s.Turns,
+			FinalLength: int32(s.FinalLength),
+			MaxLength:   int32(s.MaxLength),
+			FoodEaten:   int32(s.FoodEaten),
+			MinHealth:   s.MinHealth,
+			DeathCause:  s.DeathCause,
+			AvgLatency:  s.AvgLatency,
+		}
+		if s.DeathCause != "" {
+			rows[i].DeathTurn = int32p(s.DeathTurn)
+		}
+	}
+	return rows
+}
+
+// reachable counts the open cells head can get to, not counting itself
+func reachable(board *bitboard.Board, head bsgf.ViewCoord) int32 {
+	if !head.InBounds(board.Width, board.Height) {
+		return 0
+	}
+	start := bitboard.New(board.Width, board.Height)
+	start.Set(head)
+	filled := bitboard.FloodFill(start, board.Open())
+	return int32(filled.Count() - 1)
+}
+
+func foodDistance(head bsgf.ViewCoord, food []bsgf.ViewCoord) (int32, bool) {
+	best, found := int32(0), false
+	for _, f := range food {
+		if d := head.Manhattan(f); !found || d < best {
+			best, found = d, true
+		}
+	}
+	return best, found
+}
+
+func coords(in []bsgf.ViewCoord) []Coord {
+	out := make([]Coord, len(in))
+	for i, c := range in {
+		out[i] = Coord{X: c.X, Y: c.Y}
+	}
+	return out
+}
+
+func int32p(v int32) *int32 {
+	return &v
+}
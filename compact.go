@@ -0,0 +1,310 @@
+package battlesnakegameformat
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// CompactGame holds a game in a fraction of the memory of a ViewGame. Each
+// frame is packed into bytes, with snake bodies stored as the head followed
+// by one byte per segment giving its direction from the previous one, and
+// strings stored once in a shared table. Frames are expanded back into
+// ViewFrames on request.
+type CompactGame struct {
+	Game     ViewGameSettings
+	LastTurn int32
+	Events   []ViewEvent
+	// Unknown is the game's Unknown from DecodeKeepUnknown, kept as is
+	Unknown map[string]map[string]json.RawMessage
+
+	strings []string
+	first   []byte
+	frames  [][]byte
+}
+
+// Segment codes for packed bodies
+const (
+	segmentSame byte = iota
+	segmentUp
+	segmentDown
+	segmentLeft
+	segmentRight
+	// followed by the segment's coordinates
+	segmentAbsolute
+)
+
+// NewCompactGame packs game. The game isn't referenced afterwards.
+func NewCompactGame(game *ViewGame) *CompactGame {
+	c := &CompactGame{
+		Game:     game.Game,
+		LastTurn: game.LastTurn,
+		Events:   slices.Clone(game.Events),
+		Unknown:  cloneUnknown(game.Unknown),
+		frames:   make([][]byte, len(game.Frames)),
+	}
+	c.Game.Ruleset.Settings = maps.Clone(game.Game.Ruleset.Settings)
+	c.Game.Ruleset.Unset = slices.Clone(game.Game.Ruleset.Unset)
+	index := make(map[string]uint64)
+	c.first = c.pack(&game.FirstFrame, index)
+	for i := range game.Frames {
+		c.frames[i] = c.pack(&game.Frames[i], index)
+	}
+	return c
+}
+
+// FrameCount is the number of frames in the game
+func (c *CompactGame) FrameCount() int {
+	return len(c.frames)
+}
+
+// Size is the number of bytes used by the packed frames
+func (c *CompactGame) Size() int {
+	n := len(c.first)
+	for _, f := range c.frames {
+		n += len(f)
+	}
+	for _, s := range c.strings {
+		n += len(s)
+	}
+	return n
+}
+
+// Frame expands the frame at index i. Each call returns a new copy.
+func (c *CompactGame) Frame(i int) (*ViewFrame, error) {
+	if i < 0 || i >= len(c.frames) {
+		return nil, fmt.Errorf("%w: no frame at index %d", ErrFrameNotFound, i)
+	}
+	return c.unpack(c.frames[i])
+}
+
+// Expand returns the full game
+func (c *CompactGame) Expand() (*ViewGame, error) {
+	game := &ViewGame{
+		Game:     c.Game,
+		LastTurn: c.LastTurn,
+		Events:   slices.Clone(c.Events),
+		Unknown:  cloneUnknown(c.Unknown),
+		Frames:   make([]ViewFrame, 0, len(c.frames)),
+	}
+	game.Game.Ruleset.Settings = maps.Clone(c.Game.Ruleset.Settings)
+	game.Game.Ruleset.Unset = slices.Clone(c.Game.Ruleset.Unset)
+	first, err := c.unpack(c.first)
+	if err != nil {
+		return nil, err
+	}
+	game.FirstFrame = *first
+	for _, packed := range c.frames {
+		frame, err := c.unpack(packed)
+		if err != nil {
+			return nil, err
+		}
+		game.Frames = append(game.Frames, *frame)
+	}
+	return game, nil
+}
+
+func (c *CompactGame) str(s string, index map[string]uint64) uint64 {
+	i, ok := index[s]
+	if !ok {
+		i = uint64(len(c.strings))
+		c.strings = append(c.strings, s)
+		index[s] = i
+	}
+	return i
+}
+
+func (c *CompactGame) pack(frame *ViewFrame, index map[string]uint64) []byte {
+	var b []byte
+	b = binary.AppendVarint(b, int64(frame.Turn))
+	b = binary.AppendVarint(b, int64(frame.HazardDamage))
+	b = appendCoords(b, frame.Food)
+	b = appendCoords(b, frame.Hazards)
+	b = binary.AppendUvarint(b, uint64(len(frame.Snakes)))
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		for _, v := range []string{s.ID, s.Name, s.URL, s.Color, s.HeadType, s.TailType, s.Latency, s.Shout, s.Squad, s.APIVersion, s.Author, string(s.Death.Cause), s.Death.EliminatedBy} {
+			b = binary.AppendUvarint(b, c.str(v, index))
+		}
+		b = binary.AppendVarint(b, int64(s.Health))
+		b = binary.AppendVarint(b, int64(s.Death.Turn))
+		b = binary.AppendUvarint(b, uint64(len(s.Body)))
+		for j, p := range s.Body {
+			if j == 0 {
+				b = appendCoord(b, p)
+				continue
+			}
+			prev := s.Body[j-1]
+			switch {
+			case p == prev:
+				b = append(b, segmentSame)
+			case p.X == prev.X && p.Y == prev.Y+1:
+				b = append(b, segmentUp)
+			case p.X == prev.X && p.Y == prev.Y-1:
+				b = append(b, segmentDown)
+			case p.X == prev.X-1 && p.Y == prev.Y:
+				b = append(b, segmentLeft)
+			case p.X == prev.X+1 && p.Y == prev.Y:
+				b = append(b, segmentRight)
+			default:
+				b = append(b, segmentAbsolute)
+				b = appendCoord(b, p)
+			}
+		}
+	}
+	// trim spare capacity, the point is to save memory
+	return append([]byte(nil), b...)
+}
+
+func appendCoord(b []byte, p ViewCoord) []byte {
+	b = binary.AppendVarint(b, int64(p.X))
+	return binary.AppendVarint(b, int64(p.Y))
+}
+
+func appendCoords(b []byte, coords []ViewCoord) []byte {
+	b = binary.AppendUvarint(b, uint64(len(coords)))
+	for _, p := range coords {
+		b = appendCoord(b, p)
+	}
+	return b
+}
+
+// Reads values from a packed frame, keeping the first error
+type frameReader struct {
+	data []byte
+	err  error
+}
+
+func (r *frameReader) int() int32 {
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return int32(v)
+}
+
+func (r *frameReader) uint() int {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return int(v)
+}
+
+func (r *frameReader) byte() byte {
+	if len(r.data) == 0 {
+		r.fail()
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *frameReader) fail() {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: truncated compact frame", ErrCorruptArchive)
+	}
+	r.data = nil
+}
+
+func (r *frameReader) coord() ViewCoord {
+	x := r.int()
+	return ViewCoord{X: x, Y: r.int()}
+}
+
+// count reads a number of items that each take at least one byte
+func (r *frameReader) count() int {
+	n := r.uint()
+	if n > len(r.data) {
+		r.fail()
+		return 0
+	}
+	return n
+}
+
+func (r *frameReader) coords() []ViewCoord {
+	n := r.count()
+	if r.err != nil {
+		return nil
+	}
+	coords := make([]ViewCoord, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		coords = append(coords, r.coord())
+	}
+	return coords
+}
+
+func (c *CompactGame) unpack(packed []byte) (*ViewFrame, error) {
+	r := frameReader{data: packed}
+	str := func() string {
+		i := r.uint()
+		if i >= len(c.strings) {
+			r.fail()
+			return ""
+		}
+		return c.strings[i]
+	}
+	frame := &ViewFrame{Turn: r.int(), HazardDamage: r.int()}
+	frame.Food = r.coords()
+	frame.Hazards = r.coords()
+	n := r.count()
+	if r.err == nil {
+		frame.Snakes = make([]ViewSnake, 0, n)
+	}
+	for i := 0; i < n && r.err == nil; i++ {
+		s := ViewSnake{
+			ID:         str(),
+			Name:       str(),
+			URL:        str(),
+			Color:      str(),
+			HeadType:   str(),
+			TailType:   str(),
+			Latency:    str(),
+			Shout:      str(),
+			Squad:      str(),
+			APIVersion: str(),
+			Author:     str(),
+		}
+		s.Death.Cause = DeathCause(str())
+		s.Death.EliminatedBy = str()
+		s.Health = r.int()
+		s.Death.Turn = r.int()
+		length := r.count()
+		s.Body = make([]ViewCoord, 0, length)
+		for j := 0; j < length && r.err == nil; j++ {
+			if j == 0 {
+				s.Body = append(s.Body, r.coord())
+				continue
+			}
+			p := s.Body[j-1]
+			switch r.byte() {
+			case segmentSame:
+			case segmentUp:
+				p.Y++
+			case segmentDown:
+				p.Y--
+			case segmentLeft:
+				p.X--
+			case segmentRight:
+				p.X++
+			case segmentAbsolute:
+				p = r.coord()
+			default:
+				r.fail()
+			}
+			s.Body = append(s.Body, p)
+		}
+		frame.Snakes = append(frame.Snakes, s)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return frame, nil
+}
@@ -0,0 +1,41 @@
+package battlesnakegameformat_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func TestCompactRoundTrip(t *testing.T) {
+	for _, name := range []bsgf.RulesetName{bsgf.RulesetStandard, bsgf.RulesetRoyale, bsgf.RulesetWrapped, bsgf.RulesetConstrictor} {
+		t.Run(string(name), func(t *testing.T) {
+			game := testGame(t, name)
+			game.Unknown = map[string]map[string]json.RawMessage{"Frames[2].Snakes[0]": {"Extra": json.RawMessage(`"kept"`)}}
+			game.Game.Ruleset.Unset = []string{"minimumFood"}
+			c := bsgf.NewCompactGame(game)
+			if c.FrameCount() != len(game.Frames) {
+				t.Fatalf("expected %d frames, got %d", len(game.Frames), c.FrameCount())
+			}
+			got, err := c.Expand()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertSameGame(t, game, got)
+			if !reflect.DeepEqual(got.Unknown, game.Unknown) {
+				t.Errorf("expected Unknown %v, got %v", game.Unknown, got.Unknown)
+			}
+			if !reflect.DeepEqual(got.Game.Ruleset.Unset, game.Game.Ruleset.Unset) {
+				t.Errorf("expected Ruleset.Unset %v, got %v", game.Game.Ruleset.Unset, got.Game.Ruleset.Unset)
+			}
+			frame, err := c.Frame(len(game.Frames) - 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diffs := bsgf.DiffFrames(&game.Frames[len(game.Frames)-1], frame); len(diffs) > 0 {
+				t.Errorf("last frame: %v", diffs)
+			}
+		})
+	}
+}
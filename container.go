@@ -0,0 +1,191 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Multi-game container - a zip archive with a manifest and one json file per
+// game, used for distributing many games as a single file
+
+const manifestFile = "manifest.json"
+
+// Version of the container layout written by EncodeMulti
+const ContainerVersion = 1
+
+type Manifest struct {
+	Version int             `json:"version"`
+	Games   []ManifestEntry `json:"games"`
+}
+
+type ManifestEntry struct {
+	ID       string `json:"id"`
+	File     string `json:"file"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+	Ruleset  string `json:"ruleset"`
+	LastTurn int32  `json:"lastTurn"`
+}
+
+// Compress many games into a single container (stored in buf)
+func EncodeMulti(games []*ViewGame, buf *bytes.Buffer) error {
+	manifest := Manifest{Version: ContainerVersion}
+	seen := make(map[string]bool, len(games))
+	w := zip.NewWriter(buf)
+	for _, game := range games {
+		if seen[game.Game.ID] {
+			return fmt.Errorf("duplicate game ID %s in container", game.Game.ID)
+		}
+		seen[game.Game.ID] = true
+		contents, err := marshalGame(game)
+		if err != nil {
+			return fmt.Errorf("error marshaling ViewGame to json: %w", err)
+		}
+		sum := sha256.Sum256(contents)
+		entry := ManifestEntry{
+			ID:       game.Game.ID,
+			File:     "games/" + game.Game.ID + ".json",
+			Size:     int64(len(contents)),
+			SHA256:   hex.EncodeToString(sum[:]),
+			Ruleset:  game.Game.Ruleset.Name,
+			LastTurn: game.LastTurn,
+		}
+		f, err := w.Create(entry.File)
+		if err != nil {
+			return fmt.Errorf("error adding file to zip archive: %w", err)
+		}
+		_, err = f.Write(contents)
+		if err != nil {
+			return fmt.Errorf("error writing contents to zip archive: %w", err)
+		}
+		manifest.Games = append(manifest.Games, entry)
+	}
+	contents, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling manifest to json: %w", err)
+	}
+	f, err := w.Create(manifestFile)
+	if err != nil {
+		return fmt.Errorf("error adding file to zip archive: %w", err)
+	}
+	_, err = f.Write(contents)
+	if err != nil {
+		return fmt.Errorf("error writing contents to zip archive: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("error closing zip archive: %w", err)
+	}
+	return nil
+}
+
+// Uncompress every game in a container, verifying sizes and checksums
+// against the manifest
+func DecodeMulti(data []byte) ([]*ViewGame, error) {
+	r, manifest, err := openContainer(data)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+	games := make([]*ViewGame, 0, len(manifest.Games))
+	for i := range manifest.Games {
+		game, err := decodeEntry(files, &manifest.Games[i])
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// Read only the manifest of a container
+func DecodeManifest(data []byte) (*Manifest, error) {
+	_, manifest, err := openContainer(data)
+	return manifest, err
+}
+
+// IsMulti reports whether data is a multi-game container
+func IsMulti(data []byte) bool {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+	for _, f := range r.File {
+		if f.Name == manifestFile {
+			return true
+		}
+	}
+	return false
+}
+
+func openContainer(data []byte) (*zip.Reader, *Manifest, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	for _, f := range r.File {
+		if f.Name != manifestFile {
+			continue
+		}
+		contents, err := readZipFile(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		var manifest Manifest
+		err = json.Unmarshal(contents, &manifest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: error unmarshalling manifest: %w", ErrCorruptArchive, err)
+		}
+		if manifest.Version > ContainerVersion {
+			return nil, nil, fmt.Errorf("%w: container version %d is newer than %d", ErrUnsupportedFormat, manifest.Version, ContainerVersion)
+		}
+		return r, &manifest, nil
+	}
+	return nil, nil, fmt.Errorf("%w: no %s found in container", ErrCorruptArchive, manifestFile)
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening zip archive file: %w", ErrCorruptArchive, err)
+	}
+	defer rc.Close()
+	contents, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading %s: %w", ErrCorruptArchive, f.Name, err)
+	}
+	return contents, nil
+}
+
+// readZipFileInto appends the contents of f to buf
+func readZipFileInto(f *zip.File, buf *bytes.Buffer) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: error opening zip archive file: %w", ErrCorruptArchive, err)
+	}
+	defer rc.Close()
+	_, err = buf.ReadFrom(rc)
+	if err != nil {
+		return fmt.Errorf("%w: error reading %s: %w", ErrCorruptArchive, f.Name, err)
+	}
+	return nil
+}
+
+func verifyEntry(entry *ManifestEntry, contents []byte) error {
+	if int64(len(contents)) != entry.Size {
+		return fmt.Errorf("%w: game %s is %d bytes, manifest says %d", ErrCorruptArchive, entry.ID, len(contents), entry.Size)
+	}
+	sum := sha256.Sum256(contents)
+	if hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return fmt.Errorf("%w: checksum mismatch for game %s", ErrCorruptArchive, entry.ID)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package battlesnakegameformat_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func TestMultiRoundTrip(t *testing.T) {
+	games := []*bsgf.ViewGame{testGame(t, bsgf.RulesetStandard), testGame(t, bsgf.RulesetRoyale)}
+	var buf bytes.Buffer
+	err := bsgf.EncodeMulti(games, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if !bsgf.IsMulti(data) || bsgf.IsMulti(encode(t, games[0])) {
+		t.Fatal("IsMulti doesn't tell containers from archives")
+	}
+	manifest, err := bsgf.DecodeManifest(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Version != bsgf.ContainerVersion || len(manifest.Games) != 2 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+	for i, entry := range manifest.Games {
+		if entry.ID != games[i].Game.ID || entry.Ruleset != games[i].Game.Ruleset.Name || entry.LastTurn != games[i].LastTurn {
+			t.Errorf("entry %d is %+v", i, entry)
+		}
+	}
+	got, err := bsgf.DecodeMulti(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(games) {
+		t.Fatalf("expected %d games, got %d", len(games), len(got))
+	}
+	for i := range games {
+		assertSameGame(t, games[i], got[i])
+	}
+}
+
+func TestMultiDuplicateID(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	var buf bytes.Buffer
+	err := bsgf.EncodeMulti([]*bsgf.ViewGame{game, game}, &buf)
+	if err == nil {
+		t.Fatal("expected an error for a duplicate game ID")
+	}
+}
+
+func TestMultiChecksum(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	var buf bytes.Buffer
+	err := bsgf.EncodeMulti([]*bsgf.ViewGame{game}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := rewriteZip(t, buf.Bytes(), "games/"+game.Game.ID+".json", func(contents []byte) []byte {
+		return bytes.Replace(contents, []byte(`"Turn":1,`), []byte(`"Turn":2,`), 1)
+	})
+	_, err = bsgf.DecodeMulti(data)
+	if !errors.Is(err, bsgf.ErrCorruptArchive) {
+		t.Errorf("expected ErrCorruptArchive, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package battlesnakegameformat
+
+// Neighbors returns the four cells next to c, in the order of Directions.
+// They may be off the board.
+func (c ViewCoord) Neighbors() [4]ViewCoord {
+	var n [4]ViewCoord
+	for i, d := range Directions {
+		n[i] = d.Apply(c)
+	}
+	return n
+}
+
+// Manhattan is the number of moves between c and o on a board without
+// walls or wrapping.
+func (c ViewCoord) Manhattan(o ViewCoord) int32 {
+	return absInt32(c.X-o.X) + absInt32(c.Y-o.Y)
+}
+
+// ManhattanWrapped is like Manhattan for wrapped boards, where a snake can
+// go off one edge and come back on the other.
+func (c ViewCoord) ManhattanWrapped(o ViewCoord, width, height int32) int32 {
+	return wrappedDist(c.X, o.X, width) + wrappedDist(c.Y, o.Y, height)
+}
+
+// Chebyshev is the larger of the x and y distances between c and o.
+func (c ViewCoord) Chebyshev(o ViewCoord) int32 {
+	dx, dy := absInt32(c.X-o.X), absInt32(c.Y-o.Y)
+	if dx > dy {
+		return dx
+	}
+	return dy
+}
+
+// InBounds is true if c is on a width by height board.
+func (c ViewCoord) InBounds(width, height int32) bool {
+	return c.X >= 0 && c.X < width && c.Y >= 0 && c.Y < height
+}
+
+// Wrap maps c back onto a width by height board the way wrapped rulesets
+// do, so (-1, 0) becomes (width-1, 0).
+func (c ViewCoord) Wrap(width, height int32) ViewCoord {
+	return ViewCoord{X: wrap(c.X, width), Y: wrap(c.Y, height)}
+}
+
+// Neighbors returns the four cells next to c, in the order of Directions.
+func (c MoveCoord) Neighbors() [4]MoveCoord {
+	var n [4]MoveCoord
+	for i, v := range ViewCoord(c).Neighbors() {
+		n[i] = MoveCoord(v)
+	}
+	return n
+}
+
+// Manhattan is the number of moves between c and o on a board without
+// walls or wrapping.
+func (c MoveCoord) Manhattan(o MoveCoord) int32 {
+	return ViewCoord(c).Manhattan(ViewCoord(o))
+}
+
+// ManhattanWrapped is like Manhattan for wrapped boards.
+func (c MoveCoord) ManhattanWrapped(o MoveCoord, width, height int32) int32 {
+	return ViewCoord(c).ManhattanWrapped(ViewCoord(o), width, height)
+}
+
+// Chebyshev is the larger of the x and y distances between c and o.
+func (c MoveCoord) Chebyshev(o MoveCoord) int32 {
+	return ViewCoord(c).Chebyshev(ViewCoord(o))
+}
+
+// InBounds is true if c is on a width by height board.
+func (c MoveCoord) InBounds(width, height int32) bool {
+	return ViewCoord(c).InBounds(width, height)
+}
+
+// Wrap maps c back onto a width by height board the way wrapped rulesets do.
+func (c MoveCoord) Wrap(width, height int32) MoveCoord {
+	return MoveCoord(ViewCoord(c).Wrap(width, height))
+}
+
+func wrap(v, size int32) int32 {
+	if size <= 0 {
+		return v
+	}
+	v %= size
+	if v < 0 {
+		v += size
+	}
+	return v
+}
+
+func wrappedDist(a, b, size int32) int32 {
+	d := absInt32(a - b)
+	if size > 0 {
+		d %= size
+		if size-d < d {
+			d = size - d
+		}
+	}
+	return d
+}
+
+func absInt32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
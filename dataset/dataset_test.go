@@ -0,0 +1,246 @@
+package dataset_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/dataset"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+)
+
+func play(t *testing.T, seed int64) *bsgf.ViewGame {
+	t.Helper()
+	players := []harness.Player{
+		{Name: "Alpha", Snake: harness.NewRandomSnake(seed)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(seed + 1)},
+		{Name: "Gamma", Snake: harness.NewRandomSnake(seed + 2)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: seed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.Winner() == "" {
+		t.Fatalf("seed %d has no winner", seed)
+	}
+	return game
+}
+
+func TestLabels(t *testing.T) {
+	game := play(t, 1)
+	labels, err := dataset.Labels(game, dataset.LabelOptions{FatalWithin: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	final := &game.Frames[len(game.Frames)-1]
+	for _, l := range labels {
+		var snake *bsgf.ViewSnake
+		for i := range final.Snakes {
+			if final.Snakes[i].ID == l.SnakeID {
+				snake = &final.Snakes[i]
+			}
+		}
+		want := dataset.LabelLoser
+		switch {
+		case l.SnakeID == game.Winner():
+			want = dataset.LabelWinner
+		case snake.Death.Eliminated() && snake.Death.Turn-l.Turn <= 3:
+			want = dataset.LabelFatal
+		}
+		if l.Label != want {
+			t.Errorf("turn %d: %s's move is %s, expected %s", l.Turn, l.SnakeID, l.Label, want)
+		}
+		if snake.Death.Eliminated() && (l.DeathCause != snake.Death.Cause || l.TurnsToDeath != snake.Death.Turn-l.Turn) {
+			t.Errorf("turn %d: %s died of %s in %d turns", l.Turn, l.SnakeID, l.DeathCause, l.TurnsToDeath)
+		}
+		move, err := game.MoveAt(l.Turn, l.SnakeID)
+		if err != nil || move != l.Move {
+			t.Errorf("turn %d: %s moved %s, labeled %s", l.Turn, l.SnakeID, move, l.Move)
+		}
+	}
+	counts := dataset.LabelCounts(labels)
+	if counts[dataset.LabelWinner] != int(game.LastTurn) || counts[dataset.LabelFatal] != 2*3 {
+		t.Errorf("unexpected label counts %v", counts)
+	}
+}
+
+func TestSamples(t *testing.T) {
+	game := play(t, 1)
+	samples, err := dataset.Samples(game, dataset.Options{Stride: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (int(game.LastTurn) + 2) / 3; len(samples) != want {
+		t.Errorf("expected %d samples, got %d", want, len(samples))
+	}
+	for i, s := range samples {
+		if s.SnakeID != game.Winner() || !s.Won || s.Turn != int32(3*i) || s.State.You.ID != s.SnakeID {
+			t.Errorf("unexpected sample %+v", s)
+		}
+	}
+	var loser string
+	for _, s := range game.Frames[0].Snakes {
+		if s.ID != game.Winner() {
+			loser = s.ID
+		}
+	}
+	deathTurn, _ := game.DeathTurn(loser)
+	samples, err = dataset.Samples(game, dataset.Options{SnakeID: loser, SkipBeforeDeath: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := samples[len(samples)-1].Turn; last != deathTurn-6 {
+		t.Errorf("expected the last sample on turn %d, got %d", deathTurn-6, last)
+	}
+	samples, err = dataset.Samples(game, dataset.Options{SnakeID: loser, Outcome: dataset.OutcomeWin})
+	if err != nil || len(samples) != 0 {
+		t.Errorf("expected no samples of a loser winning, got %d, %v", len(samples), err)
+	}
+}
+
+func TestEncodePlanes(t *testing.T) {
+	frame := &bsgf.ViewFrame{
+		Food:    []bsgf.ViewCoord{{X: 0, Y: 2}},
+		Hazards: []bsgf.ViewCoord{{X: 2, Y: 0}},
+		Snakes: []bsgf.ViewSnake{
+			{ID: "a", Health: 50, Body: []bsgf.ViewCoord{{X: 0, Y: 0}, {X: 0, Y: 1}}},
+			{ID: "b", Health: 25, Body: []bsgf.ViewCoord{{X: 2, Y: 2}, {X: 1, Y: 2}}},
+		},
+	}
+	p, err := dataset.EncodePlanes(frame, "a", dataset.PlaneOptions{Width: 3, Height: 3, Size: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Channels != int(dataset.PlaneCount) || p.Width != 4 || p.Height != 4 {
+		t.Fatalf("unexpected shape %dx%dx%d", p.Channels, p.Height, p.Width)
+	}
+	for _, test := range []struct {
+		plane dataset.Plane
+		x, y  int
+		want  float32
+	}{
+		{dataset.PlaneOwnHead, 0, 0, 1},
+		{dataset.PlaneOwnBody, 0, 1, 1},
+		{dataset.PlaneOwnHead, 0, 1, 0},
+		{dataset.PlaneEnemyHeads, 2, 2, 1},
+		{dataset.PlaneEnemyBodies, 1, 2, 1},
+		{dataset.PlaneEnemyHealth, 1, 2, 0.25},
+		{dataset.PlaneFood, 0, 2, 1},
+		{dataset.PlaneHazards, 2, 0, 1},
+		{dataset.PlaneOwnHealth, 1, 1, 0.5},
+		{dataset.PlaneBoard, 2, 2, 1},
+		// padding
+		{dataset.PlaneBoard, 3, 3, 0},
+		{dataset.PlaneOwnHealth, 3, 0, 0},
+	} {
+		if got := p.At(test.plane, test.x, test.y); got != test.want {
+			t.Errorf("plane %d at (%d,%d) is %g, expected %g", test.plane, test.x, test.y, got, test.want)
+		}
+	}
+	if _, err := dataset.EncodePlanes(frame, "a", dataset.PlaneOptions{Width: 3, Height: 3, Size: 2}); err == nil {
+		t.Error("expected an error for planes smaller than the board")
+	}
+}
+
+func TestSamplerDuplicates(t *testing.T) {
+	game := play(t, 1)
+	samples, err := dataset.Samples(game, dataset.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := dataset.NewSampler(dataset.SamplerOptions{PerPhase: len(samples)})
+	if added := s.Add(samples...); added != len(samples) {
+		t.Errorf("expected every position to be new, %d of %d were", added, len(samples))
+	}
+	if added := s.Add(samples...); added != 0 || s.Duplicates() != len(samples) {
+		t.Errorf("expected every position to repeat, %d were new", added)
+	}
+	if got := s.Samples(); len(got) != len(samples) {
+		t.Errorf("expected %d samples, got %d", len(samples), len(got))
+	}
+}
+
+func TestSplits(t *testing.T) {
+	var ids []string
+	for i := 0; i < 200; i++ {
+		ids = append(ids, fmt.Sprintf("game-%d", i))
+	}
+	opts := dataset.SplitOptions{Val: 0.1, Test: 0.2, Seed: "s"}
+	m, err := dataset.BuildSplits(append(ids, ids[0]), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for split, games := range m.Splits {
+		total += len(games)
+		for _, id := range games {
+			if dataset.SplitOf(id, opts) != split {
+				t.Errorf("%s is in %s", id, split)
+			}
+		}
+	}
+	if total != len(ids) || len(m.Splits[dataset.SplitTest]) < 20 || len(m.Splits[dataset.SplitVal]) < 5 {
+		t.Errorf("unexpected split sizes train %d, val %d, test %d", len(m.Splits[dataset.SplitTrain]), len(m.Splits[dataset.SplitVal]), len(m.Splits[dataset.SplitTest]))
+	}
+	if _, err := dataset.BuildSplits(ids, dataset.SplitOptions{Val: 0.6, Test: 0.6}); err == nil {
+		t.Error("expected an error for fractions over 1")
+	}
+}
+
+func TestShards(t *testing.T) {
+	dir := t.TempDir()
+	w := dataset.NewShardWriter(dir, "labels", "labels", 200)
+	labels, err := dataset.Labels(play(t, 1), dataset.LabelOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range labels {
+		if err := w.Write(&labels[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Records != len(labels) || len(m.Shards) < 2 {
+		t.Fatalf("expected %d records over several shards, got %+v", len(labels), m)
+	}
+	if err := dataset.VerifyShards(w.ManifestPath()); err != nil {
+		t.Fatal(err)
+	}
+	r, err := dataset.OpenShards(w.ManifestPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		var l dataset.LabeledMove
+		err := r.Next(&l)
+		if err == io.EOF {
+			if i != len(labels) {
+				t.Errorf("read %d of %d records", i, len(labels))
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.Turn != labels[i].Turn || l.SnakeID != labels[i].SnakeID || l.Label != labels[i].Label {
+			t.Errorf("record %d is %+v, expected %+v", i, l, labels[i])
+		}
+	}
+	r.Close()
+	// truncate a shard
+	path := filepath.Join(dir, m.Shards[1].File)
+	if err := os.Truncate(path, m.Shards[1].Bytes-1); err != nil {
+		t.Fatal(err)
+	}
+	if err := dataset.VerifyShards(w.ManifestPath()); !errors.Is(err, dataset.ErrBadShard) {
+		t.Errorf("expected ErrBadShard, got %v", err)
+	}
+}
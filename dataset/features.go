@@ -0,0 +1,312 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+	"github.com/jlafayette/battlesnake-game-format-go/bitboard"
+)
+
+// FeatureContext is what a feature extractor is given: one live snake on
+// one frame. Work shared by several features, like territory, is done once
+// per frame and cached.
+type FeatureContext struct {
+	Game  *bsgf.ViewGame
+	Frame *bsgf.ViewFrame
+	Snake *bsgf.ViewSnake
+	// Index of Snake in Frame.Snakes
+	Index int
+
+	shared *frameCache
+}
+
+type frameCache struct {
+	territory []int
+	board     *bitboard.Board
+}
+
+// Territory is analysis.Territory for the frame
+func (c *FeatureContext) Territory() []int {
+	if c.shared.territory == nil {
+		c.shared.territory = analysis.Territory(c.Game.Game.Width, c.Game.Game.Height, c.Frame)
+	}
+	return c.shared.territory
+}
+
+// Board is the frame as bitboards
+func (c *FeatureContext) Board() *bitboard.Board {
+	if c.shared.board == nil {
+		c.shared.board = bitboard.FromFrame(c.Game.Game.Width, c.Game.Game.Height, c.Frame)
+	}
+	return c.shared.board
+}
+
+// FeatureFunc computes one feature, returning false when it has no value
+// for this snake, which is written as an empty cell
+type FeatureFunc func(c *FeatureContext) (float64, bool)
+
+// Feature is a named extractor
+type Feature struct {
+	Name    string
+	Extract FeatureFunc
+}
+
+var (
+	featuresMu sync.RWMutex
+	features   = make(map[string]FeatureFunc)
+)
+
+// RegisterFeature makes fn available to NewFeatureBuilder under name,
+// replacing any earlier feature with that name
+func RegisterFeature(name string, fn FeatureFunc) {
+	featuresMu.Lock()
+	defer featuresMu.Unlock()
+	features[name] = fn
+}
+
+// FeatureNames lists the registered features, sorted
+func FeatureNames() []string {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FeatureBuilder runs features over every live snake on every frame
+type FeatureBuilder struct {
+	Features []Feature
+}
+
+// NewFeatureBuilder starts a builder with registered features, in the
+// order given
+func NewFeatureBuilder(names ...string) (*FeatureBuilder, error) {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	b := &FeatureBuilder{}
+	for _, name := range names {
+		fn, ok := features[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown feature %q", name)
+		}
+		b.Features = append(b.Features, Feature{Name: name, Extract: fn})
+	}
+	return b, nil
+}
+
+// Add appends a feature that doesn't need to be registered
+func (b *FeatureBuilder) Add(name string, fn FeatureFunc) *FeatureBuilder {
+	b.Features = append(b.Features, Feature{Name: name, Extract: fn})
+	return b
+}
+
+// FeatureRow holds the features of one snake on one frame, in the order of
+// the builder's Features. Missing values are NaN.
+type FeatureRow struct {
+	GameID  string
+	Turn    int32
+	SnakeID string
+	Values  []float64
+}
+
+// Columns are the names of the columns FeatureWriter writes
+func (b *FeatureBuilder) Columns() []string {
+	columns := []string{"game_id", "turn", "snake_id"}
+	for _, f := range b.Features {
+		columns = append(columns, f.Name)
+	}
+	return columns
+}
+
+// Rows extracts the features of every live snake on every frame of game
+func (b *FeatureBuilder) Rows(game *bsgf.ViewGame) []FeatureRow {
+	var rows []FeatureRow
+	for i := range game.Frames {
+		frame := &game.Frames[i]
+		shared := &frameCache{}
+		for k := range frame.Snakes {
+			s := &frame.Snakes[k]
+			if s.Death.Eliminated() || len(s.Body) == 0 {
+				continue
+			}
+			c := &FeatureContext{Game: game, Frame: frame, Snake: s, Index: k, shared: shared}
+			row := FeatureRow{GameID: game.Game.ID, Turn: frame.Turn, SnakeID: s.ID, Values: make([]float64, len(b.Features))}
+			for f, feature := range b.Features {
+				v, ok := feature.Extract(c)
+				if !ok {
+					v = math.NaN()
+				}
+				row.Values[f] = v
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// FeatureWriter writes feature rows as csv, with a header first. It's an
+// analysis.Analyzer, so a corpus can be extracted with analysis.Run.
+type FeatureWriter struct {
+	Builder *FeatureBuilder
+
+	w      *csv.Writer
+	header bool
+	err    error
+}
+
+func NewFeatureWriter(b *FeatureBuilder, w io.Writer) *FeatureWriter {
+	return &FeatureWriter{Builder: b, w: csv.NewWriter(w)}
+}
+
+// Write adds the rows of one game
+func (fw *FeatureWriter) Write(rows []FeatureRow) error {
+	if fw.err != nil {
+		return fw.err
+	}
+	if !fw.header {
+		fw.header = true
+		fw.err = fw.w.Write(fw.Builder.Columns())
+	}
+	record := make([]string, 3+len(fw.Builder.Features))
+	for _, row := range rows {
+		if fw.err != nil {
+			break
+		}
+		record[0] = row.GameID
+		record[1] = strconv.Itoa(int(row.Turn))
+		record[2] = row.SnakeID
+		for i, v := range row.Values {
+			record[3+i] = ""
+			if !math.IsNaN(v) {
+				record[3+i] = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+		}
+		fw.err = fw.w.Write(record)
+	}
+	return fw.err
+}
+
+func (fw *FeatureWriter) Analyze(game *bsgf.ViewGame) (interface{}, error) {
+	return fw.Builder.Rows(game), nil
+}
+
+func (fw *FeatureWriter) Merge(result interface{}) {
+	fw.Write(result.([]FeatureRow))
+}
+
+// Flush writes out buffered rows and returns the first error from any
+// write
+func (fw *FeatureWriter) Flush() error {
+	if !fw.header && fw.err == nil {
+		fw.Write(nil)
+	}
+	fw.w.Flush()
+	if fw.err != nil {
+		return fmt.Errorf("error writing features: %w", fw.err)
+	}
+	if err := fw.w.Error(); err != nil {
+		return fmt.Errorf("error writing features: %w", err)
+	}
+	return nil
+}
+
+// Health buckets, as upper bounds
+var healthBuckets = []int32{25, 50, 75}
+
+func init() {
+	RegisterFeature("health", func(c *FeatureContext) (float64, bool) {
+		return float64(c.Snake.Health), true
+	})
+	RegisterFeature("health_bucket", func(c *FeatureContext) (float64, bool) {
+		// 0 for 25 or less up to 3 for over 75
+		for i, max := range healthBuckets {
+			if c.Snake.Health <= max {
+				return float64(i), true
+			}
+		}
+		return float64(len(healthBuckets)), true
+	})
+	RegisterFeature("length", func(c *FeatureContext) (float64, bool) {
+		return float64(len(c.Snake.Body)), true
+	})
+	RegisterFeature("length_lead", func(c *FeatureContext) (float64, bool) {
+		// how much longer than the longest other live snake
+		longest, found := 0, false
+		for i, s := range c.Frame.Snakes {
+			if i != c.Index && !s.Death.Eliminated() && (!found || len(s.Body) > longest) {
+				longest, found = len(s.Body), true
+			}
+		}
+		return float64(len(c.Snake.Body) - longest), found
+	})
+	RegisterFeature("territory", func(c *FeatureContext) (float64, bool) {
+		return float64(c.Territory()[c.Index]), true
+	})
+	RegisterFeature("reachable", func(c *FeatureContext) (float64, bool) {
+		board := c.Board()
+		head := c.Snake.Body[0]
+		if !head.InBounds(board.Width, board.Height) {
+			return 0, true
+		}
+		start := bitboard.New(board.Width, board.Height)
+		start.Set(head)
+		filled := bitboard.FloodFill(start, board.Open())
+		return float64(filled.Count() - 1), true
+	})
+	RegisterFeature("food_distance", func(c *FeatureContext) (float64, bool) {
+		d, ok := nearestDistance(c.Snake.Body[0], c.Frame.Food)
+		return float64(d), ok
+	})
+	RegisterFeature("enemy_head_distance", func(c *FeatureContext) (float64, bool) {
+		var heads []bsgf.ViewCoord
+		for i, s := range c.Frame.Snakes {
+			if i != c.Index && !s.Death.Eliminated() && len(s.Body) > 0 {
+				heads = append(heads, s.Body[0])
+			}
+		}
+		d, ok := nearestDistance(c.Snake.Body[0], heads)
+		return float64(d), ok
+	})
+	RegisterFeature("wall_distance", func(c *FeatureContext) (float64, bool) {
+		head := c.Snake.Body[0]
+		w, h := c.Game.Game.Width, c.Game.Game.Height
+		d := head.X
+		for _, v := range []int32{w - 1 - head.X, head.Y, h - 1 - head.Y} {
+			if v < d {
+				d = v
+			}
+		}
+		return float64(d), true
+	})
+	RegisterFeature("in_hazard", func(c *FeatureContext) (float64, bool) {
+		head := c.Snake.Body[0]
+		for _, hz := range c.Frame.Hazards {
+			if hz == head {
+				return 1, true
+			}
+		}
+		return 0, true
+	})
+}
+
+// nearestDistance is the Manhattan distance from head to the closest of
+// targets
+func nearestDistance(head bsgf.ViewCoord, targets []bsgf.ViewCoord) (int32, bool) {
+	best, found := int32(0), false
+	for _, t := range targets {
+		if d := head.Manhattan(t); !found || d < best {
+			best, found = d, true
+		}
+	}
+	return best, found
+}
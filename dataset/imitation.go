@@ -0,0 +1,105 @@
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Outcome filters which games produce samples, from the point of view of the
+// labeled snake
+type Outcome int
+
+const (
+	OutcomeAny Outcome = iota
+	OutcomeWin
+	OutcomeLoss
+)
+
+// Sample is a position labeled with the move the chosen snake made there
+type Sample struct {
+	GameID  string              `json:"gameId"`
+	Turn    int32               `json:"turn"`
+	SnakeID string              `json:"snakeId"`
+	Won     bool                `json:"won"`
+	State   *bsgf.MoveGameState `json:"state"`
+	Move    bsgf.Direction      `json:"move"`
+}
+
+type Options struct {
+	// SnakeID labels moves made by this snake. When empty, the winner of
+	// each game is used and games without a winner are skipped.
+	SnakeID string
+	Outcome Outcome
+	// SkipBeforeDeath drops moves made within this many turns of the
+	// labeled snake's elimination, since those are likely blunders.
+	SkipBeforeDeath int32
+	// Stride keeps every Nth eligible position (1 or 0 keeps them all)
+	Stride int
+}
+
+// Samples labels positions from game with the moves made by the winner (or
+// opts.SnakeID)
+func Samples(game *bsgf.ViewGame, opts Options) ([]Sample, error) {
+	winnerId := game.Winner()
+	snakeId := opts.SnakeID
+	if snakeId == "" {
+		snakeId = winnerId
+	}
+	if snakeId == "" {
+		return nil, nil
+	}
+	won := snakeId == winnerId
+	if (opts.Outcome == OutcomeWin && !won) || (opts.Outcome == OutcomeLoss && won) {
+		return nil, nil
+	}
+	deathTurn, died := game.DeathTurn(snakeId)
+	stride := opts.Stride
+	if stride < 1 {
+		stride = 1
+	}
+	var samples []Sample
+	eligible := 0
+	for i := 0; i+1 < len(game.Frames); i++ {
+		turn := game.Frames[i].Turn
+		if died && turn >= deathTurn-opts.SkipBeforeDeath {
+			break
+		}
+		move, err := game.MoveAt(turn, snakeId)
+		if err != nil {
+			// snake was not alive on this turn
+			continue
+		}
+		eligible++
+		if (eligible-1)%stride != 0 {
+			continue
+		}
+		state, err := game.ToMove(turn, snakeId)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, Sample{
+			GameID:  game.Game.ID,
+			Turn:    turn,
+			SnakeID: snakeId,
+			Won:     won,
+			State:   state,
+			Move:    move,
+		})
+	}
+	return samples, nil
+}
+
+// WriteJSONL writes one sample per line
+func WriteJSONL(w io.Writer, samples []Sample) error {
+	enc := json.NewEncoder(w)
+	for i := range samples {
+		err := enc.Encode(&samples[i])
+		if err != nil {
+			return fmt.Errorf("error writing sample: %w", err)
+		}
+	}
+	return nil
+}
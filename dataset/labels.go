@@ -0,0 +1,122 @@
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// MoveLabel is the move-quality label of a move, from the outcome for the
+// snake that made it
+type MoveLabel string
+
+const (
+	// LabelWinner is a move made by the snake that went on to win
+	LabelWinner MoveLabel = "winner"
+	// LabelLoser is a move made by a snake that didn't win, but didn't
+	// die soon after either
+	LabelLoser MoveLabel = "loser"
+	// LabelFatal is a move made within LabelOptions.FatalWithin turns of
+	// the snake being eliminated, the likely blunder
+	LabelFatal MoveLabel = "fatal"
+)
+
+// LabeledMove is one move made by one snake, labeled with how things turned
+// out for it
+type LabeledMove struct {
+	GameID  string         `json:"gameId"`
+	Turn    int32          `json:"turn"`
+	SnakeID string         `json:"snakeId"`
+	Move    bsgf.Direction `json:"move"`
+	Label   MoveLabel      `json:"label"`
+	// TurnsToDeath is how many turns after this move the snake was
+	// eliminated, 0 if it never was
+	TurnsToDeath int32           `json:"turnsToDeath,omitempty"`
+	DeathCause   bsgf.DeathCause `json:"deathCause,omitempty"`
+	// State is the position the move was made from, when
+	// LabelOptions.States is set
+	State *bsgf.MoveGameState `json:"state,omitempty"`
+}
+
+type LabelOptions struct {
+	// FatalWithin labels moves made within this many turns of the snake's
+	// elimination as fatal. 0 means 1, so only the move into the death.
+	FatalWithin int32
+	// States includes the position each move was made from
+	States bool
+}
+
+// Labels labels every move made by every snake in game. Moves by the
+// winner are only ever LabelWinner, so in games won by the last snake
+// standing the fatal moves are all by losers.
+func Labels(game *bsgf.ViewGame, opts LabelOptions) ([]LabeledMove, error) {
+	final, err := game.FinalFrame()
+	if err != nil {
+		return nil, err
+	}
+	within := opts.FatalWithin
+	if within < 1 {
+		within = 1
+	}
+	winnerId := game.Winner()
+	var labels []LabeledMove
+	for _, snake := range final.Snakes {
+		deathTurn, died := game.DeathTurn(snake.ID)
+		for i := 0; i+1 < len(game.Frames); i++ {
+			turn := game.Frames[i].Turn
+			move, err := game.MoveAt(turn, snake.ID)
+			if err != nil {
+				// snake was not alive on this turn
+				continue
+			}
+			l := LabeledMove{
+				GameID:  game.Game.ID,
+				Turn:    turn,
+				SnakeID: snake.ID,
+				Move:    move,
+				Label:   LabelLoser,
+			}
+			if died {
+				l.TurnsToDeath = deathTurn - turn
+				l.DeathCause = snake.Death.Cause
+			}
+			switch {
+			case snake.ID == winnerId:
+				l.Label = LabelWinner
+			case died && l.TurnsToDeath <= within:
+				l.Label = LabelFatal
+			}
+			if opts.States {
+				l.State, err = game.ToMove(turn, snake.ID)
+				if err != nil {
+					return nil, err
+				}
+			}
+			labels = append(labels, l)
+		}
+	}
+	return labels, nil
+}
+
+// LabelCounts tallies labels, to check how balanced a labeled corpus is
+func LabelCounts(labels []LabeledMove) map[MoveLabel]int {
+	counts := make(map[MoveLabel]int)
+	for _, l := range labels {
+		counts[l.Label]++
+	}
+	return counts
+}
+
+// WriteLabelsJSONL writes one labeled move per line
+func WriteLabelsJSONL(w io.Writer, labels []LabeledMove) error {
+	enc := json.NewEncoder(w)
+	for i := range labels {
+		err := enc.Encode(&labels[i])
+		if err != nil {
+			return fmt.Errorf("error writing label: %w", err)
+		}
+	}
+	return nil
+}
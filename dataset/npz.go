@@ -0,0 +1,167 @@
+package dataset
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+type NPZOptions struct {
+	// Samples picks the positions and snakes, as for Samples
+	Samples Options
+	// Size pads the planes, see PlaneOptions. Needed when the games have
+	// different board sizes.
+	Size int32
+}
+
+// WriteNPZ labels positions from games the same way as Samples and writes
+// them as a numpy .npz archive with these arrays, one entry per sample:
+//
+//	planes     float32 (n, channels, height, width), see EncodePlanes
+//	moves      uint8 (n,), the index of the move in bsgf.Directions
+//	won        bool (n,)
+//	turns      int32 (n,)
+//	game_ids   str (n,)
+//	snake_ids  str (n,)
+//
+// so np.load(path)["planes"] is ready for training. Everything is held in
+// memory until the archive is written.
+func WriteNPZ(w io.Writer, games []*bsgf.ViewGame, opts NPZOptions) error {
+	var planes []float32
+	var moves []byte
+	var won []byte
+	var turns []byte
+	var gameIds, snakeIds []string
+	var shape [3]int
+	for _, game := range games {
+		samples, err := Samples(game, opts.Samples)
+		if err != nil {
+			return err
+		}
+		planeOpts := PlaneOptions{Width: game.Game.Width, Height: game.Game.Height, Size: opts.Size}
+		for _, s := range samples {
+			frame, err := game.FrameAt(s.Turn)
+			if err != nil {
+				return err
+			}
+			p, err := EncodePlanes(frame, s.SnakeID, planeOpts)
+			if err != nil {
+				return err
+			}
+			if len(planes) == 0 {
+				shape = [3]int{p.Channels, p.Height, p.Width}
+			} else if shape != [3]int{p.Channels, p.Height, p.Width} {
+				return fmt.Errorf("game %s has %dx%d planes but earlier games have %dx%d, set Size to pad boards to one shape", game.Game.ID, p.Width, p.Height, shape[2], shape[1])
+			}
+			planes = append(planes, p.Data...)
+			moves = append(moves, directionIndex(s.Move))
+			if s.Won {
+				won = append(won, 1)
+			} else {
+				won = append(won, 0)
+			}
+			turns = binary.LittleEndian.AppendUint32(turns, uint32(s.Turn))
+			gameIds = append(gameIds, s.GameID)
+			snakeIds = append(snakeIds, s.SnakeID)
+		}
+	}
+	n := len(moves)
+	planeData := make([]byte, 4*len(planes))
+	for i, v := range planes {
+		binary.LittleEndian.PutUint32(planeData[4*i:], math.Float32bits(v))
+	}
+	gameIdDescr, gameIdData := npyStrings(gameIds)
+	snakeIdDescr, snakeIdData := npyStrings(snakeIds)
+
+	z := zip.NewWriter(w)
+	for _, a := range []struct {
+		name  string
+		descr string
+		shape []int
+		data  []byte
+	}{
+		{"planes", "<f4", []int{n, shape[0], shape[1], shape[2]}, planeData},
+		{"moves", "|u1", []int{n}, moves},
+		{"won", "|b1", []int{n}, won},
+		{"turns", "<i4", []int{n}, turns},
+		{"game_ids", gameIdDescr, []int{n}, gameIdData},
+		{"snake_ids", snakeIdDescr, []int{n}, snakeIdData},
+	} {
+		f, err := z.CreateHeader(&zip.FileHeader{Name: a.name + ".npy", Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		err = writeNPY(f, a.descr, a.shape, a.data)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", a.name, err)
+		}
+	}
+	return z.Close()
+}
+
+func directionIndex(d bsgf.Direction) byte {
+	for i, dir := range bsgf.Directions {
+		if dir == d {
+			return byte(i)
+		}
+	}
+	return math.MaxUint8
+}
+
+// writeNPY writes one array in the .npy format, version 1.0
+func writeNPY(w io.Writer, descr string, shape []int, data []byte) error {
+	dims := make([]string, len(shape))
+	for i, n := range shape {
+		dims[i] = fmt.Sprint(n)
+	}
+	shapeText := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shapeText += ","
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, shapeText)
+	// magic, version and length take 10 bytes, and the data has to start
+	// on a multiple of 64
+	pad := 64 - (10+len(header)+1)%64
+	if pad == 64 {
+		pad = 0
+	}
+	header += strings.Repeat(" ", pad) + "\n"
+	prefix := []byte("\x93NUMPY\x01\x00")
+	prefix = binary.LittleEndian.AppendUint16(prefix, uint16(len(header)))
+	for _, b := range [][]byte{prefix, []byte(header), data} {
+		_, err := w.Write(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// npyStrings encodes strings as a fixed width numpy unicode array, which
+// is utf-32 padded to the longest string
+func npyStrings(values []string) (string, []byte) {
+	width := 1
+	for _, v := range values {
+		if n := utf8.RuneCountInString(v); n > width {
+			width = n
+		}
+	}
+	data := make([]byte, 0, 4*width*len(values))
+	for _, v := range values {
+		count := 0
+		for _, r := range v {
+			data = binary.LittleEndian.AppendUint32(data, uint32(r))
+			count++
+		}
+		for ; count < width; count++ {
+			data = binary.LittleEndian.AppendUint32(data, 0)
+		}
+	}
+	return fmt.Sprintf("<U%d", width), data
+}
@@ -0,0 +1,137 @@
+package dataset
+
+import (
+	"fmt"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Plane is one channel of a Planes tensor
+type Plane int
+
+// Channels in the order they appear in Planes.Data
+const (
+	// 1 on every segment of the snake the planes are for
+	PlaneOwnBody Plane = iota
+	// 1 on its head
+	PlaneOwnHead
+	// 1 on every segment of every other live snake
+	PlaneEnemyBodies
+	// 1 on the head of every other live snake
+	PlaneEnemyHeads
+	PlaneFood
+	PlaneHazards
+	// the snake's health / 100 on every board cell
+	PlaneOwnHealth
+	// health / 100 of each other live snake on its body
+	PlaneEnemyHealth
+	// 1 on board cells and 0 on padding, so boards of different sizes can
+	// share a model
+	PlaneBoard
+	PlaneCount
+)
+
+// PlaneOptions sets the board size and shape of the tensor
+type PlaneOptions struct {
+	// Width and Height of the board, the frame doesn't know its own size
+	Width, Height int32
+	// Size pads the planes out to Size x Size when the board is smaller,
+	// with the board in the bottom left corner. 0 uses the board size.
+	Size int32
+}
+
+// Planes is a channels x height x width tensor of float32 in row-major
+// order, so the value for channel c at (x, y) is
+// Data[(c*Height+y)*Width+x]. Row 0 is y=0, the bottom of the board, the
+// same as the coordinates in frames.
+type Planes struct {
+	Channels, Height, Width int
+	Data                    []float32
+}
+
+// At is the value of channel c at (x, y)
+func (p *Planes) At(c Plane, x, y int) float32 {
+	return p.Data[(int(c)*p.Height+y)*p.Width+x]
+}
+
+func (p *Planes) set(c Plane, coord bsgf.ViewCoord, value float32) {
+	p.Data[(int(c)*p.Height+int(coord.Y))*p.Width+int(coord.X)] = value
+}
+
+// EncodePlanes encodes frame from the point of view of snakeId, the input
+// representation for networks trained on recorded games. Squad mates count
+// as enemies. Coordinates off the board are left out.
+func EncodePlanes(frame *bsgf.ViewFrame, snakeId string, opts PlaneOptions) (*Planes, error) {
+	if opts.Width <= 0 || opts.Height <= 0 {
+		return nil, fmt.Errorf("invalid board size %dx%d", opts.Width, opts.Height)
+	}
+	width, height := int(opts.Width), int(opts.Height)
+	if opts.Size > 0 {
+		if int(opts.Size) < width || int(opts.Size) < height {
+			return nil, fmt.Errorf("board %dx%d doesn't fit in planes of size %d", width, height, opts.Size)
+		}
+		width, height = int(opts.Size), int(opts.Size)
+	}
+	var you *bsgf.ViewSnake
+	for i := range frame.Snakes {
+		if frame.Snakes[i].ID == snakeId {
+			you = &frame.Snakes[i]
+		}
+	}
+	if you == nil {
+		return nil, fmt.Errorf("%w: no snake %s on turn %d", bsgf.ErrSnakeNotFound, snakeId, frame.Turn)
+	}
+	if you.Death.Eliminated() || len(you.Body) == 0 {
+		return nil, fmt.Errorf("snake %s is not alive on turn %d", snakeId, frame.Turn)
+	}
+	p := &Planes{
+		Channels: int(PlaneCount),
+		Height:   height,
+		Width:    width,
+		Data:     make([]float32, int(PlaneCount)*height*width),
+	}
+	inside := func(c bsgf.ViewCoord) bool {
+		return c.InBounds(opts.Width, opts.Height)
+	}
+	health := float32(you.Health) / 100
+	for y := int32(0); y < opts.Height; y++ {
+		for x := int32(0); x < opts.Width; x++ {
+			p.set(PlaneBoard, bsgf.ViewCoord{X: x, Y: y}, 1)
+			p.set(PlaneOwnHealth, bsgf.ViewCoord{X: x, Y: y}, health)
+		}
+	}
+	for _, c := range you.Body {
+		if inside(c) {
+			p.set(PlaneOwnBody, c, 1)
+		}
+	}
+	if inside(you.Body[0]) {
+		p.set(PlaneOwnHead, you.Body[0], 1)
+	}
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		if s == you || s.Death.Eliminated() || len(s.Body) == 0 {
+			continue
+		}
+		for _, c := range s.Body {
+			if inside(c) {
+				p.set(PlaneEnemyBodies, c, 1)
+				p.set(PlaneEnemyHealth, c, float32(s.Health)/100)
+			}
+		}
+		if inside(s.Body[0]) {
+			p.set(PlaneEnemyHeads, s.Body[0], 1)
+		}
+	}
+	for _, c := range frame.Food {
+		if inside(c) {
+			p.set(PlaneFood, c, 1)
+		}
+	}
+	for _, c := range frame.Hazards {
+		if inside(c) {
+			p.set(PlaneHazards, c, 1)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,202 @@
+package dataset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// PositionKey hashes a position from the point of view of state.You so
+// that positions which are the same up to a rotation or reflection of the
+// board, or the order of the other snakes, food and hazards, get the same
+// key. Non-square boards only have the four symmetries that keep their
+// shape. Health is left out unless withHealth is set, so openings that only
+// differ by a point or two of health count as the same.
+func PositionKey(state *bsgf.MoveGameState, withHealth bool) uint64 {
+	width, height := state.Board.Width, state.Board.Height
+	symmetries := 4
+	if width == height {
+		symmetries = 8
+	}
+	var best []byte
+	for sym := 0; sym < symmetries; sym++ {
+		transform := func(c bsgf.MoveCoord) bsgf.MoveCoord {
+			return symmetry(sym, c, width, height)
+		}
+		var b bytes.Buffer
+		writeSnake(&b, &state.You, transform, withHealth)
+		var enemies [][]byte
+		for i := range state.Board.Snakes {
+			s := &state.Board.Snakes[i]
+			if s.ID == state.You.ID {
+				continue
+			}
+			var e bytes.Buffer
+			writeSnake(&e, s, transform, withHealth)
+			enemies = append(enemies, e.Bytes())
+		}
+		sort.Slice(enemies, func(i, j int) bool { return bytes.Compare(enemies[i], enemies[j]) < 0 })
+		for _, e := range enemies {
+			b.Write(e)
+		}
+		writeCoordSet(&b, state.Board.Food, transform)
+		writeCoordSet(&b, state.Board.Hazards, transform)
+		if best == nil || bytes.Compare(b.Bytes(), best) < 0 {
+			best = b.Bytes()
+		}
+	}
+	sum := sha256.Sum256(best)
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// symmetry applies one of the 8 symmetries of the board to c. The last 4
+// swap x and y, so they only apply to square boards.
+func symmetry(sym int, c bsgf.MoveCoord, width, height int32) bsgf.MoveCoord {
+	x, y := c.X, c.Y
+	if sym >= 4 {
+		x, y = y, x
+	}
+	if sym&1 != 0 {
+		x = width - 1 - x
+	}
+	if sym&2 != 0 {
+		y = height - 1 - y
+	}
+	return bsgf.MoveCoord{X: x, Y: y}
+}
+
+func writeSnake(b *bytes.Buffer, s *bsgf.MoveBattlesnake, transform func(bsgf.MoveCoord) bsgf.MoveCoord, withHealth bool) {
+	health := int32(0)
+	if withHealth {
+		health = s.Health
+	}
+	binary.Write(b, binary.BigEndian, [2]int32{int32(len(s.Body)), health})
+	for _, c := range s.Body {
+		binary.Write(b, binary.BigEndian, transform(c))
+	}
+}
+
+func writeCoordSet(b *bytes.Buffer, coords []bsgf.MoveCoord, transform func(bsgf.MoveCoord) bsgf.MoveCoord) {
+	moved := make([]bsgf.MoveCoord, len(coords))
+	for i, c := range coords {
+		moved[i] = transform(c)
+	}
+	sort.Slice(moved, func(i, j int) bool {
+		if moved[i].X != moved[j].X {
+			return moved[i].X < moved[j].X
+		}
+		return moved[i].Y < moved[j].Y
+	})
+	binary.Write(b, binary.BigEndian, int32(len(moved)))
+	for _, c := range moved {
+		binary.Write(b, binary.BigEndian, c)
+	}
+}
+
+type SamplerOptions struct {
+	// Phases are the last turn of each game phase, so {25, 100} makes
+	// turns 0-25, 26-100 and everything after three phases. Without phases
+	// every sample is in one phase.
+	Phases []int32
+	// PerPhase caps the samples kept from each phase. 0 keeps as many from
+	// every phase as there are in the smallest one.
+	PerPhase int
+	// WithHealth keeps positions that only differ by health apart, see
+	// PositionKey
+	WithHealth bool
+	// Seed changes which samples are kept when a phase is cut down
+	Seed string
+}
+
+// Sampler drops duplicate positions and balances samples across the phases
+// of the game, so the openings that repeat in nearly every game don't
+// drown out the rest. The first sample added for a position is kept.
+type Sampler struct {
+	opts       SamplerOptions
+	seen       map[uint64]bool
+	phases     [][]keyedSample
+	duplicates int
+}
+
+type keyedSample struct {
+	Sample
+	key   uint64
+	order int
+}
+
+func NewSampler(opts SamplerOptions) *Sampler {
+	return &Sampler{
+		opts:   opts,
+		seen:   make(map[uint64]bool),
+		phases: make([][]keyedSample, len(opts.Phases)+1),
+	}
+}
+
+// Add offers samples to the sampler, returning how many weren't duplicates
+func (s *Sampler) Add(samples ...Sample) int {
+	added := 0
+	for _, sample := range samples {
+		key := PositionKey(sample.State, s.opts.WithHealth)
+		if s.seen[key] {
+			s.duplicates++
+			continue
+		}
+		s.seen[key] = true
+		phase := len(s.opts.Phases)
+		for i, last := range s.opts.Phases {
+			if sample.Turn <= last {
+				phase = i
+				break
+			}
+		}
+		s.phases[phase] = append(s.phases[phase], keyedSample{Sample: sample, key: key, order: len(s.phases[phase])})
+		added++
+	}
+	return added
+}
+
+// Duplicates is the number of samples dropped for repeating a position
+func (s *Sampler) Duplicates() int {
+	return s.duplicates
+}
+
+// Samples returns the balanced samples, phase by phase. Which samples are
+// kept from a phase that's cut down only depends on the positions and the
+// seed, not on the order they were added in.
+func (s *Sampler) Samples() []Sample {
+	limit := s.opts.PerPhase
+	if limit <= 0 {
+		limit = -1
+		for _, phase := range s.phases {
+			if len(phase) > 0 && (limit < 0 || len(phase) < limit) {
+				limit = len(phase)
+			}
+		}
+	}
+	var out []Sample
+	for _, phase := range s.phases {
+		kept := phase
+		if len(phase) > limit {
+			kept = append([]keyedSample(nil), phase...)
+			rank := func(k keyedSample) [sha256.Size]byte {
+				var b [8]byte
+				binary.BigEndian.PutUint64(b[:], k.key)
+				return sha256.Sum256(append([]byte(s.opts.Seed+"/"), b[:]...))
+			}
+			sort.Slice(kept, func(i, j int) bool {
+				ri, rj := rank(kept[i]), rank(kept[j])
+				return bytes.Compare(ri[:], rj[:]) < 0
+			})
+			kept = kept[:limit]
+			// back to the order they were added in
+			sort.Slice(kept, func(i, j int) bool { return kept[i].order < kept[j].order })
+		}
+		for _, k := range kept {
+			out = append(out, k.Sample)
+		}
+	}
+	return out
+}
@@ -0,0 +1,296 @@
+package dataset
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ShardSchemaVersion is the version of the manifest and shard layout
+// written by ShardWriter
+const ShardSchemaVersion = 1
+
+// ErrBadShard is returned when a shard doesn't match its manifest entry
+var ErrBadShard = errors.New("shard doesn't match manifest")
+
+// ShardInfo describes one shard in a manifest
+type ShardInfo struct {
+	File    string `json:"file"`
+	Records int    `json:"records"`
+	Bytes   int64  `json:"bytes"`
+	SHA256  string `json:"sha256"`
+}
+
+// ShardManifest lists the shards of a dataset written by ShardWriter, in
+// order. Shard files are relative to the manifest.
+type ShardManifest struct {
+	SchemaVersion int `json:"schemaVersion"`
+	// Kind says what the records are, like "samples" or "labels"
+	Kind    string      `json:"kind,omitempty"`
+	Records int         `json:"records"`
+	Bytes   int64       `json:"bytes"`
+	Shards  []ShardInfo `json:"shards"`
+}
+
+// ShardWriter writes records as json lines, split into shards of at most
+// MaxBytes each, named prefix-00000.jsonl, prefix-00001.jsonl and so on.
+// Close writes prefix.manifest.json with the count, size and sha256 of every
+// shard. A record bigger than MaxBytes gets a shard of its own.
+type ShardWriter struct {
+	Dir      string
+	Prefix   string
+	Kind     string
+	MaxBytes int64
+
+	manifest ShardManifest
+	f        *os.File
+	w        *bufio.Writer
+	sum      hash.Hash
+	current  ShardInfo
+}
+
+func NewShardWriter(dir, prefix, kind string, maxBytes int64) *ShardWriter {
+	return &ShardWriter{Dir: dir, Prefix: prefix, Kind: kind, MaxBytes: maxBytes}
+}
+
+// Write adds one record, starting a new shard when it wouldn't fit in the
+// current one
+func (w *ShardWriter) Write(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error marshalling record: %w", err)
+	}
+	line = append(line, '\n')
+	if w.f == nil || (w.MaxBytes > 0 && w.current.Records > 0 && w.current.Bytes+int64(len(line)) > w.MaxBytes) {
+		err = w.nextShard()
+		if err != nil {
+			return err
+		}
+	}
+	_, err = w.w.Write(line)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", w.f.Name(), err)
+	}
+	w.sum.Write(line)
+	w.current.Records++
+	w.current.Bytes += int64(len(line))
+	return nil
+}
+
+func (w *ShardWriter) nextShard() error {
+	err := w.closeShard()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(w.Dir, 0755)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%05d.jsonl", w.Prefix, len(w.manifest.Shards))
+	w.f, err = os.Create(filepath.Join(w.Dir, name))
+	if err != nil {
+		return err
+	}
+	w.w = bufio.NewWriter(w.f)
+	w.sum = sha256.New()
+	w.current = ShardInfo{File: name}
+	return nil
+}
+
+func (w *ShardWriter) closeShard() error {
+	if w.f == nil {
+		return nil
+	}
+	err := w.w.Flush()
+	if err != nil {
+		w.f.Close()
+		return fmt.Errorf("error writing %s: %w", w.f.Name(), err)
+	}
+	err = w.f.Close()
+	w.f = nil
+	if err != nil {
+		return err
+	}
+	w.current.SHA256 = hex.EncodeToString(w.sum.Sum(nil))
+	w.manifest.Shards = append(w.manifest.Shards, w.current)
+	w.manifest.Records += w.current.Records
+	w.manifest.Bytes += w.current.Bytes
+	return nil
+}
+
+// ManifestPath is where Close writes the manifest
+func (w *ShardWriter) ManifestPath() string {
+	return filepath.Join(w.Dir, w.Prefix+".manifest.json")
+}
+
+// Close finishes the last shard and writes the manifest, returning it
+func (w *ShardWriter) Close() (*ShardManifest, error) {
+	err := w.closeShard()
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(w.Dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	m := w.manifest
+	m.SchemaVersion = ShardSchemaVersion
+	m.Kind = w.Kind
+	if m.Shards == nil {
+		m.Shards = []ShardInfo{}
+	}
+	data, err := json.MarshalIndent(&m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling manifest: %w", err)
+	}
+	err = ioutil.WriteFile(w.ManifestPath(), append(data, '\n'), 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ReadShardManifest loads a manifest written by ShardWriter
+func ReadShardManifest(path string) (*ShardManifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m ShardManifest
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+	if m.SchemaVersion != ShardSchemaVersion {
+		return nil, fmt.Errorf("unsupported shard schema version %d", m.SchemaVersion)
+	}
+	return &m, nil
+}
+
+// ShardReader streams the records of a sharded dataset back in the order
+// they were written. Each shard is checked against the manifest once it's
+// been read to the end, so a corrupt or truncated shard is an ErrBadShard
+// error from Next, after its records have been returned.
+type ShardReader struct {
+	Manifest *ShardManifest
+
+	dir     string
+	shard   int
+	f       *os.File
+	r       *bufio.Reader
+	sum     hash.Hash
+	records int
+	bytes   int64
+}
+
+// OpenShards reads the manifest at path, ready to stream its shards
+func OpenShards(path string) (*ShardReader, error) {
+	m, err := ReadShardManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ShardReader{Manifest: m, dir: filepath.Dir(path), shard: -1}, nil
+}
+
+// Next decodes the next record into v, returning io.EOF after the last one
+func (r *ShardReader) Next(v interface{}) error {
+	for {
+		if r.f == nil {
+			if r.shard+1 >= len(r.Manifest.Shards) {
+				return io.EOF
+			}
+			r.shard++
+			f, err := os.Open(filepath.Join(r.dir, r.Manifest.Shards[r.shard].File))
+			if err != nil {
+				return err
+			}
+			r.f = f
+			r.sum = sha256.New()
+			r.r = bufio.NewReader(io.TeeReader(f, r.sum))
+			r.records, r.bytes = 0, 0
+		}
+		line, err := r.r.ReadBytes('\n')
+		r.bytes += int64(len(line))
+		if err == io.EOF {
+			if len(line) > 0 {
+				r.f.Close()
+				r.f = nil
+				return fmt.Errorf("%w: %s ends without a newline", ErrBadShard, r.Manifest.Shards[r.shard].File)
+			}
+			err = r.finishShard()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", r.Manifest.Shards[r.shard].File, err)
+		}
+		r.records++
+		err = json.Unmarshal(line, v)
+		if err != nil {
+			return fmt.Errorf("error decoding record %d of %s: %w", r.records, r.Manifest.Shards[r.shard].File, err)
+		}
+		return nil
+	}
+}
+
+func (r *ShardReader) finishShard() error {
+	info := r.Manifest.Shards[r.shard]
+	r.f.Close()
+	r.f = nil
+	if r.records != info.Records || r.bytes != info.Bytes {
+		return fmt.Errorf("%w: %s has %d records in %d bytes, expected %d in %d", ErrBadShard, info.File, r.records, r.bytes, info.Records, info.Bytes)
+	}
+	if sum := hex.EncodeToString(r.sum.Sum(nil)); sum != info.SHA256 {
+		return fmt.Errorf("%w: %s has sha256 %s, expected %s", ErrBadShard, info.File, sum, info.SHA256)
+	}
+	return nil
+}
+
+// Close closes the shard being read, if any
+func (r *ShardReader) Close() error {
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// VerifyShards checks every shard listed in the manifest at path without
+// decoding the records
+func VerifyShards(path string) error {
+	m, err := ReadShardManifest(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	for _, info := range m.Shards {
+		f, err := os.Open(filepath.Join(dir, info.File))
+		if err != nil {
+			return err
+		}
+		sum := sha256.New()
+		n, err := io.Copy(sum, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", info.File, err)
+		}
+		if n != info.Bytes {
+			return fmt.Errorf("%w: %s is %d bytes, expected %d", ErrBadShard, info.File, n, info.Bytes)
+		}
+		if s := hex.EncodeToString(sum.Sum(nil)); s != info.SHA256 {
+			return fmt.Errorf("%w: %s has sha256 %s, expected %s", ErrBadShard, info.File, s, info.SHA256)
+		}
+	}
+	return nil
+}
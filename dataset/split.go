@@ -0,0 +1,136 @@
+package dataset
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+// Split is one part of a dataset
+type Split string
+
+const (
+	SplitTrain Split = "train"
+	SplitVal   Split = "val"
+	SplitTest  Split = "test"
+)
+
+// Splits lists every split in the order they're written
+var Splits = []Split{SplitTrain, SplitVal, SplitTest}
+
+type SplitOptions struct {
+	// Val and Test are the fractions of games in the validation and test
+	// splits, the rest go to train
+	Val, Test float64
+	// Seed changes which games land in which split. Keep it fixed for
+	// reproducible experiments.
+	Seed string
+}
+
+// SplitOf picks the split for a game from the hash of its ID, so every
+// position from one game ends up in the same split and a game stays in its
+// split as the corpus grows. The first 8 bytes of sha256(seed + "/" + id),
+// as a big endian fraction of 2^64, are compared against the cumulative
+// fractions test, then val.
+func SplitOf(gameId string, opts SplitOptions) Split {
+	sum := sha256.Sum256([]byte(opts.Seed + "/" + gameId))
+	x := float64(binary.BigEndian.Uint64(sum[:8])) / math.Exp2(64)
+	switch {
+	case x < opts.Test:
+		return SplitTest
+	case x < opts.Test+opts.Val:
+		return SplitVal
+	}
+	return SplitTrain
+}
+
+// Manifest records which games are in each split and how they were picked
+type Manifest struct {
+	Seed   string             `json:"seed"`
+	Val    float64            `json:"val"`
+	Test   float64            `json:"test"`
+	Splits map[Split][]string `json:"splits"`
+}
+
+// BuildSplits assigns each game in ids to a split. IDs are sorted and
+// duplicates dropped, so the manifest only depends on the set of games.
+func BuildSplits(ids []string, opts SplitOptions) (*Manifest, error) {
+	if opts.Val < 0 || opts.Test < 0 || opts.Val+opts.Test > 1 {
+		return nil, fmt.Errorf("invalid split fractions val %g and test %g", opts.Val, opts.Test)
+	}
+	m := &Manifest{Seed: opts.Seed, Val: opts.Val, Test: opts.Test, Splits: make(map[Split][]string)}
+	for _, split := range Splits {
+		m.Splits[split] = []string{}
+	}
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	for i, id := range sorted {
+		if i > 0 && id == sorted[i-1] {
+			continue
+		}
+		split := SplitOf(id, opts)
+		m.Splits[split] = append(m.Splits[split], id)
+	}
+	return m, nil
+}
+
+// BuildStoreSplits splits every game in s
+func BuildStoreSplits(s store.Store, opts SplitOptions) (*Manifest, error) {
+	ids, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	return BuildSplits(ids, opts)
+}
+
+// Write saves the manifest in dir as manifest.json, along with train.txt,
+// val.txt and test.txt listing one game ID per line
+func (m *Manifest) Write(dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling manifest: %w", err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "manifest.json"), append(data, '\n'), 0644)
+	if err != nil {
+		return err
+	}
+	for _, split := range Splits {
+		var b strings.Builder
+		for _, id := range m.Splits[split] {
+			b.WriteString(id)
+			b.WriteByte('\n')
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, string(split)+".txt"), []byte(b.String()), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadManifest loads a manifest saved by Write
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,241 @@
+package dataset
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Transition is one step of a snake's trajectory, from turn to turn+1
+type Transition struct {
+	GameID  string
+	SnakeID string
+	Turn    int32
+	State   *Planes
+	Action  bsgf.Direction
+	Reward  float32
+	// Next is nil when the snake was eliminated by this move
+	Next *Planes
+	// Done is true on the snake's last move, whether it died or the game
+	// ended
+	Done bool
+}
+
+// RewardFunc scores the move snakeId made from prev to next. next is the
+// snake's last frame when done is true.
+type RewardFunc func(game *bsgf.ViewGame, prev, next *bsgf.ViewFrame, snakeId string, done bool) float32
+
+// DefaultReward is 1 for the winner's last move, -1 for a move that gets
+// the snake eliminated and 0 otherwise
+func DefaultReward(game *bsgf.ViewGame, prev, next *bsgf.ViewFrame, snakeId string, done bool) float32 {
+	if !done {
+		return 0
+	}
+	for _, s := range next.Snakes {
+		if s.ID == snakeId && s.Death.Eliminated() {
+			return -1
+		}
+	}
+	if game.Winner() == snakeId {
+		return 1
+	}
+	return 0
+}
+
+type TrajectoryOptions struct {
+	// SnakeID limits the trajectories to one snake, otherwise every snake
+	// gets one
+	SnakeID string
+	// Size pads the planes, see PlaneOptions
+	Size int32
+	// Reward defaults to DefaultReward
+	Reward RewardFunc
+}
+
+// Trajectories lists the transitions of every snake in game (or
+// opts.SnakeID), one snake after another, in turn order
+func Trajectories(game *bsgf.ViewGame, opts TrajectoryOptions) ([]Transition, error) {
+	reward := opts.Reward
+	if reward == nil {
+		reward = DefaultReward
+	}
+	planeOpts := PlaneOptions{Width: game.Game.Width, Height: game.Game.Height, Size: opts.Size}
+	if len(game.Frames) == 0 {
+		return nil, nil
+	}
+	var transitions []Transition
+	for _, snake := range game.Frames[0].Snakes {
+		if opts.SnakeID != "" && snake.ID != opts.SnakeID {
+			continue
+		}
+		var state *Planes
+		for i := 0; i+1 < len(game.Frames); i++ {
+			prev, next := &game.Frames[i], &game.Frames[i+1]
+			move, err := game.MoveAt(prev.Turn, snake.ID)
+			if err != nil {
+				// not alive on this turn
+				break
+			}
+			if state == nil {
+				state, err = EncodePlanes(prev, snake.ID, planeOpts)
+				if err != nil {
+					return nil, err
+				}
+			}
+			t := Transition{
+				GameID:  game.Game.ID,
+				SnakeID: snake.ID,
+				Turn:    prev.Turn,
+				State:   state,
+				Action:  move,
+				Done:    i+2 == len(game.Frames),
+			}
+			t.Next, err = EncodePlanes(next, snake.ID, planeOpts)
+			if err != nil {
+				t.Next = nil
+				t.Done = true
+			}
+			t.Reward = reward(game, prev, next, snake.ID, t.Done)
+			transitions = append(transitions, t)
+			if t.Done {
+				break
+			}
+			state = t.Next
+		}
+	}
+	return transitions, nil
+}
+
+// Trajectory shards start with an 8 byte magic and the plane shape as three
+// little endian uint32s (channels, height, width), followed by fixed size
+// records until the end of the file:
+//
+//	int32    turn
+//	uint8    action, the index in bsgf.Directions (up, down, left, right)
+//	uint8    done, 0 or 1
+//	2 bytes  padding
+//	float32  reward
+//	float32  state[channels*height*width]
+//	float32  next_state[channels*height*width], zeros when the snake died
+//
+// so with numpy a shard is np.fromfile(path, dtype, offset=20) for the
+// matching structured dtype. Everything is little endian.
+const trajectoryMagic = "BSTRAJ1\n"
+
+// TrajectoryWriter writes transitions into numbered shard files of at most
+// PerShard records each: prefix-00000.traj, prefix-00001.traj and so on.
+// Every transition must have the same plane shape.
+type TrajectoryWriter struct {
+	Dir      string
+	Prefix   string
+	PerShard int
+	// Shards lists the files written so far
+	Shards []string
+
+	shape  [3]int
+	count  int
+	f      *os.File
+	w      *bufio.Writer
+	record []byte
+}
+
+func NewTrajectoryWriter(dir, prefix string, perShard int) *TrajectoryWriter {
+	return &TrajectoryWriter{Dir: dir, Prefix: prefix, PerShard: perShard}
+}
+
+// Write adds a transition, starting a new shard when the current one is full
+func (w *TrajectoryWriter) Write(t *Transition) error {
+	shape := [3]int{t.State.Channels, t.State.Height, t.State.Width}
+	if w.shape == [3]int{} {
+		w.shape = shape
+	} else if shape != w.shape {
+		return fmt.Errorf("transition planes are %v but the shards are %v, set Size to pad boards to one shape", shape, w.shape)
+	}
+	if w.f == nil || (w.PerShard > 0 && w.count >= w.PerShard) {
+		err := w.nextShard()
+		if err != nil {
+			return err
+		}
+	}
+	action := uint8(math.MaxUint8)
+	for i, d := range bsgf.Directions {
+		if d == t.Action {
+			action = uint8(i)
+		}
+	}
+	size := shape[0] * shape[1] * shape[2]
+	if w.record == nil {
+		w.record = make([]byte, 12+8*size)
+	}
+	b := w.record
+	binary.LittleEndian.PutUint32(b[0:], uint32(t.Turn))
+	b[4] = action
+	b[5] = 0
+	if t.Done {
+		b[5] = 1
+	}
+	binary.LittleEndian.PutUint32(b[8:], math.Float32bits(t.Reward))
+	for i, v := range t.State.Data {
+		binary.LittleEndian.PutUint32(b[12+4*i:], math.Float32bits(v))
+	}
+	next := b[12+4*size:]
+	for i := 0; i < size; i++ {
+		var v float32
+		if t.Next != nil {
+			v = t.Next.Data[i]
+		}
+		binary.LittleEndian.PutUint32(next[4*i:], math.Float32bits(v))
+	}
+	_, err := w.w.Write(b)
+	if err != nil {
+		return fmt.Errorf("error writing transition: %w", err)
+	}
+	w.count++
+	return nil
+}
+
+func (w *TrajectoryWriter) nextShard() error {
+	err := w.closeShard()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(w.Dir, fmt.Sprintf("%s-%05d.traj", w.Prefix, len(w.Shards)))
+	w.f, err = os.Create(path)
+	if err != nil {
+		return err
+	}
+	w.Shards = append(w.Shards, path)
+	w.w = bufio.NewWriter(w.f)
+	w.count = 0
+	header := make([]byte, len(trajectoryMagic)+12)
+	copy(header, trajectoryMagic)
+	for i, n := range w.shape {
+		binary.LittleEndian.PutUint32(header[len(trajectoryMagic)+4*i:], uint32(n))
+	}
+	_, err = w.w.Write(header)
+	return err
+}
+
+func (w *TrajectoryWriter) closeShard() error {
+	if w.f == nil {
+		return nil
+	}
+	err := w.w.Flush()
+	if err != nil {
+		w.f.Close()
+		return fmt.Errorf("error writing %s: %w", w.f.Name(), err)
+	}
+	err = w.f.Close()
+	w.f = nil
+	return err
+}
+
+// Close finishes the last shard
+func (w *TrajectoryWriter) Close() error {
+	return w.closeShard()
+}
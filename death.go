@@ -0,0 +1,50 @@
+package battlesnakegameformat
+
+// DeathCause is the reason the engine gives for eliminating a snake.
+type DeathCause string
+
+// Death causes recorded by the engine
+const (
+	CauseNone           DeathCause = ""
+	CauseSnakeCollision DeathCause = "snake-collision"
+	CauseSelfCollision  DeathCause = "snake-self-collision"
+	CauseHeadCollision  DeathCause = "head-collision"
+	CauseOutOfHealth    DeathCause = "out-of-health"
+	CauseWallCollision  DeathCause = "wall-collision"
+	CauseHazard         DeathCause = "hazard"
+	// CauseUnknown is for games imported from sources that don't record
+	// why a snake was eliminated
+	CauseUnknown DeathCause = "unknown"
+)
+
+// IsKill is true when another snake caused the death, either by running
+// into its body or winning a head to head.
+func (c DeathCause) IsKill() bool {
+	return c == CauseSnakeCollision || c == CauseHeadCollision
+}
+
+// IsEnvironmental is true when the snake died without another snake being
+// involved: walls, starvation, hazards or running into itself.
+func (c DeathCause) IsEnvironmental() bool {
+	switch c {
+	case CauseSelfCollision, CauseOutOfHealth, CauseWallCollision, CauseHazard:
+		return true
+	}
+	return false
+}
+
+// Eliminated is true if the snake has died.
+func (d ViewDeath) Eliminated() bool {
+	return d.Cause != CauseNone
+}
+
+// IsKill is true when another snake eliminated this one.
+func (d ViewDeath) IsKill() bool {
+	return d.Cause.IsKill()
+}
+
+// IsEnvironmental is true when the snake died without another snake being
+// involved.
+func (d ViewDeath) IsEnvironmental() bool {
+	return d.Cause.IsEnvironmental()
+}
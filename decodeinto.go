@@ -0,0 +1,108 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Buffers for uncompressed game json, shared by DecodeInto calls
+var archiveBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// DecodeInto uncompresses data into game, reusing the frame, snake and coord
+// slices game already holds. Anything game held before is overwritten.
+// Decoding many games into the same ViewGame avoids most allocations, but
+// slices from an earlier game must not be kept once it's reused.
+func DecodeInto(data []byte, game *ViewGame) error {
+	start := time.Now()
+	err := decodeInto(data, game)
+	if err != nil {
+		reportDecode(DecodeFormatArchive, start, len(data), nil, err)
+	} else {
+		reportDecode(DecodeFormatArchive, start, len(data), game, nil)
+	}
+	return err
+}
+
+func decodeInto(data []byte, game *ViewGame) error {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	buf := archiveBuffers.Get().(*bytes.Buffer)
+	defer archiveBuffers.Put(buf)
+	buf.Reset()
+	if isChunkedZip(r) {
+		return decodeChunkedInto(r, game, buf)
+	}
+	err = readArchiveInto(r, buf)
+	if err != nil {
+		return err
+	}
+	game.reset()
+	err = unmarshalJSON(buf.Bytes(), game)
+	if err != nil {
+		return fmt.Errorf("%w: error unmarshalling compressed game: %w", ErrCorruptArchive, err)
+	}
+	Intern(game)
+	return nil
+}
+
+// decodeChunkedInto is decodeInto for the chunked layout, decoding each
+// chunk into the frames game has room for past the ones already read
+func decodeChunkedInto(r *zip.Reader, game *ViewGame, buf *bytes.Buffer) error {
+	manifest, files, err := chunkManifest(r)
+	if err != nil {
+		return err
+	}
+	game.reset()
+	game.Game = manifest.Game
+	game.LastTurn = manifest.LastTurn
+	game.Events = append(game.Events, manifest.Events...)
+	for i := range manifest.Chunks {
+		entry := &manifest.Chunks[i]
+		buf.Reset()
+		err = readZipFileInto(files[entry.File], buf)
+		if err != nil {
+			return err
+		}
+		frames := game.Frames[len(game.Frames):]
+		err = decodeChunk(entry, buf.Bytes(), &frames)
+		if err != nil {
+			return err
+		}
+		game.Frames = append(game.Frames, frames...)
+	}
+	if len(game.Frames) > 0 {
+		// a copy, as the next decode into game fills FirstFrame and the
+		// frames separately
+		game.FirstFrame = *game.Frames[0].Clone()
+	}
+	Intern(game)
+	return nil
+}
+
+// reset zeroes game while keeping the capacity of its slices. The json
+// decoder reuses slice elements as they are, so elements past the length
+// are zeroed too.
+func (game *ViewGame) reset() {
+	frames := game.Frames[:cap(game.Frames)]
+	for i := range frames {
+		frames[i].Reset()
+	}
+	events := game.Events[:cap(game.Events)]
+	for i := range events {
+		events[i] = ViewEvent{}
+	}
+	first := game.FirstFrame
+	first.Reset()
+	*game = ViewGame{
+		Frames:     frames[:0],
+		FirstFrame: first,
+		Events:     events[:0],
+	}
+}
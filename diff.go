@@ -0,0 +1,328 @@
+package battlesnakegameformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Difference between two values at the same path in a game or frame
+type Difference struct {
+	Path string
+	A    string
+	B    string
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s != %s", d.Path, d.A, d.B)
+}
+
+type differ struct {
+	diffs []Difference
+}
+
+func (d *differ) add(path string, a, b interface{}) {
+	d.diffs = append(d.diffs, Difference{Path: path, A: fmt.Sprint(a), B: fmt.Sprint(b)})
+}
+
+// DiffGames compares settings, frames and events of two games, matching
+// frames by index. Food and hazards are compared without regard to order.
+func DiffGames(a, b *ViewGame) []Difference {
+	var d differ
+	d.settings("Game", &a.Game, &b.Game)
+	if a.LastTurn != b.LastTurn {
+		d.add("LastTurn", a.LastTurn, b.LastTurn)
+	}
+	d.frame("FirstFrame", &a.FirstFrame, &b.FirstFrame)
+	if len(a.Frames) != len(b.Frames) {
+		d.add("len(Frames)", len(a.Frames), len(b.Frames))
+	}
+	for i := 0; i < len(a.Frames) && i < len(b.Frames); i++ {
+		d.frame(fmt.Sprintf("Frames[%d]", i), &a.Frames[i], &b.Frames[i])
+	}
+	if len(a.Events) != len(b.Events) {
+		d.add("len(Events)", len(a.Events), len(b.Events))
+	}
+	for i := 0; i < len(a.Events) && i < len(b.Events); i++ {
+		if a.Events[i] != b.Events[i] {
+			d.add(fmt.Sprintf("Events[%d]", i), a.Events[i], b.Events[i])
+		}
+	}
+	return d.diffs
+}
+
+// DiffFrames compares two frames, matching snakes by ID
+func DiffFrames(a, b *ViewFrame) []Difference {
+	var d differ
+	d.frame("Frame", a, b)
+	return d.diffs
+}
+
+// DiffMoveStates compares two move requests, matching snakes by ID
+func DiffMoveStates(a, b *MoveGameState) []Difference {
+	var d differ
+	d.moveState("State", a, b)
+	return d.diffs
+}
+
+// Equal reports whether game and o have the same settings, frames and events. The
+// order of food and hazards within a frame is ignored.
+func (game *ViewGame) Equal(o *ViewGame) bool {
+	return len(DiffGames(game, o)) == 0
+}
+
+// Equal reports whether frame and o hold the same board. The order of
+// snakes, food and hazards is ignored.
+func (frame *ViewFrame) Equal(o *ViewFrame) bool {
+	return len(DiffFrames(frame, o)) == 0
+}
+
+// Equal reports whether state and o describe the same move request. The
+// order of snakes, food and hazards is ignored.
+func (state *MoveGameState) Equal(o *MoveGameState) bool {
+	return len(DiffMoveStates(state, o)) == 0
+}
+
+func (d *differ) settings(path string, a, b *ViewGameSettings) {
+	if a.ID != b.ID {
+		d.add(path+".ID", a.ID, b.ID)
+	}
+	d.ruleset(path+".Ruleset", &a.Ruleset, &b.Ruleset)
+	if a.Timeout != b.Timeout {
+		d.add(path+".Timeout", a.Timeout, b.Timeout)
+	}
+	if a.Status != b.Status {
+		d.add(path+".Status", a.Status, b.Status)
+	}
+	if a.Width != b.Width || a.Height != b.Height {
+		d.add(path+".Size", fmt.Sprintf("%dx%d", a.Width, a.Height), fmt.Sprintf("%dx%d", b.Width, b.Height))
+	}
+}
+
+func (d *differ) ruleset(path string, a, b *ViewRuleset) {
+	fields := []struct {
+		name string
+		a, b interface{}
+	}{
+		{"Name", a.Name, b.Name},
+		{"Map", a.Map, b.Map},
+		{"MapAuthor", a.MapAuthor, b.MapAuthor},
+		{"FoodSpawnChance", a.FoodSpawnChance, b.FoodSpawnChance},
+		{"MinimumFood", a.MinimumFood, b.MinimumFood},
+		{"DamagePerTurn", a.DamagePerTurn, b.DamagePerTurn},
+	}
+	for _, f := range fields {
+		if f.a != f.b {
+			d.add(path+"."+f.name, f.a, f.b)
+		}
+	}
+	d.extraSettings(path+".Settings", a.Settings, b.Settings)
+}
+
+// extraSettings compares untyped settings by key, ignoring json formatting
+func (d *differ) extraSettings(path string, a, b map[string]json.RawMessage) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		va, oka := a[k]
+		vb, okb := b[k]
+		switch {
+		case !oka:
+			d.add(path+"["+k+"]", "missing", string(vb))
+		case !okb:
+			d.add(path+"["+k+"]", string(va), "missing")
+		case !jsonEqual(va, vb):
+			d.add(path+"["+k+"]", string(va), string(vb))
+		}
+	}
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func (d *differ) frame(path string, a, b *ViewFrame) {
+	if a.Turn != b.Turn {
+		d.add(path+".Turn", a.Turn, b.Turn)
+	}
+	d.coordSet(path+".Food", a.Food, b.Food)
+	d.coordSet(path+".Hazards", a.Hazards, b.Hazards)
+	if a.HazardDamage != b.HazardDamage {
+		d.add(path+".HazardDamage", a.HazardDamage, b.HazardDamage)
+	}
+	for i := range a.Snakes {
+		sa := &a.Snakes[i]
+		sb, ok := findSnake(b, sa.ID)
+		if !ok {
+			d.add(fmt.Sprintf("%s.Snakes[%s]", path, sa.ID), "present", "missing")
+			continue
+		}
+		d.snake(fmt.Sprintf("%s.Snakes[%s]", path, sa.ID), sa, sb)
+	}
+	for i := range b.Snakes {
+		if _, ok := findSnake(a, b.Snakes[i].ID); !ok {
+			d.add(fmt.Sprintf("%s.Snakes[%s]", path, b.Snakes[i].ID), "missing", "present")
+		}
+	}
+}
+
+func (d *differ) snake(path string, a, b *ViewSnake) {
+	d.coords(path+".Body", a.Body, b.Body)
+	if a.Health != b.Health {
+		d.add(path+".Health", a.Health, b.Health)
+	}
+	if a.Death != b.Death {
+		d.add(path+".Death", a.Death, b.Death)
+	}
+	fields := []struct {
+		name string
+		a, b string
+	}{
+		{"Name", a.Name, b.Name},
+		{"URL", a.URL, b.URL},
+		{"Color", a.Color, b.Color},
+		{"HeadType", a.HeadType, b.HeadType},
+		{"TailType", a.TailType, b.TailType},
+		{"Latency", a.Latency, b.Latency},
+		{"Shout", a.Shout, b.Shout},
+		{"Squad", a.Squad, b.Squad},
+		{"APIVersion", a.APIVersion, b.APIVersion},
+		{"Author", a.Author, b.Author},
+	}
+	for _, f := range fields {
+		if f.a != f.b {
+			d.add(path+"."+f.name, f.a, f.b)
+		}
+	}
+}
+
+func (d *differ) coords(path string, a, b []ViewCoord) {
+	if len(a) != len(b) {
+		d.add(path, a, b)
+		return
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			d.add(path, a, b)
+			return
+		}
+	}
+}
+
+// coordSet compares coords ignoring order. Duplicates still count, since
+// stacked hazards do more damage.
+func (d *differ) coordSet(path string, a, b []ViewCoord) {
+	if len(a) != len(b) {
+		d.add(path, a, b)
+		return
+	}
+	counts := make(map[ViewCoord]int, len(a))
+	for _, c := range a {
+		counts[c]++
+	}
+	for _, c := range b {
+		counts[c]--
+		if counts[c] < 0 {
+			d.add(path, a, b)
+			return
+		}
+	}
+}
+
+func (d *differ) moveState(path string, a, b *MoveGameState) {
+	if a.Game.ID != b.Game.ID {
+		d.add(path+".Game.ID", a.Game.ID, b.Game.ID)
+	}
+	if a.Game.Ruleset.Name != b.Game.Ruleset.Name {
+		d.add(path+".Game.Ruleset.Name", a.Game.Ruleset.Name, b.Game.Ruleset.Name)
+	}
+	if a.Game.Ruleset.Version != b.Game.Ruleset.Version {
+		d.add(path+".Game.Ruleset.Version", a.Game.Ruleset.Version, b.Game.Ruleset.Version)
+	}
+	sa, sb := a.Game.Ruleset.Settings, b.Game.Ruleset.Settings
+	sa.Extra, sb.Extra = nil, nil
+	if !reflect.DeepEqual(sa, sb) {
+		d.add(path+".Game.Ruleset.Settings", sa, sb)
+	}
+	d.extraSettings(path+".Game.Ruleset.Settings.Extra", a.Game.Ruleset.Settings.Extra, b.Game.Ruleset.Settings.Extra)
+	if a.Game.Timeout != b.Game.Timeout {
+		d.add(path+".Game.Timeout", a.Game.Timeout, b.Game.Timeout)
+	}
+	if a.Turn != b.Turn {
+		d.add(path+".Turn", a.Turn, b.Turn)
+	}
+	if a.Board.Width != b.Board.Width || a.Board.Height != b.Board.Height {
+		d.add(path+".Board.Size", fmt.Sprintf("%dx%d", a.Board.Width, a.Board.Height), fmt.Sprintf("%dx%d", b.Board.Width, b.Board.Height))
+	}
+	d.coordSet(path+".Board.Food", viewCoords(a.Board.Food), viewCoords(b.Board.Food))
+	d.coordSet(path+".Board.Hazards", viewCoords(a.Board.Hazards), viewCoords(b.Board.Hazards))
+	for i := range a.Board.Snakes {
+		sa := &a.Board.Snakes[i]
+		sb, ok := findMoveSnake(b.Board.Snakes, sa.ID)
+		if !ok {
+			d.add(fmt.Sprintf("%s.Board.Snakes[%s]", path, sa.ID), "present", "missing")
+			continue
+		}
+		d.moveSnake(fmt.Sprintf("%s.Board.Snakes[%s]", path, sa.ID), sa, sb)
+	}
+	for i := range b.Board.Snakes {
+		if _, ok := findMoveSnake(a.Board.Snakes, b.Board.Snakes[i].ID); !ok {
+			d.add(fmt.Sprintf("%s.Board.Snakes[%s]", path, b.Board.Snakes[i].ID), "missing", "present")
+		}
+	}
+	d.moveSnake(path+".You", &a.You, &b.You)
+}
+
+func (d *differ) moveSnake(path string, a, b *MoveBattlesnake) {
+	d.coords(path+".Body", viewCoords(a.Body), viewCoords(b.Body))
+	fields := []struct {
+		name string
+		a, b interface{}
+	}{
+		{"ID", a.ID, b.ID},
+		{"Name", a.Name, b.Name},
+		{"Health", a.Health, b.Health},
+		{"Head", a.Head, b.Head},
+		{"Length", a.Length, b.Length},
+		{"Latency", a.Latency, b.Latency},
+		{"Shout", a.Shout, b.Shout},
+		{"Squad", a.Squad, b.Squad},
+		{"Customizations", a.Customizations, b.Customizations},
+	}
+	for _, f := range fields {
+		if f.a != f.b {
+			d.add(path+"."+f.name, f.a, f.b)
+		}
+	}
+}
+
+func findMoveSnake(snakes []MoveBattlesnake, snakeId string) (*MoveBattlesnake, bool) {
+	for i := range snakes {
+		if snakes[i].ID == snakeId {
+			return &snakes[i], true
+		}
+	}
+	return nil, false
+}
+
+func viewCoords(coords []MoveCoord) []ViewCoord {
+	result := make([]ViewCoord, len(coords))
+	for i, c := range coords {
+		result[i] = ViewCoord(c)
+	}
+	return result
+}
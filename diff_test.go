@@ -0,0 +1,36 @@
+package battlesnakegameformat_test
+
+import (
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func TestDiffGames(t *testing.T) {
+	game := testGame(t, bsgf.RulesetRoyale)
+	if diffs := bsgf.DiffGames(game, game.Clone()); len(diffs) > 0 {
+		t.Fatalf("a clone differs: %v", diffs)
+	}
+	changed := game.Clone()
+	changed.Frames[2].HazardDamage = 99
+	changed.Frames[4].Snakes[1].Health--
+	changed.Frames[5].Food = append(changed.Frames[5].Food, bsgf.ViewCoord{X: 0, Y: 0})
+	// food is compared without regard to order
+	food := changed.Frames[6].Food
+	for i, j := 0, len(food)-1; i < j; i, j = i+1, j-1 {
+		food[i], food[j] = food[j], food[i]
+	}
+	diffs := bsgf.DiffGames(game, changed)
+	paths := make(map[string]bool)
+	for _, d := range diffs {
+		paths[d.Path] = true
+	}
+	for _, path := range []string{"Frames[2].HazardDamage", "Frames[4].Snakes[" + game.Frames[4].Snakes[1].ID + "].Health"} {
+		if !paths[path] {
+			t.Errorf("expected a difference at %s, got %v", path, diffs)
+		}
+	}
+	if len(diffs) != 3 {
+		t.Errorf("expected 3 differences, got %v", diffs)
+	}
+}
@@ -0,0 +1,79 @@
+package battlesnakegameformat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Direction is a move a snake can make, using the strings the move API
+// expects.
+type Direction string
+
+const (
+	Up    Direction = "up"
+	Down  Direction = "down"
+	Left  Direction = "left"
+	Right Direction = "right"
+)
+
+// Directions lists every valid move
+var Directions = []Direction{Up, Down, Left, Right}
+
+// ParseDirection reads a move as sent by a snake. Case and surrounding
+// whitespace are ignored.
+func ParseDirection(s string) (Direction, error) {
+	d := Direction(strings.ToLower(strings.TrimSpace(s)))
+	switch d {
+	case Up, Down, Left, Right:
+		return d, nil
+	}
+	return "", fmt.Errorf("invalid move %q", s)
+}
+
+// Delta is the change in x and y from one step in direction d. Up is
+// positive y, matching the move API's coordinates.
+func (d Direction) Delta() (int32, int32) {
+	switch d {
+	case Up:
+		return 0, 1
+	case Down:
+		return 0, -1
+	case Left:
+		return -1, 0
+	case Right:
+		return 1, 0
+	}
+	return 0, 0
+}
+
+// Apply returns the coordinate one step from c in direction d. It doesn't
+// check the board bounds.
+func (d Direction) Apply(c ViewCoord) ViewCoord {
+	dx, dy := d.Delta()
+	return ViewCoord{X: c.X + dx, Y: c.Y + dy}
+}
+
+// Opposite returns the direction that undoes d.
+func (d Direction) Opposite() Direction {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	case Right:
+		return Left
+	}
+	return d
+}
+
+// Response builds a move response for d.
+func (d Direction) Response() *MoveBattlesnakeResponse {
+	return &MoveBattlesnakeResponse{Move: string(d)}
+}
+
+// Direction parses the move in r.
+func (r *MoveBattlesnakeResponse) Direction() (Direction, error) {
+	return ParseDirection(r.Move)
+}
@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+const DefaultBaseURL = "https://engine.battlesnake.com"
+
+// Number of frames requested per page from the frames endpoint
+const frameLimit = 100
+
+// Client fetches games from the battlesnake engine API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Retries is how many times a failed request is retried. Requests are
+	// retried on network errors, 429 and 5xx responses.
+	Retries int
+	// Backoff is the delay before the first retry, doubled on each attempt
+	Backoff time.Duration
+	// Strict rejects responses with fields the bsgf structs don't know about
+	// or that are missing fields they expect, see bsgf.UnmarshalStrict
+	Strict bool
+	// Local marks the games Record returns as played on a locally run
+	// engine, see MarkLocal. Builds are the build identifiers of the snakes
+	// by name.
+	Local  bool
+	Builds map[string]string
+}
+
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		Retries:    3,
+		Backoff:    500 * time.Millisecond,
+	}
+}
+
+// Game downloads the settings and every frame for a game
+func (c *Client) Game(ctx context.Context, id string) (*bsgf.ViewGame, error) {
+	var resp bsgf.ViewGameResponse
+	err := c.getJSON(ctx, "/games/"+url.PathEscape(id), &resp)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := c.Frames(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	game := &bsgf.ViewGame{
+		Game:   resp.Game,
+		Frames: frames,
+	}
+	if len(frames) > 0 {
+		game.FirstFrame = frames[0]
+		game.LastTurn = frames[len(frames)-1].Turn
+	}
+	bsgf.Intern(game)
+	return game, nil
+}
+
+// Frames downloads every frame for a game, one page at a time
+func (c *Client) Frames(ctx context.Context, id string) ([]bsgf.ViewFrame, error) {
+	var frames []bsgf.ViewFrame
+	for {
+		var page bsgf.ViewTurn
+		path := fmt.Sprintf("/games/%s/frames?offset=%d&limit=%d", url.PathEscape(id), len(frames), frameLimit)
+		err := c.getJSON(ctx, path, &page)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, page.Frames...)
+		if len(page.Frames) < frameLimit {
+			return frames, nil
+		}
+	}
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, v interface{}) error {
+	backoff := c.Backoff
+	var err error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		var retry bool
+		stats := bsgf.FetchStats{Path: path, Attempt: attempt}
+		start := time.Now()
+		retry, err = c.tryGetJSON(ctx, path, v, &stats)
+		if h := bsgf.CurrentHooks(); h.OnFetch != nil {
+			stats.Duration = time.Since(start)
+			stats.Err = err
+			h.OnFetch(stats)
+		}
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// tryGetJSON makes one request, filling in the status and size in stats
+func (c *Client) tryGetJSON(ctx context.Context, path string, v interface{}, stats *bsgf.FetchStats) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.BaseURL, "/")+path, nil)
+	if err != nil {
+		return false, fmt.Errorf("error creating engine request: %w", err)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("error requesting %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	stats.StatusCode = resp.StatusCode
+	in := &countingReader{r: resp.Body, n: &stats.Bytes}
+	if resp.StatusCode != http.StatusOK {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("unexpected status for %s: %s", path, resp.Status)
+	}
+	if c.Strict {
+		body, err := ioutil.ReadAll(in)
+		if err != nil {
+			return true, fmt.Errorf("error reading response for %s: %w", path, err)
+		}
+		err = bsgf.UnmarshalStrict(body, v)
+		if err != nil {
+			return false, fmt.Errorf("error decoding response for %s: %w", path, err)
+		}
+		return false, nil
+	}
+	err = json.NewDecoder(in).Decode(v)
+	if err != nil {
+		return true, fmt.Errorf("error decoding response for %s: %w", path, err)
+	}
+	return false, nil
+}
+
+// countingReader adds the number of bytes read to n
+type countingReader struct {
+	r io.Reader
+	n *int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	*c.n += n
+	return n, err
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// FrameFunc receives frames as they're recorded, the same as the onFrame
+// argument of Record
+type FrameFunc func(settings *bsgf.ViewGameSettings, frame *bsgf.ViewFrame)
+
+type delayedFrame struct {
+	due      time.Time
+	settings bsgf.ViewGameSettings
+	frame    *bsgf.ViewFrame
+}
+
+// DelayBuffer holds recorded frames back from spectators for a fixed time,
+// so tournament streams can keep an anti-sniping delay while Record still
+// stores every frame as it arrives:
+//
+//	d := engine.NewDelayBuffer(2*time.Minute, sendToOverlay)
+//	game, err := client.Record(ctx, id, d.Frame)
+//	d.Close()
+//
+// Frames are passed on in order, from one goroutine, each Delay after it
+// was given to Frame.
+type DelayBuffer struct {
+	Delay time.Duration
+
+	out     FrameFunc
+	mu      sync.Mutex
+	queue   []delayedFrame
+	wake    chan struct{}
+	closed  bool
+	stopped bool
+	done    chan struct{}
+}
+
+func NewDelayBuffer(delay time.Duration, out FrameFunc) *DelayBuffer {
+	d := &DelayBuffer{
+		Delay: delay,
+		out:   out,
+		wake:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Frame queues a copy of frame, to pass as the onFrame argument of Record.
+// It never blocks on the consumer.
+func (d *DelayBuffer) Frame(settings *bsgf.ViewGameSettings, frame *bsgf.ViewFrame) {
+	d.mu.Lock()
+	if !d.closed {
+		d.queue = append(d.queue, delayedFrame{due: time.Now().Add(d.Delay), settings: *settings, frame: frame.Clone()})
+	}
+	d.mu.Unlock()
+	d.signal()
+}
+
+// Pending is the number of frames waiting to be passed on
+func (d *DelayBuffer) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.queue)
+}
+
+// Close stops taking frames and waits until the ones already queued have
+// been passed on, each at its usual time, so spectators still see the end
+// of the game
+func (d *DelayBuffer) Close() {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	d.signal()
+	<-d.done
+}
+
+// Stop drops any queued frames and returns once the consumer isn't being
+// called anymore
+func (d *DelayBuffer) Stop() {
+	d.mu.Lock()
+	d.closed = true
+	d.stopped = true
+	d.queue = nil
+	d.mu.Unlock()
+	d.signal()
+	<-d.done
+}
+
+func (d *DelayBuffer) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *DelayBuffer) run() {
+	defer close(d.done)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	for {
+		d.mu.Lock()
+		if d.stopped || (d.closed && len(d.queue) == 0) {
+			d.mu.Unlock()
+			return
+		}
+		var next *delayedFrame
+		wait := time.Duration(-1)
+		if len(d.queue) > 0 {
+			wait = time.Until(d.queue[0].due)
+			if wait <= 0 {
+				f := d.queue[0]
+				d.queue = d.queue[1:]
+				next = &f
+			}
+		}
+		d.mu.Unlock()
+
+		if next != nil {
+			d.out(&next.settings, next.frame)
+			continue
+		}
+		if wait < 0 {
+			<-d.wake
+			continue
+		}
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-d.wake:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Types of message on the engine's /games/{id}/events websocket
+const (
+	EventFrame       = "frame"
+	EventElimination = "elimination"
+	EventGameEnd     = "game_end"
+)
+
+// Event is a decoded message from the events websocket. Types other than
+// the ones above are returned with only Type and Data set.
+type Event struct {
+	Type string
+	Data json.RawMessage
+	// Frame is set for frame events
+	Frame *bsgf.ViewFrame
+	// Elimination is set for elimination events
+	Elimination *bsgf.ViewEvent
+}
+
+// Messages as they are sent
+type rawEvent struct {
+	Type string          `json:"Type"`
+	Data json.RawMessage `json:"Data"`
+}
+
+// ParseEvent decodes one websocket message, for callers that read the event
+// stream themselves instead of using Record.
+func ParseEvent(message []byte) (*Event, error) {
+	var raw rawEvent
+	err := json.Unmarshal(message, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling game event: %w", err)
+	}
+	e := &Event{Type: raw.Type, Data: raw.Data}
+	switch raw.Type {
+	case EventFrame:
+		var frame bsgf.ViewFrame
+		err = json.Unmarshal(raw.Data, &frame)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling frame event: %w", err)
+		}
+		e.Frame = &frame
+	case EventElimination:
+		var elimination bsgf.ViewEvent
+		err = json.Unmarshal(raw.Data, &elimination)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling elimination event: %w", err)
+		}
+		elimination.Type = bsgf.EventElimination
+		e.Elimination = &elimination
+	}
+	return e, nil
+}
+
+// EventGame builds a game from parsed events. Frames may arrive out of
+// order or more than once, the last copy of a turn wins.
+type EventGame struct {
+	settings bsgf.ViewGameSettings
+	frames   map[int32]bsgf.ViewFrame
+	events   []bsgf.ViewEvent
+	lastTurn int32
+	ended    bool
+}
+
+// NewEventGame starts a game with settings from the games endpoint
+func NewEventGame(settings bsgf.ViewGameSettings) *EventGame {
+	return &EventGame{settings: settings, frames: make(map[int32]bsgf.ViewFrame)}
+}
+
+// Add records e. Events of unknown types are ignored.
+func (g *EventGame) Add(e *Event) {
+	switch e.Type {
+	case EventFrame:
+		g.frames[e.Frame.Turn] = *e.Frame
+		if e.Frame.Turn > g.lastTurn {
+			g.lastTurn = e.Frame.Turn
+		}
+	case EventElimination:
+		g.events = append(g.events, *e.Elimination)
+	case EventGameEnd:
+		if !g.ended {
+			g.events = append(g.events, bsgf.ViewEvent{Type: bsgf.EventGameEnd, Turn: g.lastTurn})
+			g.ended = true
+		}
+	}
+}
+
+// Ended is true once a game end event has been added
+func (g *EventGame) Ended() bool {
+	return g.ended
+}
+
+// SetSettings replaces the game's settings, for example with a fresh copy
+// once the game has ended so Status is up to date
+func (g *EventGame) SetSettings(settings bsgf.ViewGameSettings) {
+	g.settings = settings
+}
+
+// Complete is true when every turn from 0 to the last one has a frame
+func (g *EventGame) Complete() bool {
+	for turn := int32(0); turn <= g.lastTurn; turn++ {
+		if _, ok := g.frames[turn]; !ok {
+			return false
+		}
+	}
+	return len(g.frames) > 0
+}
+
+// Game returns the frames and events so far as a game, frames in turn order
+func (g *EventGame) Game() *bsgf.ViewGame {
+	game := &bsgf.ViewGame{Game: g.settings, Frames: make([]bsgf.ViewFrame, 0, len(g.frames))}
+	for _, frame := range g.frames {
+		game.Frames = append(game.Frames, frame)
+	}
+	sort.Slice(game.Frames, func(i, j int) bool { return game.Frames[i].Turn < game.Frames[j].Turn })
+	if len(game.Frames) > 0 {
+		game.FirstFrame = game.Frames[0]
+		game.LastTurn = game.Frames[len(game.Frames)-1].Turn
+	}
+	game.Events = append([]bsgf.ViewEvent(nil), g.events...)
+	return game
+}
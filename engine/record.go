@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func (c *Client) eventsURL(id string) string {
+	base := strings.TrimSuffix(c.BaseURL, "/")
+	switch {
+	case strings.HasPrefix(base, "https://"):
+		base = "wss://" + strings.TrimPrefix(base, "https://")
+	case strings.HasPrefix(base, "http://"):
+		base = "ws://" + strings.TrimPrefix(base, "http://")
+	}
+	return base + "/games/" + url.PathEscape(id) + "/events"
+}
+
+// Record follows a running game over the engine's event stream, calling
+// onFrame (when not nil) as each frame arrives, and returns the finished game
+// once the engine reports it has ended. Missed frames are filled in from the
+// frames endpoint. Elimination and game end events are kept in the game's
+// Events. If ctx is cancelled the frames recorded so far are
+// returned along with the context's error. Games are marked with MarkLocal
+// when c.Local is set.
+func (c *Client) Record(ctx context.Context, id string, onFrame func(settings *bsgf.ViewGameSettings, frame *bsgf.ViewFrame)) (*bsgf.ViewGame, error) {
+	var resp bsgf.ViewGameResponse
+	err := c.getJSON(ctx, "/games/"+url.PathEscape(id), &resp)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := dialWebsocket(ctx, c.eventsURL(id))
+	if err != nil {
+		return nil, err
+	}
+	defer ws.Close()
+
+	recorded := NewEventGame(resp.Game)
+	for !recorded.Ended() {
+		message, err := ws.ReadMessage()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				game := recorded.Game()
+				if c.Local {
+					MarkLocal(game, c.Builds)
+				}
+				return game, ctx.Err()
+			}
+			return nil, fmt.Errorf("error reading game events: %w", err)
+		}
+		start := time.Now()
+		e, err := ParseEvent(message)
+		if err != nil {
+			return nil, err
+		}
+		recorded.Add(e)
+		if e.Type != EventFrame {
+			continue
+		}
+		if onFrame != nil {
+			onFrame(&resp.Game, e.Frame)
+		}
+		if h := bsgf.CurrentHooks(); h.OnFrameProcessed != nil {
+			h.OnFrameProcessed(bsgf.FrameStats{Stage: bsgf.FrameStageRecord, GameID: id, Turn: e.Frame.Turn, Bytes: len(e.Data), Duration: time.Since(start)})
+		}
+	}
+
+	// refresh the settings so Status reflects the finished game
+	err = c.getJSON(ctx, "/games/"+url.PathEscape(id), &resp)
+	if err != nil {
+		return nil, err
+	}
+	recorded.SetSettings(resp.Game)
+	game := recorded.Game()
+	if !recorded.Complete() {
+		game.Frames, err = c.Frames(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(game.Frames) > 0 {
+			game.FirstFrame = game.Frames[0]
+			game.LastTurn = game.Frames[len(game.Frames)-1].Turn
+		}
+	}
+	if c.Local {
+		MarkLocal(game, c.Builds)
+	}
+	return game, nil
+}
+
+// LocalIDPrefix starts the IDs MarkLocal gives games
+const LocalIDPrefix = "local-"
+
+// MarkLocal labels a game played on a locally run engine so archives of it
+// can be told apart from engine downloads. The game ID gets LocalIDPrefix,
+// as a local engine's IDs are only unique to it, and each snake with a
+// build in builds, by name, has it added to its URL as a "build" fragment
+// and to its Author in parentheses. Games that are already marked are left
+// as they are.
+func MarkLocal(game *bsgf.ViewGame, builds map[string]string) {
+	if IsLocal(game) {
+		return
+	}
+	game.Game.ID = LocalIDPrefix + game.Game.ID
+	markBuilds(&game.FirstFrame, builds)
+	for i := range game.Frames {
+		markBuilds(&game.Frames[i], builds)
+	}
+}
+
+// IsLocal reports whether game was marked by MarkLocal
+func IsLocal(game *bsgf.ViewGame) bool {
+	return strings.HasPrefix(game.Game.ID, LocalIDPrefix)
+}
+
+// SnakeBuild is the build identifier MarkLocal added to a snake, if any
+func SnakeBuild(s *bsgf.ViewSnake) string {
+	_, fragment, _ := strings.Cut(s.URL, "#")
+	values, _ := url.ParseQuery(fragment)
+	return values.Get("build")
+}
+
+func markBuilds(frame *bsgf.ViewFrame, builds map[string]string) {
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		build, ok := builds[s.Name]
+		// FirstFrame usually shares its snakes with the first of Frames
+		if !ok || build == "" || SnakeBuild(s) != "" {
+			continue
+		}
+		sep := "#"
+		if strings.Contains(s.URL, "#") {
+			sep = "&"
+		}
+		s.URL += sep + "build=" + url.QueryEscape(build)
+		if s.Author == "" {
+			s.Author = build
+		} else {
+			s.Author += " (" + build + ")"
+		}
+	}
+}
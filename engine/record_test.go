@@ -0,0 +1,48 @@
+package engine_test
+
+import (
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/engine"
+)
+
+func localGame() *bsgf.ViewGame {
+	snakes := []bsgf.ViewSnake{
+		{ID: "a", Name: "Alpha", URL: "http://alpha.test", Author: "me"},
+		{ID: "b", Name: "Beta", URL: "http://beta.test#v=2"},
+		{ID: "c", Name: "Gamma", URL: "http://gamma.test"},
+	}
+	game := &bsgf.ViewGame{Game: bsgf.ViewGameSettings{ID: "g1"}}
+	game.Frames = []bsgf.ViewFrame{{Turn: 0, Snakes: snakes}, {Turn: 1, Snakes: append([]bsgf.ViewSnake(nil), snakes...)}}
+	// like a decoded game, FirstFrame shares its snakes with Frames[0]
+	game.FirstFrame = game.Frames[0]
+	return game
+}
+
+func TestMarkLocal(t *testing.T) {
+	game := localGame()
+	if engine.IsLocal(game) {
+		t.Fatal("unmarked game is local")
+	}
+	engine.MarkLocal(game, map[string]string{"Alpha": "v1.2.3", "Beta": "a b"})
+	engine.MarkLocal(game, map[string]string{"Alpha": "again"})
+	if !engine.IsLocal(game) || game.Game.ID != engine.LocalIDPrefix+"g1" {
+		t.Fatalf("expected a local ID, got %q", game.Game.ID)
+	}
+	want := []struct {
+		url, author, build string
+	}{
+		{"http://alpha.test#build=v1.2.3", "me (v1.2.3)", "v1.2.3"},
+		{"http://beta.test#v=2&build=a+b", "a b", "a b"},
+		{"http://gamma.test", "", ""},
+	}
+	for _, frame := range append([]bsgf.ViewFrame{game.FirstFrame}, game.Frames...) {
+		for i, w := range want {
+			s := &frame.Snakes[i]
+			if s.URL != w.url || s.Author != w.author || engine.SnakeBuild(s) != w.build {
+				t.Errorf("turn %d: expected %s by %q with build %q, got %s by %q with build %q", frame.Turn, w.url, w.author, w.build, s.URL, s.Author, engine.SnakeBuild(s))
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package battlesnakegameformat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Errors returned by this package wrap one of these, so callers can check
+// for them with errors.Is. The error underneath is wrapped as well, so
+// errors.As still finds a *json.SyntaxError or the like.
+var (
+	// ErrFrameNotFound is returned when a game has no frame for the
+	// requested turn or snake.
+	ErrFrameNotFound = errors.New("frame not found")
+	// ErrSnakeNotFound is returned when a snake ID isn't in the game or
+	// frame being looked at.
+	ErrSnakeNotFound = errors.New("snake not found")
+	// ErrCorruptArchive is returned when encoded game data is damaged,
+	// incomplete or doesn't match its manifest.
+	ErrCorruptArchive = errors.New("corrupt archive")
+	// ErrUnsupportedFormat is returned for data that isn't an archive or
+	// was written by a newer version of the format.
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	// ErrInvalidGame is wrapped by the *ValidationError from Validate.
+	ErrInvalidGame = errors.New("invalid game")
+	// ErrLimitExceeded is wrapped by the *LimitError returned when an
+	// archive is bigger than the Limits it was decoded with.
+	ErrLimitExceeded = errors.New("limit exceeded")
+)
+
+// IOError is returned when the reader or writer handed to this package
+// fails, as opposed to the data it holds being bad, so callers can retry
+// I/O failures and reject corrupt archives. Err is the reader or writer's
+// own error.
+type IOError struct {
+	// Op is "read" or "write"
+	Op  string
+	Err error
+}
+
+func (e *IOError) Error() string {
+	return fmt.Sprintf("error during %s: %s", e.Op, e.Err)
+}
+
+func (e *IOError) Unwrap() error {
+	return e.Err
+}
+
+// ioFailure keeps the first error from a reader or writer, other than
+// io.EOF
+type ioFailure struct {
+	op  string
+	err error
+}
+
+func (f *ioFailure) note(err error) {
+	if err != nil && err != io.EOF && f.err == nil {
+		f.err = err
+	}
+}
+
+// blame returns an *IOError instead of err when the reader or writer failed,
+// as err is then only a symptom of that failure
+func (f *ioFailure) blame(err error) error {
+	if err == nil || f.err == nil {
+		return err
+	}
+	return &IOError{Op: f.op, Err: f.err}
+}
+
+type failureReaderAt struct {
+	r io.ReaderAt
+	ioFailure
+}
+
+func (r *failureReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.r.ReadAt(p, off)
+	r.note(err)
+	return n, err
+}
+
+type failureWriter struct {
+	w io.Writer
+	ioFailure
+}
+
+func (w *failureWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.note(err)
+	return n, err
+}
@@ -0,0 +1,31 @@
+package battlesnakegameformat
+
+// EventType names a kind of game event
+type EventType string
+
+// Events recorded from the engine's event stream
+const (
+	EventElimination EventType = "elimination"
+	EventGameEnd     EventType = "game_end"
+)
+
+// ViewEvent is a turn stamped event from the engine's event stream.
+// Eliminations carry the snake and cause, the same as its ViewDeath.
+type ViewEvent struct {
+	Type         EventType  `json:"Type"`
+	Turn         int32      `json:"Turn"`
+	SnakeID      string     `json:"SnakeID,omitempty"`
+	Cause        DeathCause `json:"Cause,omitempty"`
+	EliminatedBy string     `json:"EliminatedBy,omitempty"`
+}
+
+// Eliminations returns the elimination events in the order they happened
+func (game *ViewGame) Eliminations() []ViewEvent {
+	var events []ViewEvent
+	for _, e := range game.Events {
+		if e.Type == EventElimination {
+			events = append(events, e)
+		}
+	}
+	return events
+}
@@ -0,0 +1,68 @@
+package battlesnakegameformat
+
+import (
+	"sync"
+)
+
+// RulesetSettingsDecoder turns the untyped Settings of a ruleset into a
+// typed value. It is given the whole ruleset so it can read the typed
+// fields too.
+type RulesetSettingsDecoder func(r ViewRuleset) (interface{}, error)
+
+var (
+	rulesetDecodersMu sync.RWMutex
+	rulesetDecoders   = make(map[RulesetName]RulesetSettingsDecoder)
+)
+
+// RegisterRulesetSettings sets the decoder used by DecodeSettings for games
+// played with name, replacing any earlier one. Settings are kept as raw
+// json whether or not a decoder is registered, so games with new modes
+// still round trip.
+func RegisterRulesetSettings(name RulesetName, decode RulesetSettingsDecoder) {
+	rulesetDecodersMu.Lock()
+	defer rulesetDecodersMu.Unlock()
+	rulesetDecoders[name] = decode
+}
+
+// DecodeSettings returns the typed settings for the ruleset from the
+// registered decoder, or nil when there is no decoder for it.
+func (r ViewRuleset) DecodeSettings() (interface{}, error) {
+	rulesetDecodersMu.RLock()
+	decode, ok := rulesetDecoders[r.RulesetName()]
+	rulesetDecodersMu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+	return decode(r)
+}
+
+// RoyaleSettings are the extra settings of the royale ruleset
+type RoyaleSettings struct {
+	ShrinkEveryNTurns int32
+	DamagePerTurn     int32
+}
+
+// SquadSettings are the extra settings of the squad ruleset
+type SquadSettings struct {
+	AllowBodyCollisions bool
+	SharedElimination   bool
+	SharedHealth        bool
+	SharedLength        bool
+}
+
+func init() {
+	RegisterRulesetSettings(RulesetRoyale, func(r ViewRuleset) (interface{}, error) {
+		return &RoyaleSettings{
+			ShrinkEveryNTurns: r.settingInt(settingShrinkEveryNTurns),
+			DamagePerTurn:     r.DamagePerTurn,
+		}, nil
+	})
+	RegisterRulesetSettings(RulesetSquad, func(r ViewRuleset) (interface{}, error) {
+		return &SquadSettings{
+			AllowBodyCollisions: r.settingBool(settingAllowBodyCollisions),
+			SharedElimination:   r.settingBool(settingSharedElimination),
+			SharedHealth:        r.settingBool(settingSharedHealth),
+			SharedLength:        r.settingBool(settingSharedLength),
+		}, nil
+	})
+}
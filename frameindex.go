@@ -0,0 +1,165 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Extension added to an archive's path for its frame index
+const FrameIndexExtension = ".idx"
+
+// Version of the frame index layout written by BuildFrameIndex
+const FrameIndexVersion = 1
+
+// FrameIndex is kept next to an archive so metadata queries and single
+// frame reads don't have to decode the whole game. It works with archives
+// written by any version, which don't need to be encoded again.
+type FrameIndex struct {
+	Version int `json:"version"`
+	// Size and SHA256 of the archive the index was built from
+	ArchiveSize   int64  `json:"archiveSize"`
+	ArchiveSHA256 string `json:"archiveSha256"`
+
+	GameID   string       `json:"gameId"`
+	Ruleset  string       `json:"ruleset"`
+	Map      string       `json:"map"`
+	Width    int32        `json:"width"`
+	Height   int32        `json:"height"`
+	LastTurn int32        `json:"lastTurn"`
+	Winner   string       `json:"winner,omitempty"`
+	Snakes   []Placement  `json:"snakes"`
+	Frames   []FrameEntry `json:"frames"`
+}
+
+// FrameEntry locates one frame in the uncompressed game json, or in the
+// uncompressed json of File for a chunked archive
+type FrameEntry struct {
+	Turn   int32  `json:"turn"`
+	File   string `json:"file,omitempty"`
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+}
+
+// BuildFrameIndex reads an archive and returns its frame index, the roster
+// of snakes with their placements and the winner.
+func BuildFrameIndex(data []byte) (*FrameIndex, error) {
+	lazy, err := DecodeLazy(data)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	ix := &FrameIndex{
+		Version:       FrameIndexVersion,
+		ArchiveSize:   int64(len(data)),
+		ArchiveSHA256: hex.EncodeToString(sum[:]),
+		GameID:        lazy.Game.ID,
+		Ruleset:       lazy.Game.Ruleset.Name,
+		Map:           lazy.Game.Ruleset.Map,
+		Width:         lazy.Game.Width,
+		Height:        lazy.Game.Height,
+		LastTurn:      lazy.LastTurn,
+		Frames:        make([]FrameEntry, 0, lazy.FrameCount()),
+	}
+	for i := 0; i < lazy.FrameCount(); i++ {
+		_, file, span, err := lazy.frameJSON(i)
+		if err != nil {
+			return nil, err
+		}
+		turn, err := lazy.turnOf(i)
+		if err != nil {
+			return nil, err
+		}
+		ix.Frames = append(ix.Frames, FrameEntry{Turn: turn, File: file, Offset: int64(span.start), Length: int64(span.end - span.start)})
+	}
+	if n := lazy.FrameCount(); n > 0 {
+		last, err := lazy.Frame(n - 1)
+		if err != nil {
+			return nil, err
+		}
+		final := ViewGame{Frames: []ViewFrame{*last}}
+		ix.Snakes = final.Placements()
+		ix.Winner = final.Winner()
+	}
+	return ix, nil
+}
+
+// DecodeFrameIndex reads an index written as json
+func DecodeFrameIndex(data []byte) (*FrameIndex, error) {
+	var ix FrameIndex
+	err := json.Unmarshal(data, &ix)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling frame index: %w", ErrCorruptArchive, err)
+	}
+	if ix.Version != FrameIndexVersion {
+		return nil, fmt.Errorf("%w: frame index version %d", ErrUnsupportedFormat, ix.Version)
+	}
+	return &ix, nil
+}
+
+// Matches reports whether the index was built from archive
+func (ix *FrameIndex) Matches(archive []byte) bool {
+	if int64(len(archive)) != ix.ArchiveSize {
+		return false
+	}
+	sum := sha256.Sum256(archive)
+	return hex.EncodeToString(sum[:]) == ix.ArchiveSHA256
+}
+
+// ReadFrame decodes only the frame for turn from archive. The archive, or
+// the chunk holding the frame, is still uncompressed up to the frame, but
+// nothing before it is parsed.
+func (ix *FrameIndex) ReadFrame(archive []byte, turn int32) (*ViewFrame, error) {
+	var entry *FrameEntry
+	for i := range ix.Frames {
+		if ix.Frames[i].Turn == turn {
+			entry = &ix.Frames[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: no frame for turn %d", ErrFrameNotFound, turn)
+	}
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	var f *zip.File
+	if entry.File != "" {
+		f = zipFiles(r)[entry.File]
+		if f == nil {
+			return nil, fmt.Errorf("%w: no %s in archive, is the index stale?", ErrCorruptArchive, entry.File)
+		}
+	} else {
+		f, err = gameFile(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening zip archive file: %w", ErrCorruptArchive, err)
+	}
+	defer rc.Close()
+	_, err = io.CopyN(ioutil.Discard, rc, entry.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error seeking to turn %d: %w", ErrCorruptArchive, turn, err)
+	}
+	raw := make([]byte, entry.Length)
+	_, err = io.ReadFull(rc, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading turn %d: %w", ErrCorruptArchive, turn, err)
+	}
+	var frame ViewFrame
+	err = json.Unmarshal(raw, &frame)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling turn %d, is the index stale? %w", ErrCorruptArchive, turn, err)
+	}
+	internFrame(&frame)
+	return &frame, nil
+}
@@ -0,0 +1,62 @@
+package battlesnakegameformat_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func TestFrameIndex(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	for _, layout := range []struct {
+		name    string
+		data    []byte
+		chunked bool
+	}{
+		{"single", encode(t, game), false},
+		{"chunked", encodeChunked(t, game, 4), true},
+	} {
+		t.Run(layout.name, func(t *testing.T) {
+			ix, err := bsgf.BuildFrameIndex(layout.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ix.Matches(layout.data) || ix.Matches(layout.data[1:]) {
+				t.Error("Matches doesn't tell archives apart")
+			}
+			if ix.GameID != game.Game.ID || ix.LastTurn != game.LastTurn || ix.Winner != game.Winner() || len(ix.Snakes) != 3 {
+				t.Errorf("unexpected index %+v", ix)
+			}
+			// through json, like the .idx file
+			contents, err := json.Marshal(ix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ix, err = bsgf.DecodeFrameIndex(contents)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ix.Frames) != len(game.Frames) {
+				t.Fatalf("expected %d entries, got %d", len(game.Frames), len(ix.Frames))
+			}
+			for i, entry := range ix.Frames {
+				if (entry.File != "") != layout.chunked {
+					t.Errorf("turn %d has file %q", entry.Turn, entry.File)
+				}
+				frame, err := ix.ReadFrame(layout.data, game.Frames[i].Turn)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diffs := bsgf.DiffFrames(&game.Frames[i], frame); len(diffs) > 0 {
+					t.Errorf("turn %d: %v", frame.Turn, diffs)
+				}
+			}
+			_, err = ix.ReadFrame(layout.data, game.LastTurn+1)
+			if !errors.Is(err, bsgf.ErrFrameNotFound) {
+				t.Errorf("expected ErrFrameNotFound, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package battlesnakegameformat
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FrameAt returns the frame recorded for turn. Frames are usually stored one
+// per turn starting at 0, but games that were trimmed or recorded with gaps
+// are looked up by their Turn field.
+func (game *ViewGame) FrameAt(turn int32) (*ViewFrame, error) {
+	if turn >= 0 && int(turn) < len(game.Frames) && game.Frames[turn].Turn == turn {
+		return &game.Frames[turn], nil
+	}
+	i := sort.Search(len(game.Frames), func(i int) bool {
+		return game.Frames[i].Turn >= turn
+	})
+	if i < len(game.Frames) && game.Frames[i].Turn == turn {
+		return &game.Frames[i], nil
+	}
+	return nil, fmt.Errorf("%w: no frame for turn %d", ErrFrameNotFound, turn)
+}
+
+// FirstAliveFrame returns the first frame where snakeId is on the board and
+// hasn't been eliminated.
+func (game *ViewGame) FirstAliveFrame(snakeId string) (*ViewFrame, error) {
+	for i := range game.Frames {
+		s, ok := findSnake(&game.Frames[i], snakeId)
+		if ok && !s.Death.Eliminated() {
+			return &game.Frames[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: snake %s is never alive", ErrFrameNotFound, snakeId)
+}
+
+// FinalFrame returns the last recorded frame.
+func (game *ViewGame) FinalFrame() (*ViewFrame, error) {
+	if len(game.Frames) == 0 {
+		return nil, fmt.Errorf("%w: game has no frames", ErrFrameNotFound)
+	}
+	return &game.Frames[len(game.Frames)-1], nil
+}
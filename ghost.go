@@ -0,0 +1,184 @@
+package battlesnakegameformat
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Health of a snake at the start of a game and after eating
+const ghostMaxHealth = 100
+
+// GhostMover picks a ghost's move from the move request it would have been
+// sent that turn, the same request a snake server gets. harness.GhostMover
+// turns a snake server into one.
+type GhostMover func(state *MoveGameState) (Direction, error)
+
+// GhostOptions say where a ghost goes. Exactly one of Bodies and Move is
+// set.
+type GhostOptions struct {
+	// Bodies places the ghost by hand, Bodies[i] being its body on turn i,
+	// head first. The ghost is eliminated with CauseUnknown on the first
+	// turn Bodies doesn't cover and stays where it last was.
+	Bodies [][]ViewCoord
+	// Move drives the ghost a turn at a time from the body it's given
+	Move GhostMover
+}
+
+// InjectGhost returns a copy of game with ghost added to every frame, to
+// see how a snake would have fared in a recorded game. The recorded snakes
+// play exactly as they did and never react to the ghost or die to it. The
+// ghost plays by the ruleset: it loses health every turn and more in
+// hazards, eats the recorded food and is eliminated by walls, starving,
+// itself and the bodies and heads of the recorded snakes. Its health
+// starts at 100 when ghost.Health is 0.
+func (game *ViewGame) InjectGhost(ghost ViewSnake, opts GhostOptions) (*ViewGame, error) {
+	if (opts.Bodies == nil) == (opts.Move == nil) {
+		return nil, errors.New("exactly one of Bodies and Move must be set")
+	}
+	if len(game.Frames) == 0 {
+		return nil, fmt.Errorf("%w: game has no frames", ErrFrameNotFound)
+	}
+	if ghost.ID == "" {
+		return nil, errors.New("ghost needs an ID")
+	}
+	if _, ok := findSnake(&game.Frames[0], ghost.ID); ok {
+		return nil, fmt.Errorf("game %s already has a snake %s", game.Game.ID, ghost.ID)
+	}
+	if opts.Bodies != nil {
+		if len(opts.Bodies) == 0 {
+			return nil, errors.New("empty Bodies, expected a body for turn 0")
+		}
+		ghost.Body = opts.Bodies[0]
+	}
+	if len(ghost.Body) == 0 {
+		return nil, errors.New("ghost needs a body on turn 0")
+	}
+	ghost.Body = append([]ViewCoord(nil), ghost.Body...)
+	if ghost.Health == 0 {
+		ghost.Health = ghostMaxHealth
+	}
+	ghost.Death = ViewDeath{}
+
+	out := game.Clone()
+	out.Frames[0].Snakes = append(out.Frames[0].Snakes, ghost)
+	for t := 1; t < len(out.Frames); t++ {
+		prev := &out.Frames[t-1]
+		before := &prev.Snakes[len(prev.Snakes)-1]
+		after := *before
+		after.Body = append([]ViewCoord(nil), before.Body...)
+		if !before.Death.Eliminated() {
+			err := out.moveGhost(prev, &out.Frames[t], before, &after, opts, t)
+			if err != nil {
+				return nil, fmt.Errorf("error moving ghost on turn %d: %w", out.Frames[t].Turn, err)
+			}
+		}
+		out.Frames[t].Snakes = append(out.Frames[t].Snakes, after)
+		if after.Death.Eliminated() && !before.Death.Eliminated() && len(out.Events) > 0 {
+			out.addElimination(ghost.ID, after.Death)
+		}
+	}
+	out.FirstFrame = out.Frames[0]
+	return out, nil
+}
+
+// moveGhost plays one turn for the ghost, from before in prev to after in
+// frame. The recorded snakes in frame have already moved.
+func (game *ViewGame) moveGhost(prev, frame *ViewFrame, before, after *ViewSnake, opts GhostOptions, t int) error {
+	width, height := game.Game.Width, game.Game.Height
+	name := game.Game.Ruleset.RulesetName()
+	moved := opts.Bodies == nil
+	if moved {
+		dir, err := opts.Move(game.moveState(prev, before, nil))
+		if err != nil {
+			return err
+		}
+		head := dir.Apply(before.Body[0])
+		if name.Wrapped() {
+			head = head.Wrap(width, height)
+		}
+		after.Body = append([]ViewCoord{head}, before.Body[:len(before.Body)-1]...)
+	} else {
+		if t >= len(opts.Bodies) {
+			after.Death = ViewDeath{Cause: CauseUnknown, Turn: frame.Turn}
+			return nil
+		}
+		if len(opts.Bodies[t]) == 0 {
+			return errors.New("empty body")
+		}
+		after.Body = append([]ViewCoord(nil), opts.Bodies[t]...)
+	}
+	head := after.Body[0]
+
+	ate := false
+	for _, f := range prev.Food {
+		if f == head {
+			ate = true
+		}
+	}
+	after.Health = before.Health - 1
+	if n := frame.HazardStacks(head); n > 0 && !ate {
+		after.Health -= game.hazardDamage(frame) * int32(n)
+	}
+	if ate || name.Constrictor() {
+		after.Health = ghostMaxHealth
+		// constrictor snakes only grow when their tail isn't already stacked
+		n := len(after.Body)
+		if moved && (ate || n < 2 || after.Body[n-1] != after.Body[n-2]) {
+			after.Body = append(after.Body, after.Body[n-1])
+		}
+	}
+
+	death := ViewDeath{Turn: frame.Turn}
+	switch {
+	case after.Health <= 0:
+		after.Health = 0
+		death.Cause = CauseOutOfHealth
+	case !head.InBounds(width, height):
+		death.Cause = CauseWallCollision
+	case containsCoord(after.Body[1:], head):
+		death.Cause = CauseSelfCollision
+	default:
+		for i := range frame.Snakes {
+			other := &frame.Snakes[i]
+			// snakes eliminated this turn were still on the board when the
+			// ghost moved
+			if was, ok := findSnake(prev, other.ID); !ok || was.Death.Eliminated() || len(other.Body) == 0 {
+				continue
+			}
+			if containsCoord(other.Body[1:], head) {
+				death.Cause, death.EliminatedBy = CauseSnakeCollision, other.ID
+				break
+			}
+			if other.Body[0] == head && len(after.Body) <= len(other.Body) {
+				death.Cause, death.EliminatedBy = CauseHeadCollision, other.ID
+				break
+			}
+		}
+	}
+	if death.Cause != CauseNone {
+		after.Death = death
+	}
+	return nil
+}
+
+// addElimination records the ghost's death among the game's events, ahead
+// of any later events and the end of the game
+func (game *ViewGame) addElimination(snakeId string, death ViewDeath) {
+	e := ViewEvent{Type: EventElimination, Turn: death.Turn, SnakeID: snakeId, Cause: death.Cause, EliminatedBy: death.EliminatedBy}
+	i := 0
+	for i < len(game.Events) && game.Events[i].Turn <= death.Turn && game.Events[i].Type != EventGameEnd {
+		i++
+	}
+	game.Events = append(game.Events, ViewEvent{})
+	copy(game.Events[i+1:], game.Events[i:])
+	game.Events[i] = e
+}
+
+func containsCoord(coords []ViewCoord, c ViewCoord) bool {
+	for _, p := range coords {
+		if p == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package battlesnakegameformat
+
+// Cell is the contents of one square of a Grid
+type Cell struct {
+	Food    bool
+	Hazards int
+	// SnakeID is the snake with a segment here, or "" for an empty cell.
+	// When segments overlap the one nearest its head wins.
+	SnakeID string
+	// Segment is the index into the snake's body, 0 for the head
+	Segment int
+	// Tail is set on the last segment of a snake's body
+	Tail bool
+}
+
+// Occupied is true if a snake has a segment in the cell.
+func (c *Cell) Occupied() bool {
+	return c.SnakeID != ""
+}
+
+// Grid is an occupancy map of a board, indexed by y*Width+x
+type Grid struct {
+	Width  int32
+	Height int32
+	Cells  []Cell
+}
+
+// NewGrid returns an empty width by height grid.
+func NewGrid(width, height int32) *Grid {
+	if width < 0 || height < 0 {
+		width, height = 0, 0
+	}
+	return &Grid{Width: width, Height: height, Cells: make([]Cell, width*height)}
+}
+
+// ToGrid builds the occupancy map of a frame on a width by height board.
+// Eliminated snakes are left out.
+func (frame *ViewFrame) ToGrid(width, height int32) *Grid {
+	g := NewGrid(width, height)
+	for _, c := range frame.Food {
+		if cell := g.At(c); cell != nil {
+			cell.Food = true
+		}
+	}
+	for _, c := range frame.Hazards {
+		if cell := g.At(c); cell != nil {
+			cell.Hazards++
+		}
+	}
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		if s.Alive() {
+			g.addSnake(s.ID, s.Body)
+		}
+	}
+	return g
+}
+
+// ToGrid builds the occupancy map of a move request's board.
+func (board *MoveBoard) ToGrid() *Grid {
+	g := NewGrid(board.Width, board.Height)
+	for _, c := range board.Food {
+		if cell := g.At(ViewCoord(c)); cell != nil {
+			cell.Food = true
+		}
+	}
+	for _, c := range board.Hazards {
+		if cell := g.At(ViewCoord(c)); cell != nil {
+			cell.Hazards++
+		}
+	}
+	for i := range board.Snakes {
+		g.addSnake(board.Snakes[i].ID, viewCoords(board.Snakes[i].Body))
+	}
+	return g
+}
+
+func (g *Grid) addSnake(snakeId string, body []ViewCoord) {
+	// tail first so segments nearer the head overwrite stacked ones
+	for i := len(body) - 1; i >= 0; i-- {
+		cell := g.At(body[i])
+		if cell == nil {
+			continue
+		}
+		cell.SnakeID = snakeId
+		cell.Segment = i
+		cell.Tail = i == len(body)-1
+	}
+}
+
+// At returns the cell at c, or nil if c is off the board.
+func (g *Grid) At(c ViewCoord) *Cell {
+	if !c.InBounds(g.Width, g.Height) {
+		return nil
+	}
+	return &g.Cells[c.Y*g.Width+c.X]
+}
+
+// Free is true if c is on the board and no snake is there.
+func (g *Grid) Free(c ViewCoord) bool {
+	cell := g.At(c)
+	return cell != nil && !cell.Occupied()
+}
+
+// FreeNeighbors returns the directions from c that lead to free cells.
+func (g *Grid) FreeNeighbors(c ViewCoord) []Direction {
+	var free []Direction
+	for _, d := range Directions {
+		if g.Free(d.Apply(c)) {
+			free = append(free, d)
+		}
+	}
+	return free
+}
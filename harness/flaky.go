@@ -0,0 +1,75 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Flake is a position where repeated requests got different answers
+type Flake struct {
+	Turn int32
+	// Moves counts how often each move was returned
+	Moves map[bsgf.Direction]int
+	// Errors counts requests that failed instead of returning a move
+	Errors int
+	Min    time.Duration
+	Max    time.Duration
+}
+
+// Spread between the fastest and slowest response for this position
+func (f *Flake) Spread() time.Duration {
+	return f.Max - f.Min
+}
+
+// DetectFlaky sends every position where snakeId is alive to snake n times
+// and returns the positions where the answers were not all the same.
+func DetectFlaky(ctx context.Context, game *bsgf.ViewGame, snakeId string, snake Snake, n int) ([]Flake, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("need at least 2 repetitions to detect flaky moves, got %d", n)
+	}
+	var flakes []Flake
+	var arena bsgf.MoveArena
+	for frame := range game.FramesAlive(snakeId) {
+		arena.Reset()
+		state, err := arena.ToMove(game, frame.Turn, snakeId)
+		if err != nil {
+			return nil, err
+		}
+		flake := Flake{Turn: frame.Turn, Moves: make(map[bsgf.Direction]int, 4)}
+		for j := 0; j < n; j++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			start := time.Now()
+			move, err := snake.Move(ctx, state)
+			d := time.Since(start)
+			if j == 0 || d < flake.Min {
+				flake.Min = d
+			}
+			if d > flake.Max {
+				flake.Max = d
+			}
+			if err != nil {
+				flake.Errors++
+				continue
+			}
+			dir, err := move.Direction()
+			if err != nil {
+				flake.Errors++
+				continue
+			}
+			flake.Moves[dir]++
+		}
+		answers := len(flake.Moves)
+		if flake.Errors > 0 {
+			answers++
+		}
+		if answers > 1 {
+			flakes = append(flakes, flake)
+		}
+	}
+	return flakes, nil
+}
@@ -0,0 +1,19 @@
+package harness
+
+import (
+	"context"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// GhostMover asks snake for each move of a ghost injected with
+// ViewGame.InjectGhost
+func GhostMover(ctx context.Context, snake Snake) bsgf.GhostMover {
+	return func(state *bsgf.MoveGameState) (bsgf.Direction, error) {
+		move, err := snake.Move(ctx, state)
+		if err != nil {
+			return "", err
+		}
+		return move.Direction()
+	}
+}
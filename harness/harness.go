@@ -0,0 +1,112 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Snake is anything that can answer a /move request. Replay reuses the
+// memory of each state once Move returns, so it must not be kept.
+type Snake interface {
+	Move(ctx context.Context, state *bsgf.MoveGameState) (*bsgf.MoveBattlesnakeResponse, error)
+}
+
+// HTTPSnake sends move requests to a running battlesnake server
+type HTTPSnake struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *HTTPSnake) Move(ctx context.Context, state *bsgf.MoveGameState) (*bsgf.MoveBattlesnakeResponse, error) {
+	body, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling MoveGameState to json: %w", err)
+	}
+	url := strings.TrimSuffix(s.URL, "/") + "/move"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating move request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending move request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from snake: %s", resp.Status)
+	}
+	var move bsgf.MoveBattlesnakeResponse
+	err = json.NewDecoder(resp.Body).Decode(&move)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding move response: %w", err)
+	}
+	return &move, nil
+}
+
+// Result of sending a single recorded position to a snake
+type Result struct {
+	Turn     int32
+	Move     bsgf.Direction
+	Duration time.Duration
+	Phase    Phase
+	// Exceeded is set when Duration was longer than the game's Timeout
+	Exceeded bool
+	Err      error
+}
+
+// Report collects replay results with latency histograms per game phase
+type Report struct {
+	Results []Result
+	Phases  map[Phase]*Histogram
+	// Exceeded lists every result that took longer than the game's Timeout
+	Exceeded []Result
+}
+
+// Replay sends every position where snakeId is alive to snake and times the
+// responses. Errors from the snake are recorded per result rather than
+// aborting the replay.
+func Replay(ctx context.Context, game *bsgf.ViewGame, snakeId string, snake Snake) (*Report, error) {
+	report := &Report{Phases: make(map[Phase]*Histogram, 3)}
+	for _, phase := range []Phase{PhaseEarly, PhaseMid, PhaseLate} {
+		report.Phases[phase] = NewHistogram(DefaultBuckets)
+	}
+	timeout := time.Duration(game.Game.Timeout) * time.Millisecond
+	var arena bsgf.MoveArena
+	for frame := range game.FramesAlive(snakeId) {
+		arena.Reset()
+		state, err := arena.ToMove(game, frame.Turn, snakeId)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := Result{Turn: frame.Turn, Phase: PhaseOf(game, frame)}
+		start := time.Now()
+		move, err := snake.Move(ctx, state)
+		result.Duration = time.Since(start)
+		if err == nil {
+			result.Move, err = move.Direction()
+		}
+		result.Err = err
+		result.Exceeded = timeout > 0 && result.Duration > timeout
+		report.Phases[result.Phase].Add(result.Duration)
+		report.Results = append(report.Results, result)
+		if result.Exceeded {
+			report.Exceeded = append(report.Exceeded, result)
+		}
+	}
+	return report, nil
+}
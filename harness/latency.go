@@ -0,0 +1,109 @@
+package harness
+
+import (
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Phase of a game, determined by how much of the board is covered by snakes
+type Phase int
+
+const (
+	PhaseEarly Phase = iota
+	PhaseMid
+	PhaseLate
+)
+
+// Board fullness thresholds separating the phases
+const (
+	midGameFullness  = 0.15
+	lateGameFullness = 0.30
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseEarly:
+		return "early"
+	case PhaseMid:
+		return "mid"
+	case PhaseLate:
+		return "late"
+	}
+	return "unknown"
+}
+
+// PhaseOf classifies a frame by the fraction of cells occupied by live snakes
+func PhaseOf(game *bsgf.ViewGame, frame *bsgf.ViewFrame) Phase {
+	area := float64(game.Game.Width * game.Game.Height)
+	if area <= 0 {
+		return PhaseEarly
+	}
+	var occupied int
+	for _, s := range frame.Snakes {
+		if !s.Death.Eliminated() {
+			occupied += len(s.Body)
+		}
+	}
+	fullness := float64(occupied) / area
+	switch {
+	case fullness >= lateGameFullness:
+		return PhaseLate
+	case fullness >= midGameFullness:
+		return PhaseMid
+	}
+	return PhaseEarly
+}
+
+// DefaultBuckets are the upper bounds used for latency histograms
+var DefaultBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	300 * time.Millisecond,
+	400 * time.Millisecond,
+	500 * time.Millisecond,
+}
+
+// Histogram of durations. Counts has one entry per bound plus a final
+// overflow bucket.
+type Histogram struct {
+	Bounds []time.Duration
+	Counts []int
+	N      int
+	Total  time.Duration
+	Min    time.Duration
+	Max    time.Duration
+}
+
+func NewHistogram(bounds []time.Duration) *Histogram {
+	return &Histogram{
+		Bounds: bounds,
+		Counts: make([]int, len(bounds)+1),
+	}
+}
+
+func (h *Histogram) Add(d time.Duration) {
+	i := 0
+	for i < len(h.Bounds) && d > h.Bounds[i] {
+		i++
+	}
+	h.Counts[i]++
+	if h.N == 0 || d < h.Min {
+		h.Min = d
+	}
+	if d > h.Max {
+		h.Max = d
+	}
+	h.N++
+	h.Total += d
+}
+
+func (h *Histogram) Mean() time.Duration {
+	if h.N == 0 {
+		return 0
+	}
+	return h.Total / time.Duration(h.N)
+}
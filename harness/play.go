@@ -0,0 +1,517 @@
+package harness
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/engine"
+)
+
+// Health of a snake at the start of a game and after eating
+const maxHealth = 100
+
+// Player is one snake in a game played by Play
+type Player struct {
+	Name   string
+	URL    string
+	Author string
+	// Build identifies the version of the snake that played, see
+	// engine.MarkLocal
+	Build string
+	Snake Snake
+}
+
+// PlayOptions set up a game played by Play. The zero value is a standard
+// game on an 11x11 board with the rules defaults.
+type PlayOptions struct {
+	// Seed decides where the snakes start, where food spawns, which edge
+	// royale hazards cover next and the order snakes are asked for their
+	// moves each turn. The same seed and snakes play the same game again.
+	Seed    int64
+	Ruleset bsgf.ViewRuleset
+	Width   int32
+	Height  int32
+	// Timeout for each move, 500ms when 0. Snakes that fail to answer in
+	// time move the same way as the turn before.
+	Timeout time.Duration
+	// MaxTurns ends the game early, 0 for no limit
+	MaxTurns int32
+}
+
+// Play runs a game between players on the standard, solo, royale,
+// constrictor, wrapped and wrapped_constrictor rules and returns it
+// recorded like a game from the engine, marked with engine.MarkLocal. The
+// game ID is derived from the seed, ruleset and players.
+func Play(ctx context.Context, players []Player, opts PlayOptions) (*bsgf.ViewGame, error) {
+	p, err := newPlay(players, opts)
+	if err != nil {
+		return nil, err
+	}
+	for !p.over() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		err = p.step(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	game := p.game
+	game.Game.Status = "complete"
+	game.FirstFrame = game.Frames[0]
+	game.LastTurn = game.Frames[len(game.Frames)-1].Turn
+	game.Events = append(game.Events, bsgf.ViewEvent{Type: bsgf.EventGameEnd, Turn: game.LastTurn})
+	builds := make(map[string]string, len(players))
+	for _, player := range players {
+		builds[player.Name] = player.Build
+	}
+	engine.MarkLocal(game, builds)
+	return game, nil
+}
+
+type play struct {
+	game    *bsgf.ViewGame
+	players []Player
+	rng     *rand.Rand
+	timeout time.Duration
+	name    bsgf.RulesetName
+	opts    PlayOptions
+	// edges of the area royale hazards haven't covered yet
+	minX, maxX, minY, maxY int32
+}
+
+func newPlay(players []Player, opts PlayOptions) (*play, error) {
+	if len(players) == 0 {
+		return nil, errors.New("no players")
+	}
+	if opts.Width == 0 {
+		opts.Width = 11
+	}
+	if opts.Height == 0 {
+		opts.Height = 11
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 500 * time.Millisecond
+	}
+	ruleset := opts.Ruleset
+	if ruleset.Name == "" {
+		ruleset.Name = string(bsgf.RulesetStandard)
+	}
+	if ruleset.Map == "" {
+		ruleset.Map = "standard"
+	}
+	name := ruleset.RulesetName()
+	switch name {
+	case bsgf.RulesetStandard, bsgf.RulesetSolo, bsgf.RulesetRoyale, bsgf.RulesetConstrictor, bsgf.RulesetWrapped, bsgf.RulesetWrappedConstrictor:
+	default:
+		return nil, fmt.Errorf("%w: can't play %s games", bsgf.ErrUnsupportedFormat, ruleset.Name)
+	}
+	if !ruleset.IsSet("foodSpawnChance") && ruleset.FoodSpawnChance == 0 {
+		ruleset.FoodSpawnChance = bsgf.DefaultFoodSpawnChance
+	}
+	if !ruleset.IsSet("minimumFood") && ruleset.MinimumFood == 0 {
+		ruleset.MinimumFood = bsgf.DefaultMinimumFood
+	}
+	if !ruleset.IsSet("damagePerTurn") && ruleset.DamagePerTurn == 0 {
+		ruleset.DamagePerTurn = bsgf.DefaultHazardDamagePerTurn
+	}
+	if name == bsgf.RulesetRoyale {
+		if _, ok := ruleset.Setting("shrinkEveryNTurns"); !ok {
+			settings := make(map[string]json.RawMessage, len(ruleset.Settings)+1)
+			for k, v := range ruleset.Settings {
+				settings[k] = v
+			}
+			settings["shrinkEveryNTurns"] = json.RawMessage("25")
+			ruleset.Settings = settings
+		}
+	}
+
+	seen := make(map[string]bool, len(players))
+	for _, player := range players {
+		if player.Name == "" || seen[player.Name] {
+			return nil, fmt.Errorf("players need unique names, got %q twice or empty", player.Name)
+		}
+		seen[player.Name] = true
+	}
+	p := &play{
+		players: players,
+		rng:     rand.New(rand.NewSource(opts.Seed)),
+		timeout: opts.Timeout,
+		name:    name,
+		opts:    opts,
+		minX:    0,
+		maxX:    opts.Width - 1,
+		minY:    0,
+		maxY:    opts.Height - 1,
+	}
+	p.game = &bsgf.ViewGame{Game: bsgf.ViewGameSettings{
+		ID:      gameID(players, ruleset.Name, opts),
+		Ruleset: ruleset,
+		Timeout: int32(opts.Timeout / time.Millisecond),
+		Status:  "running",
+		Width:   opts.Width,
+		Height:  opts.Height,
+	}}
+	first, err := p.start()
+	if err != nil {
+		return nil, err
+	}
+	p.game.Frames = append(p.game.Frames, *first)
+	return p, nil
+}
+
+// gameID is a hash of everything that decides how a game plays out
+func gameID(players []Player, ruleset string, opts PlayOptions) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d %s %dx%d %d\n", opts.Seed, ruleset, opts.Width, opts.Height, opts.MaxTurns)
+	for _, player := range players {
+		fmt.Fprintf(h, "%s %s %s\n", player.Name, player.URL, player.Build)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// start places the snakes on the standard starting points, in an order
+// picked by the seed, with a food next to each and one in the center
+func (p *play) start() (*bsgf.ViewFrame, error) {
+	w, h := p.opts.Width, p.opts.Height
+	if w < 7 || h < 7 {
+		return nil, fmt.Errorf("board must be at least 7x7, got %dx%d", w, h)
+	}
+	mn, mdX, mdY, mxX, mxY := int32(1), (w-1)/2, (h-1)/2, w-2, h-2
+	corners := []bsgf.ViewCoord{{X: mn, Y: mn}, {X: mn, Y: mxY}, {X: mxX, Y: mn}, {X: mxX, Y: mxY}}
+	edges := []bsgf.ViewCoord{{X: mn, Y: mdY}, {X: mdX, Y: mn}, {X: mdX, Y: mxY}, {X: mxX, Y: mdY}}
+	p.rng.Shuffle(len(corners), func(i, j int) { corners[i], corners[j] = corners[j], corners[i] })
+	p.rng.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+	points := append(corners, edges...)
+	if len(p.players) > len(points) {
+		return nil, fmt.Errorf("at most %d snakes can play, got %d", len(points), len(p.players))
+	}
+	frame := &bsgf.ViewFrame{}
+	for i, player := range p.players {
+		at := points[i]
+		frame.Snakes = append(frame.Snakes, bsgf.ViewSnake{
+			ID:         "snake-" + strconv.Itoa(i+1),
+			Name:       player.Name,
+			URL:        player.URL,
+			Body:       []bsgf.ViewCoord{at, at, at},
+			Health:     maxHealth,
+			HeadType:   "default",
+			TailType:   "default",
+			Latency:    "0",
+			APIVersion: "1",
+			Author:     player.Author,
+		})
+	}
+	if p.name.Constrictor() {
+		return frame, nil
+	}
+	occupied := p.occupied(frame)
+	center := bsgf.ViewCoord{X: mdX, Y: mdY}
+	for _, s := range frame.Snakes {
+		head := s.Body[0]
+		var options []bsgf.ViewCoord
+		for _, d := range [][2]int32{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}} {
+			c := bsgf.ViewCoord{X: head.X + d[0], Y: head.Y + d[1]}
+			if c.InBounds(w, h) && !occupied[c] && c != center {
+				options = append(options, c)
+			}
+		}
+		if len(options) > 0 {
+			c := options[p.rng.Intn(len(options))]
+			frame.Food = append(frame.Food, c)
+			occupied[c] = true
+		}
+	}
+	if !occupied[center] {
+		frame.Food = append(frame.Food, center)
+	}
+	return frame, nil
+}
+
+func (p *play) over() bool {
+	frame := &p.game.Frames[len(p.game.Frames)-1]
+	if p.opts.MaxTurns > 0 && frame.Turn >= p.opts.MaxTurns {
+		return true
+	}
+	alive := 0
+	for i := range frame.Snakes {
+		if frame.Snakes[i].Alive() {
+			alive++
+		}
+	}
+	if p.name == bsgf.RulesetSolo || len(p.players) == 1 {
+		return alive == 0
+	}
+	return alive <= 1
+}
+
+// step asks every live snake for its move and plays the next turn
+func (p *play) step(ctx context.Context) error {
+	prev := &p.game.Frames[len(p.game.Frames)-1]
+	moves, err := p.moves(ctx, prev)
+	if err != nil {
+		return err
+	}
+	w, h := p.opts.Width, p.opts.Height
+	frame := bsgf.ViewFrame{Turn: prev.Turn + 1, Hazards: append([]bsgf.ViewCoord(nil), prev.Hazards...)}
+	food := make(map[bsgf.ViewCoord]bool, len(prev.Food))
+	for _, c := range prev.Food {
+		food[c] = true
+	}
+	eaten := make(map[bsgf.ViewCoord]bool)
+	for i := range prev.Snakes {
+		s := prev.Snakes[i]
+		s.Body = append([]bsgf.ViewCoord(nil), s.Body...)
+		if s.Alive() {
+			m := moves[s.ID]
+			s.Latency, s.Shout = m.latency, m.shout
+			head := m.dir.Apply(s.Body[0])
+			if p.name.Wrapped() {
+				head = head.Wrap(w, h)
+			}
+			before := s.Body
+			s.Body = append([]bsgf.ViewCoord{head}, before[:len(before)-1]...)
+			s.Health--
+			ate := food[head]
+			if n := prev.HazardStacks(head); n > 0 && !ate {
+				s.Health -= p.game.Game.Ruleset.DamagePerTurn * int32(n)
+			}
+			if ate {
+				eaten[head] = true
+			}
+			n := len(s.Body)
+			switch {
+			case p.name.Constrictor():
+				s.Health = maxHealth
+				// the rules skip growing when the tail is already stacked
+				if n < 2 || s.Body[n-1] != s.Body[n-2] {
+					s.Body = append(s.Body, s.Body[n-1])
+				}
+			case ate:
+				s.Health = maxHealth
+				s.Body = append(s.Body, s.Body[n-1])
+			}
+		}
+		frame.Snakes = append(frame.Snakes, s)
+	}
+	for _, c := range prev.Food {
+		if !eaten[c] {
+			frame.Food = append(frame.Food, c)
+		}
+	}
+	p.eliminate(&frame)
+	if !p.name.Constrictor() {
+		p.spawnFood(&frame)
+	}
+	if p.name == bsgf.RulesetRoyale {
+		p.shrink(&frame)
+	}
+	p.game.Frames = append(p.game.Frames, frame)
+	return nil
+}
+
+type move struct {
+	dir     bsgf.Direction
+	latency string
+	shout   string
+}
+
+// moves asks the live snakes for their moves one at a time, in an order
+// picked by the seed
+func (p *play) moves(ctx context.Context, frame *bsgf.ViewFrame) (map[string]move, error) {
+	var order []int
+	for i := range frame.Snakes {
+		if frame.Snakes[i].Alive() {
+			order = append(order, i)
+		}
+	}
+	p.rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	moves := make(map[string]move, len(order))
+	var arena bsgf.MoveArena
+	for _, i := range order {
+		s := &frame.Snakes[i]
+		arena.Reset()
+		state, err := arena.ToMove(p.game, frame.Turn, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		m := move{dir: lastMove(s, p.opts.Width, p.opts.Height), latency: "0"}
+		moveCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		start := time.Now()
+		resp, err := p.players[i].Snake.Move(moveCtx, state)
+		elapsed := time.Since(start)
+		cancel()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err == nil && elapsed <= p.timeout {
+			if dir, err := resp.Direction(); err == nil {
+				m.dir = dir
+			}
+			m.latency = strconv.FormatInt(elapsed.Milliseconds(), 10)
+			m.shout = resp.Shout
+			if len(m.shout) > 256 {
+				m.shout = m.shout[:256]
+			}
+		}
+		moves[s.ID] = m
+	}
+	return moves, nil
+}
+
+// lastMove is the direction s moved last turn, up on the first turn
+func lastMove(s *bsgf.ViewSnake, width, height int32) bsgf.Direction {
+	if len(s.Body) < 2 || s.Body[0] == s.Body[1] {
+		return bsgf.Up
+	}
+	for _, d := range bsgf.Directions {
+		if d.Apply(s.Body[1]).Wrap(width, height) == s.Body[0] || d.Apply(s.Body[1]) == s.Body[0] {
+			return d
+		}
+	}
+	return bsgf.Up
+}
+
+// eliminate marks the snakes that starved or left the board, then the ones
+// that collided, all at once so the order of the snakes doesn't matter
+func (p *play) eliminate(frame *bsgf.ViewFrame) {
+	w, h := p.opts.Width, p.opts.Height
+	var live []*bsgf.ViewSnake
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		if !s.Alive() {
+			continue
+		}
+		switch {
+		case s.Health <= 0:
+			s.Health = 0
+			p.kill(s, frame.Turn, bsgf.CauseOutOfHealth, "")
+		case !s.Body[0].InBounds(w, h):
+			p.kill(s, frame.Turn, bsgf.CauseWallCollision, "")
+		default:
+			live = append(live, s)
+		}
+	}
+	deaths := make(map[*bsgf.ViewSnake]bsgf.ViewDeath)
+	for _, s := range live {
+		head := s.Body[0]
+		if containsCoord(s.Body[1:], head) {
+			deaths[s] = bsgf.ViewDeath{Cause: bsgf.CauseSelfCollision}
+			continue
+		}
+		for _, other := range live {
+			if other == s {
+				continue
+			}
+			if containsCoord(other.Body[1:], head) {
+				deaths[s] = bsgf.ViewDeath{Cause: bsgf.CauseSnakeCollision, EliminatedBy: other.ID}
+				break
+			}
+			if other.Body[0] == head && len(s.Body) <= len(other.Body) {
+				deaths[s] = bsgf.ViewDeath{Cause: bsgf.CauseHeadCollision, EliminatedBy: other.ID}
+				break
+			}
+		}
+	}
+	for _, s := range live {
+		if d, ok := deaths[s]; ok {
+			p.kill(s, frame.Turn, d.Cause, d.EliminatedBy)
+		}
+	}
+}
+
+func (p *play) kill(s *bsgf.ViewSnake, turn int32, cause bsgf.DeathCause, by string) {
+	s.Death = bsgf.ViewDeath{Cause: cause, Turn: turn, EliminatedBy: by}
+	p.game.Events = append(p.game.Events, bsgf.ViewEvent{Type: bsgf.EventElimination, Turn: turn, SnakeID: s.ID, Cause: cause, EliminatedBy: by})
+}
+
+// spawnFood tops food up to minimumFood, or otherwise adds one with a
+// foodSpawnChance percent chance, on a free cell picked by the seed
+func (p *play) spawnFood(frame *bsgf.ViewFrame) {
+	ruleset := &p.game.Game.Ruleset
+	n := int(ruleset.MinimumFood) - len(frame.Food)
+	if n <= 0 {
+		n = 0
+		if ruleset.FoodSpawnChance > 0 && p.rng.Intn(100) < int(ruleset.FoodSpawnChance) {
+			n = 1
+		}
+	}
+	if n == 0 {
+		return
+	}
+	occupied := p.occupied(frame)
+	var free []bsgf.ViewCoord
+	for y := int32(0); y < p.opts.Height; y++ {
+		for x := int32(0); x < p.opts.Width; x++ {
+			if c := (bsgf.ViewCoord{X: x, Y: y}); !occupied[c] {
+				free = append(free, c)
+			}
+		}
+	}
+	for ; n > 0 && len(free) > 0; n-- {
+		i := p.rng.Intn(len(free))
+		frame.Food = append(frame.Food, free[i])
+		free = append(free[:i], free[i+1:]...)
+	}
+}
+
+// shrink covers the next row or column of the board with hazards every
+// shrinkEveryNTurns turns, from an edge picked by the seed
+func (p *play) shrink(frame *bsgf.ViewFrame) {
+	setting, _ := p.game.Game.Ruleset.Setting("shrinkEveryNTurns")
+	every, _ := strconv.Atoi(setting)
+	if every <= 0 || frame.Turn%int32(every) != 0 || p.minX > p.maxX || p.minY > p.maxY {
+		return
+	}
+	switch p.rng.Intn(4) {
+	case 0:
+		p.minX++
+	case 1:
+		p.maxX--
+	case 2:
+		p.minY++
+	case 3:
+		p.maxY--
+	}
+	frame.Hazards = frame.Hazards[:0]
+	for y := int32(0); y < p.opts.Height; y++ {
+		for x := int32(0); x < p.opts.Width; x++ {
+			if x < p.minX || x > p.maxX || y < p.minY || y > p.maxY {
+				frame.Hazards = append(frame.Hazards, bsgf.ViewCoord{X: x, Y: y})
+			}
+		}
+	}
+}
+
+// occupied is every cell with a live snake or food on it
+func (p *play) occupied(frame *bsgf.ViewFrame) map[bsgf.ViewCoord]bool {
+	occupied := make(map[bsgf.ViewCoord]bool)
+	for i := range frame.Snakes {
+		if frame.Snakes[i].Alive() {
+			for _, c := range frame.Snakes[i].Body {
+				occupied[c] = true
+			}
+		}
+	}
+	for _, c := range frame.Food {
+		occupied[c] = true
+	}
+	return occupied
+}
+
+func containsCoord(coords []bsgf.ViewCoord, c bsgf.ViewCoord) bool {
+	for _, o := range coords {
+		if o == c {
+			return true
+		}
+	}
+	return false
+}
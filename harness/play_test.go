@@ -0,0 +1,65 @@
+package harness_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/engine"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+)
+
+func play(t *testing.T, seed int64, ruleset string) *bsgf.ViewGame {
+	t.Helper()
+	players := []harness.Player{
+		{Name: "Alpha", Build: "v1", Snake: harness.NewRandomSnake(1)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(2)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: seed, Ruleset: bsgf.ViewRuleset{Name: ruleset}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestPlaySeed(t *testing.T) {
+	game := play(t, 1, "standard")
+	if diffs := bsgf.DiffGames(game, play(t, 1, "standard")); len(diffs) > 0 {
+		t.Errorf("the same seed played a different game: %v", diffs[0])
+	}
+	other := play(t, 2, "standard")
+	if other.Game.ID == game.Game.ID || len(bsgf.DiffGames(game, other)) == 0 {
+		t.Error("a different seed played the same game")
+	}
+	if !engine.IsLocal(game) || !strings.HasPrefix(game.Game.ID, engine.LocalIDPrefix) {
+		t.Errorf("expected a local ID, got %q", game.Game.ID)
+	}
+	if build := engine.SnakeBuild(&game.Frames[0].Snakes[0]); build != "v1" {
+		t.Errorf("expected Alpha's build, got %q", build)
+	}
+}
+
+func TestPlayRulesets(t *testing.T) {
+	for _, ruleset := range []string{"standard", "solo", "royale", "constrictor", "wrapped", "wrapped_constrictor"} {
+		t.Run(ruleset, func(t *testing.T) {
+			game := play(t, 1, ruleset)
+			if game.Game.Status != "complete" || game.Game.Ruleset.Name != ruleset {
+				t.Errorf("expected a complete %s game, got %+v", ruleset, game.Game)
+			}
+			alive := 0
+			for range game.Frames[len(game.Frames)-1].AliveSnakes() {
+				alive++
+			}
+			// solo games go on until the snake dies
+			if (ruleset == "solo" && alive != 0) || alive > 1 {
+				t.Errorf("game ended with %d snakes alive", alive)
+			}
+		})
+	}
+	_, err := harness.Play(context.Background(), []harness.Player{{Name: "Alpha", Snake: harness.NewRandomSnake(1)}}, harness.PlayOptions{Ruleset: bsgf.ViewRuleset{Name: "unknown"}})
+	if !errors.Is(err, bsgf.ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
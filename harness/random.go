@@ -0,0 +1,55 @@
+package harness
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// RandomSnake moves at random, preferring moves that stay on the board and
+// off every body, for generating games with Play. Moves are drawn from the
+// seed given to NewRandomSnake, so the same seed moves the same way in the
+// same positions.
+type RandomSnake struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func NewRandomSnake(seed int64) *RandomSnake {
+	return &RandomSnake{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (s *RandomSnake) Move(ctx context.Context, state *bsgf.MoveGameState) (*bsgf.MoveBattlesnakeResponse, error) {
+	board := &state.Board
+	wrapped := bsgf.RulesetName(state.Game.Ruleset.Name).Wrapped()
+	blocked := make(map[bsgf.MoveCoord]bool)
+	for _, snake := range board.Snakes {
+		if len(snake.Body) == 0 {
+			continue
+		}
+		// tails move out of the way unless the snake just ate
+		for _, c := range snake.Body[:len(snake.Body)-1] {
+			blocked[c] = true
+		}
+	}
+	var safe []bsgf.Direction
+	for _, d := range bsgf.Directions {
+		dx, dy := d.Delta()
+		c := bsgf.MoveCoord{X: state.You.Head.X + dx, Y: state.You.Head.Y + dy}
+		if wrapped {
+			c = c.Wrap(board.Width, board.Height)
+		}
+		if c.InBounds(board.Width, board.Height) && !blocked[c] {
+			safe = append(safe, d)
+		}
+	}
+	if len(safe) == 0 {
+		safe = bsgf.Directions
+	}
+	s.mu.Lock()
+	d := safe[s.rng.Intn(len(safe))]
+	s.mu.Unlock()
+	return d.Response(), nil
+}
@@ -0,0 +1,32 @@
+package battlesnakegameformat
+
+// HazardDamageAt returns the damage a hazard did on turn, using the frame's
+// HazardDamage when the map changed it and the ruleset's damagePerTurn
+// otherwise, or the rules default when the game doesn't have one.
+func (game *ViewGame) HazardDamageAt(turn int32) (int32, error) {
+	frame, err := game.FrameAt(turn)
+	if err != nil {
+		return 0, err
+	}
+	return game.hazardDamage(frame), nil
+}
+
+func (game *ViewGame) hazardDamage(frame *ViewFrame) int32 {
+	if frame.HazardDamage != 0 {
+		return frame.HazardDamage
+	}
+	r := &game.Game.Ruleset
+	return r.intOr("damagePerTurn", r.DamagePerTurn, DefaultHazardDamagePerTurn)
+}
+
+// HazardStacks returns how many hazards are on c. Maps that stack hazards
+// apply the damage once per stack.
+func (frame *ViewFrame) HazardStacks(c ViewCoord) int {
+	n := 0
+	for _, h := range frame.Hazards {
+		if h == c {
+			n++
+		}
+	}
+	return n
+}
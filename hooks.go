@@ -0,0 +1,116 @@
+package battlesnakegameformat
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Hooks are called as games are decoded, fetched and processed so services
+// built on this package can export their own metrics. Every hook is
+// optional and may be called from several goroutines at once, so hooks
+// must be safe for concurrent use and should return quickly.
+type Hooks struct {
+	// OnDecode is called after every game is decoded, successfully or not
+	OnDecode func(DecodeStats)
+	// OnFetch is called after every request to the engine, including
+	// retried attempts
+	OnFetch func(FetchStats)
+	// OnFrameProcessed is called after a frame is streamed, recorded or
+	// analyzed
+	OnFrameProcessed func(FrameStats)
+}
+
+// DecodeStats describes one decoded game
+type DecodeStats struct {
+	// Format is the encoding that was read, see the DecodeFormat constants
+	Format string
+	// GameID is empty when the game couldn't be decoded
+	GameID string
+	// Bytes is the size of the encoded input
+	Bytes    int
+	Frames   int
+	Duration time.Duration
+	Err      error
+}
+
+// Formats reported in DecodeStats
+const (
+	DecodeFormatArchive   = "archive"
+	DecodeFormatContainer = "container"
+	DecodeFormatJSONL     = "jsonl"
+	DecodeFormatLazy      = "lazy"
+)
+
+// FetchStats describes one request to the engine
+type FetchStats struct {
+	Path string
+	// Attempt is 0 for the first request and counts up on retries
+	Attempt int
+	// StatusCode is 0 when no response was received
+	StatusCode int
+	// Bytes is the size of the response body read
+	Bytes    int
+	Duration time.Duration
+	Err      error
+}
+
+// FrameStats describes one processed frame
+type FrameStats struct {
+	// Stage is what was done to the frame, see the FrameStage constants
+	Stage  string
+	GameID string
+	Turn   int32
+	// Bytes is the size of the frame's json, 0 when it isn't known
+	Bytes    int
+	Duration time.Duration
+}
+
+// Stages reported in FrameStats
+const (
+	FrameStageStream   = "stream"
+	FrameStageRecord   = "record"
+	FrameStageAnalysis = "analysis"
+)
+
+var currentHooks atomic.Pointer[Hooks]
+
+// SetHooks replaces the hooks used by every decoder, client and analyzer in
+// this module. Pass Hooks{} to remove them.
+func SetHooks(h Hooks) {
+	currentHooks.Store(&h)
+}
+
+// CurrentHooks returns the hooks set by SetHooks
+func CurrentHooks() Hooks {
+	h := currentHooks.Load()
+	if h == nil {
+		return Hooks{}
+	}
+	return *h
+}
+
+func reportDecode(format string, start time.Time, size int, game *ViewGame, err error) {
+	stats := DecodeStats{Format: format, Bytes: size, Err: err}
+	if game != nil {
+		stats.GameID = game.Game.ID
+		stats.Frames = len(game.Frames)
+	}
+	reportDecodeStats(start, stats)
+}
+
+func reportDecodeStats(start time.Time, stats DecodeStats) {
+	h := currentHooks.Load()
+	if h == nil || h.OnDecode == nil {
+		return
+	}
+	stats.Duration = time.Since(start)
+	h.OnDecode(stats)
+}
+
+func reportFrame(stage string, gameId string, start time.Time, size int, frame *ViewFrame) {
+	h := currentHooks.Load()
+	if h == nil || h.OnFrameProcessed == nil {
+		return
+	}
+	h.OnFrameProcessed(FrameStats{Stage: stage, GameID: gameId, Turn: frame.Turn, Bytes: size, Duration: time.Since(start)})
+}
@@ -0,0 +1,163 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Version of the archive layout written by Encode. Version 2 added
+// metadata.json next to the game, which readers of version 1 archives
+// reject as a second file.
+const FormatVersion = 2
+
+// Summary of an archive that can be read without keeping every frame in memory
+type ArchiveInfo struct {
+	// Version is the layout of the archive, 1 when it has no metadata.json
+	Version    int
+	Files      []ArchiveFile
+	Sections   []ArchiveSection
+	Game       ViewGameSettings
+	FirstFrame ViewFrame
+	LastFrame  ViewFrame
+	LastTurn   int32
+	FrameCount int
+	// Metadata is nil for archives written before it was added
+	Metadata *Metadata
+}
+
+type ArchiveFile struct {
+	Name             string
+	CompressedSize   uint64
+	UncompressedSize uint64
+}
+
+// ArchiveSection is the size in bytes of a top level field in the game json
+type ArchiveSection struct {
+	Name string
+	Size int64
+}
+
+// Read game settings, the first and last frames and size information from
+// an archive. Frames are streamed one at a time so memory use doesn't grow
+// with the length of the game.
+func DecodeInfo(data []byte) (*ArchiveInfo, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	if isChunkedZip(r) {
+		return chunkedInfo(r)
+	}
+	game, err := gameFile(r)
+	if err != nil {
+		return nil, err
+	}
+	info := ArchiveInfo{Version: FormatVersion}
+	info.Metadata, err = readMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+	if info.Metadata == nil {
+		info.Version = 1
+	}
+	for _, f := range r.File {
+		info.Files = append(info.Files, ArchiveFile{
+			Name:             f.Name,
+			CompressedSize:   f.CompressedSize64,
+			UncompressedSize: f.UncompressedSize64,
+		})
+	}
+	rc, err := game.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening zip archive file: %w", ErrCorruptArchive, err)
+	}
+	defer rc.Close()
+	err = decodeInfo(json.NewDecoder(rc), &info)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading compressed game: %w", ErrCorruptArchive, err)
+	}
+	return &info, nil
+}
+
+func decodeInfo(dec *json.Decoder, info *ArchiveInfo) error {
+	err := expectDelim(dec, '{')
+	if err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, found %v", tok)
+		}
+		start := dec.InputOffset()
+		switch key {
+		case "Game":
+			err = dec.Decode(&info.Game)
+		case "FirstFrame":
+			err = dec.Decode(&info.FirstFrame)
+		case "LastTurn":
+			err = dec.Decode(&info.LastTurn)
+		case "Frames":
+			err = decodeFrames(dec, func(frame *ViewFrame) bool {
+				info.LastFrame = *frame
+				info.FrameCount++
+				return true
+			})
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return fmt.Errorf("error decoding %s: %w", key, err)
+		}
+		info.Sections = append(info.Sections, ArchiveSection{Name: key, Size: dec.InputOffset() - start})
+	}
+	return expectDelim(dec, '}')
+}
+
+// decodeFrames streams a json array of frames, calling fn with each one
+// until it returns false. Every frame passed to fn is newly allocated.
+func decodeFrames(dec *json.Decoder, fn func(frame *ViewFrame) bool) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected array of frames, found %v", tok)
+	}
+	for dec.More() {
+		var frame ViewFrame
+		err = dec.Decode(&frame)
+		if err != nil {
+			return err
+		}
+		if !fn(&frame) {
+			return errStopped
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return fmt.Errorf("unexpected end of json, expected %s", want)
+	}
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %s, found %v", want, tok)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package battlesnakegameformat
+
+import (
+	"unique"
+)
+
+// Intern replaces the snake metadata strings in game with shared copies, so
+// the ID, name, colors and so on repeated in every frame are held in memory
+// once rather than once per frame. Games returned by the Decode functions
+// are already interned.
+func Intern(game *ViewGame) {
+	game.Game.ID = intern(game.Game.ID)
+	internFrame(&game.FirstFrame)
+	for i := range game.Frames {
+		internFrame(&game.Frames[i])
+	}
+	for i := range game.Events {
+		e := &game.Events[i]
+		e.SnakeID = intern(e.SnakeID)
+		e.EliminatedBy = intern(e.EliminatedBy)
+	}
+}
+
+func internFrame(frame *ViewFrame) {
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		s.ID = intern(s.ID)
+		s.Name = intern(s.Name)
+		s.URL = intern(s.URL)
+		s.Color = intern(s.Color)
+		s.HeadType = intern(s.HeadType)
+		s.TailType = intern(s.TailType)
+		s.Latency = intern(s.Latency)
+		s.Shout = intern(s.Shout)
+		s.Squad = intern(s.Squad)
+		s.APIVersion = intern(s.APIVersion)
+		s.Author = intern(s.Author)
+		s.Death.Cause = DeathCause(intern(string(s.Death.Cause)))
+		s.Death.EliminatedBy = intern(s.Death.EliminatedBy)
+	}
+}
+
+func intern(s string) string {
+	if s == "" {
+		return s
+	}
+	return unique.Make(s).Value()
+}
@@ -0,0 +1,65 @@
+// Command genschema writes the JSON Schemas for every payload kind and the
+// OpenAPI definitions into the schemas directory. Run it with go generate
+// from the repository root. With -check it writes nothing and fails if any
+// file is missing or out of date, so CI can catch structs and schemas
+// drifting apart.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+const dir = "schemas"
+
+func main() {
+	check := flag.Bool("check", false, "fail if the files in schemas are out of date instead of writing them")
+	flag.Parse()
+
+	files := make(map[string][]byte)
+	for _, kind := range bsgf.SchemaKinds {
+		data, err := bsgf.Schema(kind)
+		if err != nil {
+			log.Fatal(err)
+		}
+		files[string(kind)+".schema.json"] = data
+	}
+	for _, spec := range bsgf.OpenAPISpecs {
+		data, err := bsgf.OpenAPI(spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		files["openapi-"+string(spec)+".json"] = data
+	}
+
+	if *check {
+		stale := 0
+		for name, data := range files {
+			existing, err := ioutil.ReadFile(filepath.Join(dir, name))
+			if err != nil || !bytes.Equal(existing, data) {
+				log.Printf("%s is out of date", filepath.Join(dir, name))
+				stale++
+			}
+		}
+		if stale > 0 {
+			log.Fatal("run go generate to update the schemas")
+		}
+		return
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, data := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), data, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
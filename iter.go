@@ -0,0 +1,71 @@
+package battlesnakegameformat
+
+import (
+	"iter"
+)
+
+// AllFrames yields a pointer to every frame in order, without copying.
+func (game *ViewGame) AllFrames() iter.Seq[*ViewFrame] {
+	return func(yield func(*ViewFrame) bool) {
+		for i := range game.Frames {
+			if !yield(&game.Frames[i]) {
+				return
+			}
+		}
+	}
+}
+
+// SnakeFrames yields every frame snakeId appears in along with the snake's
+// state in that frame.
+func (game *ViewGame) SnakeFrames(snakeId string) iter.Seq2[*ViewFrame, *ViewSnake] {
+	return func(yield func(*ViewFrame, *ViewSnake) bool) {
+		for i := range game.Frames {
+			frame := &game.Frames[i]
+			s, ok := findSnake(frame, snakeId)
+			if ok && !yield(frame, s) {
+				return
+			}
+		}
+	}
+}
+
+// AllSnakes yields a pointer to every snake in the frame, eliminated or not.
+func (frame *ViewFrame) AllSnakes() iter.Seq[*ViewSnake] {
+	return func(yield func(*ViewSnake) bool) {
+		for i := range frame.Snakes {
+			if !yield(&frame.Snakes[i]) {
+				return
+			}
+		}
+	}
+}
+
+// AliveSnakes yields the snakes that haven't been eliminated.
+func (frame *ViewFrame) AliveSnakes() iter.Seq[*ViewSnake] {
+	return func(yield func(*ViewSnake) bool) {
+		for i := range frame.Snakes {
+			s := &frame.Snakes[i]
+			if s.Death.Eliminated() {
+				continue
+			}
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// EliminatedSnakes yields the snakes that have been eliminated.
+func (frame *ViewFrame) EliminatedSnakes() iter.Seq[*ViewSnake] {
+	return func(yield func(*ViewSnake) bool) {
+		for i := range frame.Snakes {
+			s := &frame.Snakes[i]
+			if !s.Death.Eliminated() {
+				continue
+			}
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
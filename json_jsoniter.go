@@ -0,0 +1,41 @@
+//go:build jsoniter
+
+package battlesnakegameformat
+
+import (
+	"io"
+	"io/ioutil"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// JSONBackend names the json library used for archives, chosen at build
+// time. Build with -tags jsoniter to use github.com/json-iterator/go.
+const JSONBackend = "jsoniter"
+
+// Configured to match encoding/json, including custom marshalers and
+// ,string tags
+var jsoniterAPI = jsoniter.ConfigCompatibleWithStandardLibrary
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	return jsoniterAPI.Marshal(v)
+}
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	return jsoniterAPI.Unmarshal(data, v)
+}
+
+// decodeGameJSON parses a whole game from r. jsoniter is fastest on a full
+// buffer, so this trades the streaming parse's lower peak memory for speed.
+func decodeGameJSON(r io.Reader) (*ViewGame, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var game ViewGame
+	err = jsoniterAPI.Unmarshal(data, &game)
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
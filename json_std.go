@@ -0,0 +1,26 @@
+//go:build !jsoniter
+
+package battlesnakegameformat
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONBackend names the json library used for archives, chosen at build
+// time. Build with -tags jsoniter to use github.com/json-iterator/go.
+const JSONBackend = "encoding/json"
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// decodeGameJSON parses a whole game from r, streaming frames to keep peak
+// memory down
+func decodeGameJSON(r io.Reader) (*ViewGame, error) {
+	return decodeGameStream(r, nil)
+}
@@ -0,0 +1,81 @@
+package battlesnakegameformat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JSON lines format - a header line with the game settings followed by one
+// line per frame. Easy to stream and to process with line based tools.
+
+type jsonlHeader struct {
+	Game     ViewGameSettings `json:"Game"`
+	LastTurn int32            `json:"LastTurn"`
+	Events   []ViewEvent      `json:"Events,omitempty"`
+}
+
+// Write game as JSON lines to w. When w fails the error is an *IOError.
+func EncodeJSONL(game *ViewGame, w io.Writer) error {
+	out := &failureWriter{w: w, ioFailure: ioFailure{op: "write"}}
+	enc := json.NewEncoder(out)
+	err := enc.Encode(jsonlHeader{Game: game.Game, LastTurn: game.LastTurn, Events: game.Events})
+	if err != nil {
+		return out.blame(fmt.Errorf("error writing game header: %w", err))
+	}
+	for i := range game.Frames {
+		err = enc.Encode(&game.Frames[i])
+		if err != nil {
+			return out.blame(fmt.Errorf("error writing frame %d: %w", i, err))
+		}
+	}
+	return nil
+}
+
+// Read a game written by EncodeJSONL
+func DecodeJSONL(data []byte) (*ViewGame, error) {
+	start := time.Now()
+	game, err := decodeJSONL(data)
+	reportDecode(DecodeFormatJSONL, start, len(data), game, err)
+	return game, err
+}
+
+func decodeJSONL(data []byte) (*ViewGame, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("%w: error reading game header: %w", ErrCorruptArchive, err)
+		}
+		return nil, fmt.Errorf("%w: missing game header line", ErrCorruptArchive)
+	}
+	var header jsonlHeader
+	err := json.Unmarshal(scanner.Bytes(), &header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling game header: %w", ErrCorruptArchive, err)
+	}
+	game := ViewGame{Game: header.Game, LastTurn: header.LastTurn, Events: header.Events}
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var frame ViewFrame
+		err = unmarshalJSON(line, &frame)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error unmarshalling frame %d: %w", ErrCorruptArchive, len(game.Frames), err)
+		}
+		game.Frames = append(game.Frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error reading frames: %w", ErrCorruptArchive, err)
+	}
+	if len(game.Frames) > 0 {
+		game.FirstFrame = game.Frames[0]
+	}
+	Intern(&game)
+	return &game, nil
+}
@@ -0,0 +1,25 @@
+package battlesnakegameformat_test
+
+import (
+	"bytes"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func TestJSONLRoundTrip(t *testing.T) {
+	game := testGame(t, bsgf.RulesetRoyale)
+	var buf bytes.Buffer
+	err := bsgf.EncodeJSONL(game, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(buf.Bytes(), []byte("\n")); lines != len(game.Frames)+1 {
+		t.Errorf("expected a header and %d frame lines, got %d lines", len(game.Frames), lines)
+	}
+	got, err := bsgf.DecodeJSONL(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameGame(t, game, got)
+}
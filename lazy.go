@@ -0,0 +1,394 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LazyGame is an archive with the settings decoded up front and each frame
+// decoded the first time it's accessed. The uncompressed json is kept in
+// memory along with the offset of every frame. Chunked archives only have
+// a chunk uncompressed when one of its frames is first accessed. It is safe
+// for concurrent use.
+type LazyGame struct {
+	Game       ViewGameSettings
+	FirstFrame ViewFrame
+	LastTurn   int32
+	Events     []ViewEvent
+
+	data   []byte
+	spans  []jsonSpan
+	chunks []*lazyChunk
+	mu     sync.Mutex
+	frames []*ViewFrame
+}
+
+// lazyChunk is one chunk of a chunked archive, uncompressed and indexed on
+// first use
+type lazyChunk struct {
+	entry *ChunkEntry
+	file  *zip.File
+	// index of the chunk's first frame in the game
+	first int
+
+	once  sync.Once
+	data  []byte
+	spans []jsonSpan
+	err   error
+}
+
+// Byte range of a json value
+type jsonSpan struct {
+	start, end int
+}
+
+// DecodeLazy uncompresses data and indexes its frames without decoding them.
+func DecodeLazy(data []byte) (*LazyGame, error) {
+	start := time.Now()
+	game, err := decodeLazy(data)
+	if err != nil {
+		reportDecode(DecodeFormatLazy, start, len(data), nil, err)
+		return nil, err
+	}
+	reportDecodeStats(start, DecodeStats{Format: DecodeFormatLazy, GameID: game.Game.ID, Bytes: len(data), Frames: game.FrameCount()})
+	return game, nil
+}
+
+func decodeLazy(data []byte) (*LazyGame, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	if isChunkedZip(r) {
+		return lazyChunked(r)
+	}
+	var buf bytes.Buffer
+	err = readArchiveInto(r, &buf)
+	if err != nil {
+		return nil, err
+	}
+	game := &LazyGame{data: buf.Bytes()}
+	err = game.index()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error indexing compressed game: %w", ErrCorruptArchive, err)
+	}
+	game.frames = make([]*ViewFrame, len(game.spans))
+	game.Game.ID = intern(game.Game.ID)
+	internFrame(&game.FirstFrame)
+	return game, nil
+}
+
+// lazyChunked is DecodeLazy for the chunked layout. Only the first chunk
+// is uncompressed, for FirstFrame.
+func lazyChunked(r *zip.Reader) (*LazyGame, error) {
+	manifest, files, err := chunkManifest(r)
+	if err != nil {
+		return nil, err
+	}
+	game := &LazyGame{Game: manifest.Game, LastTurn: manifest.LastTurn, Events: manifest.Events}
+	n := 0
+	for i := range manifest.Chunks {
+		entry := &manifest.Chunks[i]
+		game.chunks = append(game.chunks, &lazyChunk{entry: entry, file: files[entry.File], first: n})
+		n += entry.Frames
+	}
+	game.frames = make([]*ViewFrame, n)
+	game.Game.ID = intern(game.Game.ID)
+	if n > 0 {
+		first, err := game.Frame(0)
+		if err != nil {
+			return nil, err
+		}
+		game.FirstFrame = *first.Clone()
+	}
+	return game, nil
+}
+
+// FrameCount is the number of frames in the game
+func (game *LazyGame) FrameCount() int {
+	return len(game.frames)
+}
+
+// frameJSON returns the json of frame i, with the chunk file it's in and
+// its offsets there for a chunked archive
+func (game *LazyGame) frameJSON(i int) ([]byte, string, jsonSpan, error) {
+	if game.chunks == nil {
+		span := game.spans[i]
+		return game.data[span.start:span.end], "", span, nil
+	}
+	c := sort.Search(len(game.chunks), func(c int) bool { return game.chunks[c].first > i }) - 1
+	chunk := game.chunks[c]
+	chunk.once.Do(chunk.load)
+	if chunk.err != nil {
+		return nil, "", jsonSpan{}, chunk.err
+	}
+	span := chunk.spans[i-chunk.first]
+	return chunk.data[span.start:span.end], chunk.entry.File, span, nil
+}
+
+func (c *lazyChunk) load() {
+	c.data, c.err = readZipFile(c.file)
+	if c.err != nil {
+		return
+	}
+	c.err = checkChunk(c.entry, c.data)
+	if c.err != nil {
+		return
+	}
+	c.spans, c.err = indexArray(c.data, skipSpace(c.data, 0), len(c.data))
+	if c.err != nil {
+		c.err = fmt.Errorf("%w: error indexing %s: %w", ErrCorruptArchive, c.entry.File, c.err)
+		return
+	}
+	if len(c.spans) != c.entry.Frames {
+		c.err = fmt.Errorf("%w: %s has %d frames, manifest says %d", ErrCorruptArchive, c.entry.File, len(c.spans), c.entry.Frames)
+	}
+}
+
+// Frame returns the frame at index i, decoding it on first access. The frame
+// is shared by every caller and must not be modified.
+func (game *LazyGame) Frame(i int) (*ViewFrame, error) {
+	if i < 0 || i >= len(game.frames) {
+		return nil, fmt.Errorf("%w: no frame at index %d", ErrFrameNotFound, i)
+	}
+	game.mu.Lock()
+	frame := game.frames[i]
+	game.mu.Unlock()
+	if frame != nil {
+		return frame, nil
+	}
+	raw, _, _, err := game.frameJSON(i)
+	if err != nil {
+		return nil, err
+	}
+	var f ViewFrame
+	err = unmarshalJSON(raw, &f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling frame %d: %w", ErrCorruptArchive, i, err)
+	}
+	internFrame(&f)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	if game.frames[i] == nil {
+		game.frames[i] = &f
+	}
+	return game.frames[i], nil
+}
+
+// FrameAt returns the frame recorded for turn, looked up the same way as
+// (*ViewGame).FrameAt.
+func (game *LazyGame) FrameAt(turn int32) (*ViewFrame, error) {
+	if turn >= 0 && int(turn) < len(game.frames) {
+		t, err := game.turnOf(int(turn))
+		if err != nil {
+			return nil, err
+		}
+		if t == turn {
+			return game.Frame(int(turn))
+		}
+	}
+	var err error
+	i := sort.Search(len(game.frames), func(i int) bool {
+		t, terr := game.turnOf(i)
+		if terr != nil && err == nil {
+			err = terr
+		}
+		return t >= turn
+	})
+	if err != nil {
+		return nil, err
+	}
+	if i < len(game.frames) {
+		if t, _ := game.turnOf(i); t == turn {
+			return game.Frame(i)
+		}
+	}
+	return nil, fmt.Errorf("%w: no frame for turn %d", ErrFrameNotFound, turn)
+}
+
+// turnOf reads the turn of frame i without keeping the rest of the frame
+func (game *LazyGame) turnOf(i int) (int32, error) {
+	game.mu.Lock()
+	frame := game.frames[i]
+	game.mu.Unlock()
+	if frame != nil {
+		return frame.Turn, nil
+	}
+	raw, _, _, err := game.frameJSON(i)
+	if err != nil {
+		return 0, err
+	}
+	var t struct {
+		Turn int32 `json:"Turn"`
+	}
+	err = unmarshalJSON(raw, &t)
+	if err != nil {
+		return 0, fmt.Errorf("%w: error unmarshalling frame %d: %w", ErrCorruptArchive, i, err)
+	}
+	return t.Turn, nil
+}
+
+// Load decodes every frame and returns the full game
+func (game *LazyGame) Load() (*ViewGame, error) {
+	full := &ViewGame{
+		Game:       game.Game,
+		FirstFrame: game.FirstFrame,
+		LastTurn:   game.LastTurn,
+		Events:     game.Events,
+		Frames:     make([]ViewFrame, 0, len(game.frames)),
+	}
+	for i := range game.frames {
+		frame, err := game.Frame(i)
+		if err != nil {
+			return nil, err
+		}
+		full.Frames = append(full.Frames, *frame.Clone())
+	}
+	return full, nil
+}
+
+// index decodes the top level fields other than Frames and records where
+// each frame is
+func (game *LazyGame) index() error {
+	data := game.data
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return fmt.Errorf("expected {")
+	}
+	i = skipSpace(data, i+1)
+	for i < len(data) && data[i] != '}' {
+		end, err := skipValue(data, i)
+		if err != nil {
+			return err
+		}
+		var key string
+		err = json.Unmarshal(data[i:end], &key)
+		if err != nil {
+			return fmt.Errorf("expected object key at offset %d", i)
+		}
+		i = skipSpace(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return fmt.Errorf("expected : after %s", key)
+		}
+		i = skipSpace(data, i+1)
+		end, err = skipValue(data, i)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", key, err)
+		}
+		value := data[i:end]
+		switch key {
+		case "Game":
+			err = json.Unmarshal(value, &game.Game)
+		case "FirstFrame":
+			err = json.Unmarshal(value, &game.FirstFrame)
+		case "LastTurn":
+			err = json.Unmarshal(value, &game.LastTurn)
+		case "Events":
+			err = json.Unmarshal(value, &game.Events)
+		case "Frames":
+			game.spans, err = indexArray(data, i, end)
+		}
+		if err != nil {
+			return fmt.Errorf("error decoding %s: %w", key, err)
+		}
+		i = skipSpace(data, end)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	if i >= len(data) {
+		return fmt.Errorf("unexpected end of json, expected }")
+	}
+	return nil
+}
+
+// indexArray returns the spans of the frames in the json array of frames
+// from start to end
+func indexArray(data []byte, start, end int) ([]jsonSpan, error) {
+	data = data[:end]
+	if start >= len(data) {
+		return nil, fmt.Errorf("unexpected end of json")
+	}
+	if data[start] == 'n' {
+		return nil, nil
+	}
+	if data[start] != '[' {
+		return nil, fmt.Errorf("expected array of frames")
+	}
+	var spans []jsonSpan
+	i := skipSpace(data, start+1)
+	for i < len(data) && data[i] != ']' {
+		next, err := skipValue(data, i)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, jsonSpan{i, next})
+		i = skipSpace(data, next)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	return spans, nil
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipValue returns the offset just past the json value starting at i. The
+// value isn't validated beyond matching brackets and quotes, json.Unmarshal
+// catches anything else once it's decoded.
+func skipValue(data []byte, i int) (int, error) {
+	if i >= len(data) {
+		return 0, fmt.Errorf("unexpected end of json")
+	}
+	switch data[i] {
+	case '"':
+		for j := i + 1; j < len(data); j++ {
+			switch data[j] {
+			case '\\':
+				j++
+			case '"':
+				return j + 1, nil
+			}
+		}
+		return 0, fmt.Errorf("unterminated string at offset %d", i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '"':
+				end, err := skipValue(data, j)
+				if err != nil {
+					return 0, err
+				}
+				j = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("unterminated value at offset %d", i)
+	}
+	j := i
+	for j < len(data) && data[j] != ',' && data[j] != '}' && data[j] != ']' &&
+		data[j] != ' ' && data[j] != '\t' && data[j] != '\n' && data[j] != '\r' {
+		j++
+	}
+	if j == i {
+		return 0, fmt.Errorf("unexpected %q at offset %d", data[i], i)
+	}
+	return j, nil
+}
@@ -0,0 +1,243 @@
+package battlesnakegameformat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Legacy v0 games - archives from before the current engine, kept as the
+// move requests sent each turn: one per line or a json array. Two shapes
+// are read. 2017 requests have game_id, health_points and [x, y] coords,
+// 2018 requests wrap every array in {"object": "list", "data": [...]} and
+// every point in {"object": "point", ...}. Both put y=0 at the top of the
+// board, so y is flipped on the way in.
+
+type legacyTurn struct {
+	// 2017 game id, a string
+	GameID json.RawMessage `json:"game_id"`
+	// 2018 game id, a number
+	ID     json.RawMessage `json:"id"`
+	Width  int32           `json:"width"`
+	Height int32           `json:"height"`
+	Turn   int32           `json:"turn"`
+	Snakes json.RawMessage `json:"snakes"`
+	Food   json.RawMessage `json:"food"`
+}
+
+type legacySnake struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Taunt        string          `json:"taunt"`
+	HealthPoints *int32          `json:"health_points"`
+	Health       int32           `json:"health"`
+	Coords       json.RawMessage `json:"coords"`
+	Body         json.RawMessage `json:"body"`
+}
+
+type legacyList struct {
+	Object string          `json:"object"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type legacyPoint struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+}
+
+// IsLegacyV0 is true if data looks like a legacy v0 game
+func IsLegacyV0(data []byte) bool {
+	turns, err := legacyTurns(data)
+	if err != nil || len(turns) == 0 {
+		return false
+	}
+	var probe struct {
+		GameID json.RawMessage `json:"game_id"`
+		Snakes json.RawMessage `json:"snakes"`
+	}
+	if json.Unmarshal(turns[0], &probe) != nil || probe.Snakes == nil {
+		return false
+	}
+	var list legacyList
+	return probe.GameID != nil || (json.Unmarshal(probe.Snakes, &list) == nil && list.Object == "list")
+}
+
+// DecodeLegacyV0 converts a legacy v0 game. Old requests don't say why a
+// snake died, only that it's gone, so deaths are worked out the same way as
+// for DecodeRulesCLI.
+func DecodeLegacyV0(data []byte) (*ViewGame, error) {
+	turns, err := legacyTurns(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("%w: legacy game has no turns", ErrCorruptArchive)
+	}
+	game := ViewGame{Game: ViewGameSettings{
+		Ruleset: ViewRuleset{Name: string(RulesetStandard)},
+		Status:  "complete",
+	}}
+	for i, raw := range turns {
+		state, id, err := legacyState(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error converting legacy turn %d: %w", ErrCorruptArchive, i, err)
+		}
+		if i == 0 {
+			game.Game.ID = id
+			game.Game.Width = state.Board.Width
+			game.Game.Height = state.Board.Height
+		}
+		game.Frames = append(game.Frames, game.frameFromRulesCLI(state))
+	}
+	game.FirstFrame = game.Frames[0]
+	game.LastTurn = game.Frames[len(game.Frames)-1].Turn
+	Intern(&game)
+	return &game, nil
+}
+
+// legacyTurns splits data into one json object per turn
+func legacyTurns(data []byte) ([]json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var turns []json.RawMessage
+		err := json.Unmarshal(trimmed, &turns)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error unmarshalling legacy turns: %w", ErrCorruptArchive, err)
+		}
+		return turns, nil
+	}
+	var turns []json.RawMessage
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(trimmed)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) > 0 {
+			turns = append(turns, json.RawMessage(append([]byte(nil), line...)))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error reading legacy turns: %w", ErrCorruptArchive, err)
+	}
+	return turns, nil
+}
+
+// legacyState converts one legacy request to the current move API, along
+// with the game ID
+func legacyState(raw json.RawMessage) (*MoveGameState, string, error) {
+	var turn legacyTurn
+	err := json.Unmarshal(raw, &turn)
+	if err != nil {
+		return nil, "", err
+	}
+	id, err := legacyID(turn.GameID, turn.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	state := &MoveGameState{
+		Game:  MoveGame{ID: id, Ruleset: MoveRuleset{Name: string(RulesetStandard)}},
+		Turn:  turn.Turn,
+		Board: MoveBoard{Width: turn.Width, Height: turn.Height},
+	}
+	food, err := legacyPoints(turn.Food, turn.Height)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading food: %w", err)
+	}
+	state.Board.Food = convertCoords(food)
+	var snakes []legacySnake
+	err = json.Unmarshal(legacyData(turn.Snakes), &snakes)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading snakes: %w", err)
+	}
+	for _, s := range snakes {
+		points := s.Coords
+		if points == nil {
+			points = s.Body
+		}
+		body, err := legacyPoints(points, turn.Height)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading snake %s: %w", s.ID, err)
+		}
+		snake := MoveBattlesnake{
+			ID:     s.ID,
+			Name:   s.Name,
+			Health: s.Health,
+			Body:   convertCoords(body),
+			Length: int32(len(body)),
+			Shout:  s.Taunt,
+		}
+		if s.HealthPoints != nil {
+			snake.Health = *s.HealthPoints
+		}
+		if len(body) > 0 {
+			snake.Head = convertCoord(body[0])
+		}
+		state.Board.Snakes = append(state.Board.Snakes, snake)
+	}
+	return state, id, nil
+}
+
+func legacyID(gameId, id json.RawMessage) (string, error) {
+	raw := gameId
+	if raw == nil {
+		raw = id
+	}
+	if raw == nil {
+		return "", nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, nil
+	}
+	var n json.Number
+	err := json.Unmarshal(raw, &n)
+	if err != nil {
+		return "", fmt.Errorf("game id %s is not a string or number", raw)
+	}
+	if _, err := strconv.ParseInt(n.String(), 10, 64); err != nil {
+		return "", fmt.Errorf("game id %s is not an integer", raw)
+	}
+	return n.String(), nil
+}
+
+// legacyData unwraps a 2018 list object, returning anything else as is
+func legacyData(raw json.RawMessage) json.RawMessage {
+	var list legacyList
+	if json.Unmarshal(raw, &list) == nil && list.Object == "list" {
+		return list.Data
+	}
+	return raw
+}
+
+// legacyPoints reads [x, y] pairs or point objects, flipping y
+func legacyPoints(raw json.RawMessage, height int32) ([]ViewCoord, error) {
+	raw = legacyData(raw)
+	if raw == nil {
+		return nil, nil
+	}
+	var items []json.RawMessage
+	err := json.Unmarshal(raw, &items)
+	if err != nil {
+		return nil, err
+	}
+	coords := make([]ViewCoord, 0, len(items))
+	for _, item := range items {
+		var c ViewCoord
+		var pair []int32
+		var point legacyPoint
+		if json.Unmarshal(item, &pair) == nil {
+			if len(pair) != 2 {
+				return nil, fmt.Errorf("point %s doesn't have 2 values", item)
+			}
+			c = ViewCoord{X: pair[0], Y: pair[1]}
+		} else if json.Unmarshal(item, &point) == nil {
+			c = ViewCoord{X: point.X, Y: point.Y}
+		} else {
+			return nil, fmt.Errorf("invalid point %s", item)
+		}
+		c.Y = height - 1 - c.Y
+		coords = append(coords, c)
+	}
+	return coords, nil
+}
@@ -0,0 +1,55 @@
+package battlesnakegameformat_test
+
+import (
+	"reflect"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+const legacy2017 = `{"game_id":"g2017","width":3,"height":3,"turn":0,"snakes":[{"id":"a","name":"A","taunt":"hi","health_points":100,"coords":[[0,0],[0,1]]}],"food":[[2,2]]}
+{"game_id":"g2017","width":3,"height":3,"turn":1,"snakes":[{"id":"a","name":"A","health_points":99,"coords":[[1,0],[0,0]]}],"food":[[2,2]]}
+`
+
+const legacy2018 = `[
+{"id":7,"width":3,"height":3,"turn":0,"snakes":{"object":"list","data":[{"id":"a","name":"A","health":100,"body":{"object":"list","data":[{"object":"point","x":0,"y":0},{"object":"point","x":0,"y":1}]}}]},"food":{"object":"list","data":[{"object":"point","x":2,"y":2}]}},
+{"id":7,"width":3,"height":3,"turn":1,"snakes":{"object":"list","data":[{"id":"a","name":"A","health":99,"body":{"object":"list","data":[{"object":"point","x":1,"y":0},{"object":"point","x":0,"y":0}]}}]},"food":{"object":"list","data":[{"object":"point","x":2,"y":2}]}}
+]`
+
+func TestDecodeLegacyV0(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		data string
+		id   string
+	}{
+		{"2017", legacy2017, "g2017"},
+		{"2018", legacy2018, "7"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if !bsgf.IsLegacyV0([]byte(test.data)) {
+				t.Fatal("not detected as a legacy game")
+			}
+			game, err := bsgf.DecodeLegacyV0([]byte(test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if game.Game.ID != test.id || game.Game.Width != 3 || game.Game.Height != 3 || game.LastTurn != 1 || len(game.Frames) != 2 {
+				t.Fatalf("unexpected game %+v with %d frames", game.Game, len(game.Frames))
+			}
+			// y=0 was the top row
+			snake := game.Frames[1].Snakes[0]
+			if want := []bsgf.ViewCoord{{X: 1, Y: 2}, {X: 0, Y: 2}}; !reflect.DeepEqual(snake.Body, want) {
+				t.Errorf("expected body %v, got %v", want, snake.Body)
+			}
+			if snake.Health != 99 {
+				t.Errorf("expected health 99, got %d", snake.Health)
+			}
+			if want := []bsgf.ViewCoord{{X: 2, Y: 0}}; !reflect.DeepEqual(game.Frames[0].Food, want) {
+				t.Errorf("expected food %v, got %v", want, game.Frames[0].Food)
+			}
+		})
+	}
+	if bsgf.IsLegacyV0([]byte(`{"Game":{}}`)) {
+		t.Error("a current game json is not legacy")
+	}
+}
@@ -0,0 +1,49 @@
+package battlesnakegameformat
+
+import (
+	"iter"
+)
+
+// Alive is true if the snake hasn't been eliminated.
+func (s *ViewSnake) Alive() bool {
+	return !s.Death.Eliminated()
+}
+
+// IsAliveAt is true if snakeId is on the board and not eliminated on turn.
+func (game *ViewGame) IsAliveAt(snakeId string, turn int32) bool {
+	frame, err := game.FrameAt(turn)
+	if err != nil {
+		return false
+	}
+	s, ok := findSnake(frame, snakeId)
+	return ok && s.Alive()
+}
+
+// DeathTurn returns the turn snakeId was eliminated on, or false if it
+// survived to the end of the game or isn't in it.
+func (game *ViewGame) DeathTurn(snakeId string) (int32, bool) {
+	last, err := game.FinalFrame()
+	if err != nil {
+		return 0, false
+	}
+	s, ok := findSnake(last, snakeId)
+	if !ok || s.Alive() {
+		return 0, false
+	}
+	return s.Death.Turn, true
+}
+
+// FramesAlive yields the frames where snakeId is alive, along with the
+// snake's state in that frame.
+func (game *ViewGame) FramesAlive(snakeId string) iter.Seq2[*ViewFrame, *ViewSnake] {
+	return func(yield func(*ViewFrame, *ViewSnake) bool) {
+		for frame, s := range game.SnakeFrames(snakeId) {
+			if !s.Alive() {
+				continue
+			}
+			if !yield(frame, s) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// Limits bound how much an untrusted archive can make DecodeWithLimits
+// allocate. Zero fields are not limited.
+type Limits struct {
+	// MaxDecompressedSize is the most bytes of json an archive may hold
+	MaxDecompressedSize int64
+	MaxFrames           int
+	// MaxSnakes is the most snakes in any one frame
+	MaxSnakes     int
+	MaxBodyLength int
+	// MaxWidth and MaxHeight bound the board
+	MaxWidth, MaxHeight int32
+	// MaxPerCell bounds snake length and the amount of food as a multiple
+	// of the number of cells on the board. No real snake gets much longer
+	// than the board has cells, and food can't share a cell.
+	MaxPerCell float64
+}
+
+// DefaultLimits are generous for real games, which rarely pass a few
+// thousand turns, while keeping a hostile archive well below a gigabyte.
+var DefaultLimits = Limits{
+	MaxDecompressedSize: 256 << 20,
+	MaxFrames:           50000,
+	MaxSnakes:           64,
+	MaxBodyLength:       10000,
+	MaxWidth:            500,
+	MaxHeight:           500,
+	MaxPerCell:          2,
+}
+
+// LimitError is returned when an archive goes over one of its Limits
+type LimitError struct {
+	// Limit is the name of the Limits field that was exceeded
+	Limit string
+	Max   int64
+	// Got is what the archive had, as far as it was read
+	Got int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: archive is over %s of %d (got %d)", ErrLimitExceeded, e.Limit, e.Max, e.Got)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// DecodeWithLimits is Decode for archives from untrusted sources. It stops
+// with a *LimitError as soon as the archive goes over a limit, before the
+// rest of it is uncompressed. Limits relative to the board are checked as
+// frames arrive when the settings come first, as they do in archives this
+// package writes, and over the whole game otherwise. Chunked archives are
+// checked a chunk at a time.
+func DecodeWithLimits(data []byte, limits Limits) (*ViewGame, error) {
+	start := time.Now()
+	game, err := decodeWithLimits(data, limits)
+	reportDecode(DecodeFormatArchive, start, len(data), game, err)
+	return game, err
+}
+
+func decodeWithLimits(data []byte, limits Limits) (*ViewGame, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	if isChunkedZip(r) {
+		return decodeChunkedWithLimits(r, limits)
+	}
+	f, err := gameFile(r)
+	if err != nil {
+		return nil, err
+	}
+	max := limits.MaxDecompressedSize
+	if max > 0 && f.UncompressedSize64 > uint64(max) {
+		return nil, &LimitError{Limit: "MaxDecompressedSize", Max: max, Got: int64(f.UncompressedSize64)}
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening zip archive file: %w", ErrCorruptArchive, err)
+	}
+	defer rc.Close()
+	// the size in the zip header can't be trusted
+	in := &sizeLimitReader{r: rc, max: max}
+	var frames []ViewFrame
+	var limitErr *LimitError
+	// sized is set when the board size was known for the first frame
+	sized := false
+	game, err := decodeGameStream(in, func(settings *ViewGameSettings, frame *ViewFrame) bool {
+		if limits.MaxFrames > 0 && len(frames) >= limits.MaxFrames {
+			limitErr = &LimitError{Limit: "MaxFrames", Max: int64(limits.MaxFrames), Got: int64(len(frames) + 1)}
+			return false
+		}
+		if len(frames) == 0 {
+			if limitErr = limits.checkBoard(settings); limitErr != nil {
+				return false
+			}
+			sized = settings.Width > 0 && settings.Height > 0
+		}
+		limitErr = limits.checkFrame(settings, frame)
+		if limitErr != nil {
+			return false
+		}
+		frames = append(frames, *frame)
+		return true
+	})
+	if limitErr != nil {
+		return nil, limitErr
+	}
+	if in.exceeded {
+		return nil, &LimitError{Limit: "MaxDecompressedSize", Max: max, Got: in.read}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling compressed game: %w", ErrCorruptArchive, err)
+	}
+	if limitErr = limits.checkBoard(&game.Game); limitErr != nil {
+		return nil, limitErr
+	}
+	if limitErr = limits.checkFrame(&game.Game, &game.FirstFrame); limitErr != nil {
+		return nil, limitErr
+	}
+	if !sized {
+		// the settings came after the frames, check them against the board
+		// now that it's known
+		for i := range frames {
+			if limitErr = limits.checkFrame(&game.Game, &frames[i]); limitErr != nil {
+				return nil, limitErr
+			}
+		}
+	}
+	game.Frames = frames
+	Intern(game)
+	return game, nil
+}
+
+// decodeChunkedWithLimits is decodeWithLimits for the chunked layout. The
+// manifest comes first with the settings and a frame count for every chunk,
+// so those are checked before any frames are uncompressed, and each chunk's
+// frames are checked before the next chunk is read. MaxDecompressedSize
+// counts the manifest and every chunk.
+func decodeChunkedWithLimits(r *zip.Reader, limits Limits) (*ViewGame, error) {
+	files := zipFiles(r)
+	max := limits.MaxDecompressedSize
+	in := &sizeLimitReader{max: max}
+	read := func(f *zip.File) ([]byte, error) {
+		if max > 0 && uint64(in.read)+f.UncompressedSize64 > uint64(max) {
+			return nil, &LimitError{Limit: "MaxDecompressedSize", Max: max, Got: in.read + int64(f.UncompressedSize64)}
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: error opening zip archive file: %w", ErrCorruptArchive, err)
+		}
+		defer rc.Close()
+		in.r = rc
+		contents, err := ioutil.ReadAll(in)
+		if in.exceeded {
+			return nil, &LimitError{Limit: "MaxDecompressedSize", Max: max, Got: in.read}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: error reading %s: %w", ErrCorruptArchive, f.Name, err)
+		}
+		return contents, nil
+	}
+	contents, err := read(files[chunkManifestFile])
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := parseChunkManifest(contents, files)
+	if err != nil {
+		return nil, err
+	}
+	if limitErr := limits.checkBoard(&manifest.Game); limitErr != nil {
+		return nil, limitErr
+	}
+	// decodeChunk holds each chunk to its count, so more frames can't follow
+	total := 0
+	for _, entry := range manifest.Chunks {
+		total += entry.Frames
+	}
+	if limits.MaxFrames > 0 && total > limits.MaxFrames {
+		return nil, &LimitError{Limit: "MaxFrames", Max: int64(limits.MaxFrames), Got: int64(total)}
+	}
+	game := &ViewGame{Game: manifest.Game, LastTurn: manifest.LastTurn, Events: manifest.Events}
+	for i := range manifest.Chunks {
+		entry := &manifest.Chunks[i]
+		contents, err := read(files[entry.File])
+		if err != nil {
+			return nil, err
+		}
+		var frames []ViewFrame
+		err = decodeChunk(entry, contents, &frames)
+		if err != nil {
+			return nil, err
+		}
+		for j := range frames {
+			if limitErr := limits.checkFrame(&manifest.Game, &frames[j]); limitErr != nil {
+				return nil, limitErr
+			}
+		}
+		game.Frames = append(game.Frames, frames...)
+	}
+	if len(game.Frames) > 0 {
+		game.FirstFrame = game.Frames[0]
+	}
+	Intern(game)
+	return game, nil
+}
+
+func (limits Limits) checkBoard(settings *ViewGameSettings) *LimitError {
+	if limits.MaxWidth > 0 && settings.Width > limits.MaxWidth {
+		return &LimitError{Limit: "MaxWidth", Max: int64(limits.MaxWidth), Got: int64(settings.Width)}
+	}
+	if limits.MaxHeight > 0 && settings.Height > limits.MaxHeight {
+		return &LimitError{Limit: "MaxHeight", Max: int64(limits.MaxHeight), Got: int64(settings.Height)}
+	}
+	return nil
+}
+
+// checkFrame checks frame on its own, and against the board in settings
+// when its size is known
+func (limits Limits) checkFrame(settings *ViewGameSettings, frame *ViewFrame) *LimitError {
+	if limits.MaxSnakes > 0 && len(frame.Snakes) > limits.MaxSnakes {
+		return &LimitError{Limit: "MaxSnakes", Max: int64(limits.MaxSnakes), Got: int64(len(frame.Snakes))}
+	}
+	if limits.MaxBodyLength > 0 {
+		for i := range frame.Snakes {
+			if n := len(frame.Snakes[i].Body); n > limits.MaxBodyLength {
+				return &LimitError{Limit: "MaxBodyLength", Max: int64(limits.MaxBodyLength), Got: int64(n)}
+			}
+		}
+	}
+	cells := int64(settings.Width) * int64(settings.Height)
+	if limits.MaxPerCell > 0 && cells > 0 {
+		max := int64(limits.MaxPerCell * float64(cells))
+		for i := range frame.Snakes {
+			if n := int64(len(frame.Snakes[i].Body)); n > max {
+				return &LimitError{Limit: "MaxPerCell", Max: max, Got: n}
+			}
+		}
+		if n := int64(len(frame.Food)); n > max {
+			return &LimitError{Limit: "MaxPerCell", Max: max, Got: n}
+		}
+	}
+	return nil
+}
+
+// sizeLimitReader fails once more than max bytes are read, or never when
+// max is 0
+type sizeLimitReader struct {
+	r        io.Reader
+	max      int64
+	read     int64
+	exceeded bool
+}
+
+var errSizeLimit = errors.New("decompressed size limit exceeded")
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	if l.max <= 0 {
+		return l.r.Read(p)
+	}
+	if l.exceeded {
+		return 0, errSizeLimit
+	}
+	if remaining := l.max + 1 - l.read; int64(len(p)) > remaining {
+		// one extra byte tells a file of exactly max bytes from a bigger one
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		l.exceeded = true
+		return 0, errSizeLimit
+	}
+	return n, err
+}
@@ -4,9 +4,9 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"time"
 )
 
 // View Structs - these are returned from https://engine.battlesnake.com/games/{id}
@@ -16,6 +16,15 @@ type ViewGame struct {
 	Frames     []ViewFrame      `json:"Frames"`
 	FirstFrame ViewFrame        `json:"FirstFrame"`
 	LastTurn   int32            `json:"LastTurn"`
+	// Events recorded from the engine's event stream, in order. Games
+	// downloaded from the games endpoint don't have any.
+	Events []ViewEvent `json:"Events,omitempty"`
+	// Unknown holds json fields no struct field matched, when decoded with
+	// DecodeKeepUnknown. They're keyed by the dotted path of the object they
+	// were in, "" for the game itself and "Frames[3].Snakes[0]" for a snake,
+	// and written back in place by Encode. Edits that move frames or snakes
+	// around leave paths pointing at whatever is there now.
+	Unknown map[string]map[string]json.RawMessage `json:"-"`
 }
 
 type ViewGameSettings struct {
@@ -28,12 +37,19 @@ type ViewGameSettings struct {
 }
 
 type ViewRuleset struct {
-	FoodSpawnChance int32  `json:"foodSpawnChance,string"`
-	MinimumFood     int32  `json:"minimumFood,string"`
+	FoodSpawnChance int32  `json:"foodSpawnChance,omitempty,string"`
+	MinimumFood     int32  `json:"minimumFood,omitempty,string"`
 	Name            string `json:"name"`
 	Map             string `json:"map"`
 	MapAuthor       string `json:"map_author"`
-	DamagePerTurn   int32  `json:"damagePerTurn,string"`
+	DamagePerTurn   int32  `json:"damagePerTurn,omitempty,string"`
+	// Settings holds any other ruleset or map settings the engine sent,
+	// so newer game modes survive a round trip
+	Settings map[string]json.RawMessage `json:"-"`
+	// Unset lists the json keys of the integer fields above that the engine
+	// left out, or sent as null or an empty string. They read as 0 but
+	// aren't written back, see IsSet.
+	Unset []string `json:"-"`
 }
 
 type ViewTurn struct {
@@ -46,6 +62,9 @@ type ViewFrame struct {
 	Snakes  []ViewSnake `json:"Snakes"`
 	Food    []ViewCoord `json:"Food"`
 	Hazards []ViewCoord `json:"Hazards"`
+	// HazardDamage is the damage a hazard did this turn when the map changes
+	// it during the game. 0 means the ruleset's damagePerTurn.
+	HazardDamage int32 `json:"HazardDamage,omitempty"`
 }
 
 type ViewSnake struct {
@@ -66,9 +85,9 @@ type ViewSnake struct {
 }
 
 type ViewDeath struct {
-	Cause        string `json:"Cause"`
-	Turn         int32  `json:"Turn"`
-	EliminatedBy string `json:"EliminatedBy"`
+	Cause        DeathCause `json:"Cause"`
+	Turn         int32      `json:"Turn"`
+	EliminatedBy string     `json:"EliminatedBy"`
 }
 
 type ViewCoord struct {
@@ -77,8 +96,8 @@ type ViewCoord struct {
 }
 
 type ViewGameResponse struct {
-	Game ViewGameSettings `json:"Game"`
-	// ignore LastFrame
+	Game      ViewGameSettings `json:"Game"`
+	LastFrame *ViewFrame       `json:"LastFrame,omitempty"`
 }
 
 // Move Structs - these are sent to http://battlesnake-url/move
@@ -106,8 +125,13 @@ type MoveSettings struct {
 	FoodSpawnChance     int32      `json:"foodSpawnChance"`
 	MinimumFood         int32      `json:"minimumFood"`
 	HazardDamagePerTurn int32      `json:"hazardDamagePerTurn"`
+	HazardMap           string     `json:"hazardMap"`
+	HazardMapAuthor     string     `json:"hazardMapAuthor"`
 	Royale              MoveRoyale `json:"royale"`
 	Squad               MoveSquad  `json:"squad"`
+	// Extra holds settings without a typed field, passed through from
+	// ViewRuleset.Settings
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type MoveRoyale struct {
@@ -139,6 +163,14 @@ type MoveBattlesnake struct {
 	Latency string      `json:"latency"`
 	Shout   string      `json:"shout"`
 	Squad   string      `json:"squad"`
+
+	Customizations MoveCustomizations `json:"customizations"`
+}
+
+type MoveCustomizations struct {
+	Color string `json:"color"`
+	Head  string `json:"head"`
+	Tail  string `json:"tail"`
 }
 
 type MoveCoord struct {
@@ -155,117 +187,184 @@ type MoveBattlesnakeResponse struct {
 
 // Compress contents using zip archive (stored in buf)
 func Encode(game *ViewGame, buf *bytes.Buffer) error {
-	contents, err := json.Marshal(game)
+	contents, err := marshalGame(game)
 	if err != nil {
-		return fmt.Errorf("error marshaling ViewGame to json: %s", err)
+		return fmt.Errorf("error marshaling ViewGame to json: %w", err)
 	}
+	return writeArchive(contents, newMetadata(game).stamp(), buf)
+}
+
+// writeArchive stores contents as game.json in a zip archive, with m as
+// metadata.json. Entries have no modification time so the archive only
+// depends on contents and m.
+func writeArchive(contents []byte, m *Metadata, buf *bytes.Buffer) error {
 	w := zip.NewWriter(buf)
 	f, err := w.Create("game.json")
 	if err != nil {
-		return fmt.Errorf("error adding file to zip archive: %s", err)
+		return fmt.Errorf("error adding file to zip archive: %w", err)
+	}
+	_, err = f.Write(contents)
+	if err != nil {
+		return fmt.Errorf("error writing contents to zip archive: %w", err)
 	}
-	_, err = f.Write([]byte(contents))
+	err = writeMetadata(w, m)
 	if err != nil {
-		return fmt.Errorf("error writing contents to zip archive: %s", err)
+		return err
 	}
 	err = w.Close()
 	if err != nil {
-		return fmt.Errorf("error closing zip archive: %s", err)
+		return fmt.Errorf("error closing zip archive: %w", err)
 	}
 	return nil
 }
 
 // Uncompress data for a game
 func Decode(data []byte) (*ViewGame, error) {
+	start := time.Now()
+	game, err := decode(data)
+	reportDecode(DecodeFormatArchive, start, len(data), game, err)
+	return game, err
+}
+
+func decode(data []byte) (*ViewGame, error) {
 	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
 	if err != nil {
-		return nil, fmt.Errorf("error creating new zip reader: %s", err)
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
 	}
-	// fmt.Printf("zip archive contains %d files\n", len(r.File))
-	if len(r.File) != 1 {
-		return nil, fmt.Errorf("expected 1 file in zip archive, found %d", len(r.File))
+	if isChunkedZip(r) {
+		return decodeChunkedZip(r, ParallelOptions{})
 	}
-	rc, err := r.File[0].Open()
+	rc, err := openGameFile(r)
 	if err != nil {
-		return nil, fmt.Errorf("error opening zip archive file: %s", err)
+		return nil, err
 	}
 	defer rc.Close()
-	unzipped, err := ioutil.ReadAll(rc)
+	game, err := decodeGameJSON(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling compressed game: %w", ErrCorruptArchive, err)
+	}
+	Intern(game)
+	return game, nil
+}
+
+// readArchive returns the uncompressed game json from an archive
+func readArchive(data []byte) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	var buf bytes.Buffer
+	err = readArchiveInto(r, &buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readArchiveInto appends the uncompressed game json from an archive to buf
+func readArchiveInto(r *zip.Reader, buf *bytes.Buffer) error {
+	rc, err := openGameFile(r)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	_, err = buf.ReadFrom(rc)
+	if err != nil {
+		return fmt.Errorf("%w: error reading compressed game: %w", ErrCorruptArchive, err)
+	}
+	return nil
+}
+
+// openGameFile opens the game json of a single game archive
+func openGameFile(r *zip.Reader) (io.ReadCloser, error) {
+	f, err := gameFile(r)
 	if err != nil {
-		return nil, fmt.Errorf("error reading compressed game: %s", err)
+		return nil, err
 	}
-	var game ViewGame
-	err = json.Unmarshal(unzipped, &game)
+	rc, err := f.Open()
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling compressed game: %s", err)
+		return nil, fmt.Errorf("%w: error opening zip archive file: %w", ErrCorruptArchive, err)
 	}
-	return &game, nil
+	return rc, nil
 }
 
 // Translation functions
 
 func (game *ViewGame) ToMove(turn int32, snakeId string) (*MoveGameState, error) {
-	frame, err := getFrame(game, turn)
+	return game.toMove(turn, snakeId, nil)
+}
+
+// toMove translates a frame, taking slices from arena when it isn't nil
+func (game *ViewGame) toMove(turn int32, snakeId string, arena *MoveArena) (*MoveGameState, error) {
+	frame, err := game.FrameAt(turn)
 	if err != nil {
 		return nil, err
 	}
-	var you *MoveBattlesnake
-	var snakes []MoveBattlesnake = make([]MoveBattlesnake, 0, len(frame.Snakes))
-	for _, frameSnake := range frame.Snakes {
-		if frameSnake.Death.Cause != "" {
-			continue
+	var you *ViewSnake
+	for i := range frame.Snakes {
+		if !frame.Snakes[i].Death.Eliminated() && frame.Snakes[i].ID == snakeId {
+			you = &frame.Snakes[i]
 		}
-		if snakeId == frameSnake.ID {
-			you = &MoveBattlesnake{
-				ID:      frameSnake.ID,
-				Name:    frameSnake.Name,
-				Health:  frameSnake.Health,
-				Body:    convertCoords(frameSnake.Body),
-				Head:    convertCoord(frameSnake.Body[0]),
-				Length:  int32(len(frameSnake.Body)),
-				Latency: frameSnake.Latency,
-				Shout:   frameSnake.Shout,
-				Squad:   frameSnake.Squad,
-			}
-		}
-		snakes = append(snakes, MoveBattlesnake{
-			ID:      frameSnake.ID,
-			Name:    frameSnake.Name,
-			Health:  frameSnake.Health,
-			Body:    convertCoords(frameSnake.Body),
-			Head:    convertCoord(frameSnake.Body[0]),
-			Length:  int32(len(frameSnake.Body)),
-			Latency: frameSnake.Latency,
-			Shout:   frameSnake.Shout,
-			Squad:   frameSnake.Squad,
-		})
 	}
 	if you == nil {
-		return nil, errors.New("no snake ID found matching " + snakeId)
+		return nil, fmt.Errorf("%w: no snake ID found matching %s", ErrSnakeNotFound, snakeId)
 	}
+	return game.moveState(frame, you, arena), nil
+}
+
+// moveState is the move request for frame sent to you. The board only has
+// the snakes that are still alive, you doesn't have to be one of them.
+func (game *ViewGame) moveState(frame *ViewFrame, you *ViewSnake, arena *MoveArena) *MoveGameState {
+	var snakes []MoveBattlesnake = arena.snakes(len(frame.Snakes))
+	for i := range frame.Snakes {
+		if frame.Snakes[i].Death.Eliminated() {
+			continue
+		}
+		snakes = append(snakes, moveSnake(&frame.Snakes[i], arena))
+	}
+	settings := game.Game.Ruleset.moveSettings()
+	settings.HazardDamagePerTurn = game.hazardDamage(frame)
 	return &MoveGameState{
 		Game: MoveGame{
 			ID: game.Game.ID,
 			Ruleset: MoveRuleset{
-				Name: game.Game.Ruleset.Name,
-				Settings: MoveSettings{
-					FoodSpawnChance:     game.Game.Ruleset.FoodSpawnChance,
-					MinimumFood:         game.Game.Ruleset.MinimumFood,
-					HazardDamagePerTurn: game.Game.Ruleset.DamagePerTurn,
-				},
+				Name:     game.Game.Ruleset.Name,
+				Settings: settings,
 			},
 			Timeout: game.Game.Timeout,
 		},
-		Turn: turn,
+		Turn: frame.Turn,
 		Board: MoveBoard{
 			Width:   game.Game.Width,
 			Height:  game.Game.Height,
 			Snakes:  snakes,
-			Food:    convertCoords(frame.Food),
-			Hazards: convertCoords(frame.Hazards),
+			Food:    arena.convertCoords(frame.Food),
+			Hazards: arena.convertCoords(frame.Hazards),
 		},
-		You: *you,
-	}, nil
+		You: moveSnake(you, arena),
+	}
+}
+
+func moveSnake(s *ViewSnake, arena *MoveArena) MoveBattlesnake {
+	snake := MoveBattlesnake{
+		ID:      s.ID,
+		Name:    s.Name,
+		Health:  s.Health,
+		Body:    arena.convertCoords(s.Body),
+		Length:  int32(len(s.Body)),
+		Latency: s.Latency,
+		Shout:   s.Shout,
+		Squad:   s.Squad,
+		Customizations: MoveCustomizations{
+			Color: s.Color,
+			Head:  s.HeadType,
+			Tail:  s.TailType,
+		},
+	}
+	if len(s.Body) > 0 {
+		snake.Head = convertCoord(s.Body[0])
+	}
+	return snake
 }
 
 func convertCoord(c ViewCoord) MoveCoord {
@@ -280,9 +379,27 @@ func convertCoords(coords []ViewCoord) []MoveCoord {
 	return result
 }
 
-func getFrame(game *ViewGame, turn int32) (*ViewFrame, error) {
-	if len(game.Frames) < int(turn)+1 {
-		return nil, fmt.Errorf("no frame found for turn %d", turn)
+// ToMoveAll translates turn into a move request for every snake alive on
+// that turn, in frame order.
+func (game *ViewGame) ToMoveAll(turn int32) ([]*MoveGameState, error) {
+	return game.toMoveAll(turn, nil)
+}
+
+func (game *ViewGame) toMoveAll(turn int32, arena *MoveArena) ([]*MoveGameState, error) {
+	frame, err := game.FrameAt(turn)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*MoveGameState, 0, len(frame.Snakes))
+	for _, s := range frame.Snakes {
+		if s.Death.Eliminated() {
+			continue
+		}
+		state, err := game.toMove(turn, s.ID, arena)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
 	}
-	return &game.Frames[turn], nil
+	return states, nil
 }
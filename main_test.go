@@ -0,0 +1,155 @@
+package battlesnakegameformat_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+)
+
+// testGame plays a seeded game between random snakes, which has food,
+// eliminations and events. Royale games shrink every 5 turns, for hazards.
+func testGame(t *testing.T, name bsgf.RulesetName) *bsgf.ViewGame {
+	t.Helper()
+	ruleset := bsgf.ViewRuleset{Name: string(name)}
+	if name == bsgf.RulesetRoyale {
+		ruleset.Settings = map[string]json.RawMessage{"shrinkEveryNTurns": json.RawMessage("5")}
+	}
+	players := []harness.Player{
+		{Name: "Alpha", URL: "http://alpha.test", Snake: harness.NewRandomSnake(1)},
+		{Name: "Beta", URL: "http://beta.test", Snake: harness.NewRandomSnake(2)},
+		{Name: "Gamma", Snake: harness.NewRandomSnake(3)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: 3, Ruleset: ruleset})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(game.Frames) < 10 {
+		t.Fatalf("test game only has %d frames", len(game.Frames))
+	}
+	return game
+}
+
+func encode(t *testing.T, game *bsgf.ViewGame) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	err := bsgf.Encode(game, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeChunked(t *testing.T, game *bsgf.ViewGame, framesPerChunk int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	err := bsgf.EncodeChunked(game, &buf, framesPerChunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func assertSameGame(t *testing.T, want, got *bsgf.ViewGame) {
+	t.Helper()
+	diffs := bsgf.DiffGames(want, got)
+	for i, d := range diffs {
+		if i == 10 {
+			t.Errorf("and %d more differences", len(diffs)-i)
+			break
+		}
+		t.Error(d)
+	}
+}
+
+// decoders are every way of reading a whole game from an archive
+var decoders = []struct {
+	name   string
+	decode func(data []byte) (*bsgf.ViewGame, error)
+	// strict decoders report ruleset settings without a field as unknown
+	strict bool
+}{
+	{"Decode", bsgf.Decode, false},
+	{"DecodeStrict", bsgf.DecodeStrict, true},
+	{"DecodeKeepUnknown", bsgf.DecodeKeepUnknown, false},
+	{"DecodeReaderAt", func(data []byte) (*bsgf.ViewGame, error) {
+		return bsgf.DecodeReaderAt(bytes.NewReader(data), int64(len(data)))
+	}, false},
+	{"DecodeWithLimits", func(data []byte) (*bsgf.ViewGame, error) {
+		return bsgf.DecodeWithLimits(data, bsgf.DefaultLimits)
+	}, false},
+	{"DecodeInto", func(data []byte) (*bsgf.ViewGame, error) {
+		var game bsgf.ViewGame
+		err := bsgf.DecodeInto(data, &game)
+		return &game, err
+	}, false},
+	{"DecodeLazy", func(data []byte) (*bsgf.ViewGame, error) {
+		lazy, err := bsgf.DecodeLazy(data)
+		if err != nil {
+			return nil, err
+		}
+		return lazy.Load()
+	}, false},
+	{"StreamFrames", func(data []byte) (*bsgf.ViewGame, error) {
+		game, err := bsgf.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+		game.Frames = nil
+		for frame, err := range bsgf.StreamFrames(data) {
+			if err != nil {
+				return nil, err
+			}
+			game.Frames = append(game.Frames, *frame.Clone())
+		}
+		return game, nil
+	}, false},
+}
+
+func TestDecodersRoundTrip(t *testing.T) {
+	for _, name := range []bsgf.RulesetName{bsgf.RulesetStandard, bsgf.RulesetRoyale} {
+		game := testGame(t, name)
+		layouts := []struct {
+			name string
+			data []byte
+		}{
+			{"single", encode(t, game)},
+			{"chunked-1", encodeChunked(t, game, 1)},
+			{"chunked-7", encodeChunked(t, game, 7)},
+			{"chunked-all", encodeChunked(t, game, len(game.Frames))},
+		}
+		for _, layout := range layouts {
+			for _, d := range decoders {
+				t.Run(string(name)+"/"+layout.name+"/"+d.name, func(t *testing.T) {
+					got, err := d.decode(layout.data)
+					if d.strict && len(game.Game.Ruleset.Settings) > 0 {
+						var fe *bsgf.FieldError
+						if !errors.As(err, &fe) || len(fe.Unknown) != 1 || fe.Unknown[0] != "Game.Ruleset.shrinkEveryNTurns" {
+							t.Fatalf("expected the royale setting to be unknown, got %v", err)
+						}
+						return
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					assertSameGame(t, game, got)
+				})
+			}
+		}
+	}
+}
+
+func TestDecodeNotAnArchive(t *testing.T) {
+	for _, d := range decoders {
+		t.Run(d.name, func(t *testing.T) {
+			_, err := d.decode([]byte(`{"Game":{}}`))
+			if !errors.Is(err, bsgf.ErrUnsupportedFormat) {
+				t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Every archive written by this package has a small metadata.json next to
+// the game, so indexes can be rebuilt and provenance checked without
+// uncompressing any frames.
+
+const metadataFile = "metadata.json"
+
+// Version of the metadata layout
+const MetadataVersion = 1
+
+const modulePath = "github.com/jlafayette/battlesnake-game-format-go"
+
+// Metadata summarizes the game in an archive and how the archive was made
+type Metadata struct {
+	Version  int             `json:"version"`
+	GameID   string          `json:"gameId"`
+	Status   string          `json:"status"`
+	Ruleset  string          `json:"ruleset"`
+	Map      string          `json:"map"`
+	Width    int32           `json:"width"`
+	Height   int32           `json:"height"`
+	LastTurn int32           `json:"lastTurn"`
+	Winner   string          `json:"winner,omitempty"`
+	Snakes   []MetadataSnake `json:"snakes"`
+	// Encoder is the module and version that wrote the archive and Created
+	// is when. Both are left out by EncodeStable, which has to write the
+	// same bytes for the same game.
+	Encoder string     `json:"encoder,omitempty"`
+	Created *time.Time `json:"created,omitempty"`
+}
+
+// MetadataSnake is a snake and where it finished, best first
+type MetadataSnake struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Author       string     `json:"author,omitempty"`
+	Place        int        `json:"place"`
+	Eliminated   bool       `json:"eliminated"`
+	DeathTurn    int32      `json:"deathTurn,omitempty"`
+	Cause        DeathCause `json:"cause,omitempty"`
+	EliminatedBy string     `json:"eliminatedBy,omitempty"`
+}
+
+// gameMetadata summarizes game, with last as its final frame
+func gameMetadata(settings *ViewGameSettings, lastTurn int32, last *ViewFrame) *Metadata {
+	m := &Metadata{
+		Version:  MetadataVersion,
+		GameID:   settings.ID,
+		Status:   settings.Status,
+		Ruleset:  settings.Ruleset.Name,
+		Map:      settings.Ruleset.Map,
+		Width:    settings.Width,
+		Height:   settings.Height,
+		LastTurn: lastTurn,
+	}
+	if last != nil {
+		snakes := make(map[string]*ViewSnake, len(last.Snakes))
+		for i := range last.Snakes {
+			snakes[last.Snakes[i].ID] = &last.Snakes[i]
+		}
+		final := ViewGame{Frames: []ViewFrame{*last}}
+		for _, p := range final.Placements() {
+			snake := snakes[p.SnakeID]
+			m.Snakes = append(m.Snakes, MetadataSnake{
+				ID:           p.SnakeID,
+				Name:         p.Name,
+				Author:       snake.Author,
+				Place:        p.Place,
+				Eliminated:   p.Eliminated,
+				DeathTurn:    p.DeathTurn,
+				Cause:        p.Cause,
+				EliminatedBy: snake.Death.EliminatedBy,
+			})
+		}
+		m.Winner = final.Winner()
+	}
+	return m
+}
+
+func newMetadata(game *ViewGame) *Metadata {
+	var last *ViewFrame
+	if len(game.Frames) > 0 {
+		last = &game.Frames[len(game.Frames)-1]
+	}
+	return gameMetadata(&game.Game, game.LastTurn, last)
+}
+
+// stamp records the encoder and the time on m
+func (m *Metadata) stamp() *Metadata {
+	m.Encoder = encoderVersion()
+	now := time.Now().UTC().Truncate(time.Second)
+	m.Created = &now
+	return m
+}
+
+// encoderVersion is this module's path and version, as far as the binary's
+// build info knows it
+func encoderVersion() string {
+	version := "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == modulePath && info.Main.Version != "" {
+			version = info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath && dep.Version != "" {
+				version = dep.Version
+			}
+		}
+	}
+	return modulePath + "@" + version
+}
+
+func writeMetadata(w *zip.Writer, m *Metadata) error {
+	contents, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling metadata to json: %w", err)
+	}
+	f, err := w.Create(metadataFile)
+	if err != nil {
+		return fmt.Errorf("error adding file to zip archive: %w", err)
+	}
+	_, err = f.Write(contents)
+	if err != nil {
+		return fmt.Errorf("error writing contents to zip archive: %w", err)
+	}
+	return nil
+}
+
+// DecodeMetadata reads only the metadata of an archive. Archives written
+// before metadata was added get it worked out from the settings and the
+// final frame, with no Encoder or Created.
+func DecodeMetadata(data []byte) (*Metadata, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	m, err := readMetadata(r)
+	if err != nil || m != nil {
+		return m, err
+	}
+	info, err := DecodeInfo(data)
+	if err != nil {
+		return nil, err
+	}
+	var last *ViewFrame
+	if info.FrameCount > 0 {
+		last = &info.LastFrame
+	}
+	return gameMetadata(&info.Game, info.LastTurn, last), nil
+}
+
+// readMetadata returns the metadata in r, or nil if it has none
+func readMetadata(r *zip.Reader) (*Metadata, error) {
+	for _, f := range r.File {
+		if f.Name != metadataFile {
+			continue
+		}
+		contents, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var m Metadata
+		err = json.Unmarshal(contents, &m)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error unmarshalling metadata: %w", ErrCorruptArchive, err)
+		}
+		if m.Version > MetadataVersion {
+			return nil, fmt.Errorf("%w: metadata version %d is newer than %d", ErrUnsupportedFormat, m.Version, MetadataVersion)
+		}
+		return &m, nil
+	}
+	return nil, nil
+}
+
+// gameFile is the game json of a single game archive, which is the only
+// file besides the metadata. Chunked archives have no such file.
+func gameFile(r *zip.Reader) (*zip.File, error) {
+	if isChunkedZip(r) {
+		return nil, fmt.Errorf("%w: chunked archive has no single game file, decode it with Decode", ErrUnsupportedFormat)
+	}
+	var game *zip.File
+	n := 0
+	for _, f := range r.File {
+		if f.Name == metadataFile {
+			continue
+		}
+		game = f
+		n++
+	}
+	if n != 1 {
+		return nil, fmt.Errorf("%w: expected 1 game file in zip archive, found %d", ErrCorruptArchive, n)
+	}
+	return game, nil
+}
@@ -0,0 +1,148 @@
+// Package metrics counts what the decoders, engine client and servers in
+// this module do through bsgf.Hooks and serves the totals in the
+// Prometheus text format:
+//
+//	m := metrics.New()
+//	bsgf.SetHooks(m.Hooks())
+//	http.Handle("/metrics", m)
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Upper bounds of the fetch latency histogram buckets, in seconds
+var fetchBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics holds the counters. It's safe for concurrent use.
+type Metrics struct {
+	mu           sync.Mutex
+	gamesServed  int64
+	frames       map[string]int64
+	decodes      map[string]int64
+	decodeErrors map[string]int64
+	fetches      int64
+	fetchErrors  int64
+	fetchSum     float64
+	fetchCounts  []int64
+}
+
+func New() *Metrics {
+	return &Metrics{
+		frames:       make(map[string]int64),
+		decodes:      make(map[string]int64),
+		decodeErrors: make(map[string]int64),
+		fetchCounts:  make([]int64, len(fetchBuckets)),
+	}
+}
+
+// Hooks returns hooks that feed m, to pass to bsgf.SetHooks
+func (m *Metrics) Hooks() bsgf.Hooks {
+	return bsgf.Hooks{
+		OnDecode:         m.decoded,
+		OnFetch:          m.fetched,
+		OnFrameProcessed: m.frameProcessed,
+	}
+}
+
+// GameServed counts a game sent to a viewer, for use as
+// server.Server.OnGameServed
+func (m *Metrics) GameServed(id string) {
+	m.mu.Lock()
+	m.gamesServed++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) decoded(s bsgf.DecodeStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.decodes[s.Format]++
+	if s.Err != nil {
+		m.decodeErrors[s.Format]++
+	}
+}
+
+func (m *Metrics) fetched(s bsgf.FetchStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetches++
+	if s.Err != nil {
+		m.fetchErrors++
+	}
+	seconds := s.Duration.Seconds()
+	m.fetchSum += seconds
+	for i, le := range fetchBuckets {
+		if seconds <= le {
+			m.fetchCounts[i]++
+		}
+	}
+}
+
+func (m *Metrics) frameProcessed(s bsgf.FrameStats) {
+	m.mu.Lock()
+	m.frames[s.Stage]++
+	m.mu.Unlock()
+}
+
+// ServeHTTP writes every metric in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+// WriteTo writes every metric in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var b strings.Builder
+	metric(&b, "bsgf_games_served_total", "counter", "Games sent to viewers by the replay server.")
+	fmt.Fprintf(&b, "bsgf_games_served_total %d\n", m.gamesServed)
+	metric(&b, "bsgf_frames_processed_total", "counter", "Frames streamed, recorded or analyzed, by stage.")
+	labeled(&b, "bsgf_frames_processed_total", "stage", m.frames)
+	metric(&b, "bsgf_decodes_total", "counter", "Games decoded, by format.")
+	labeled(&b, "bsgf_decodes_total", "format", m.decodes)
+	metric(&b, "bsgf_decode_errors_total", "counter", "Games that failed to decode, by format.")
+	labeled(&b, "bsgf_decode_errors_total", "format", m.decodeErrors)
+	metric(&b, "bsgf_fetch_errors_total", "counter", "Failed requests to the engine, including retries.")
+	fmt.Fprintf(&b, "bsgf_fetch_errors_total %d\n", m.fetchErrors)
+	metric(&b, "bsgf_fetch_duration_seconds", "histogram", "Latency of requests to the engine.")
+	for i, le := range fetchBuckets {
+		fmt.Fprintf(&b, "bsgf_fetch_duration_seconds_bucket{le=\"%g\"} %d\n", le, m.fetchCounts[i])
+	}
+	fmt.Fprintf(&b, "bsgf_fetch_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.fetches)
+	fmt.Fprintf(&b, "bsgf_fetch_duration_seconds_sum %g\n", m.fetchSum)
+	fmt.Fprintf(&b, "bsgf_fetch_duration_seconds_count %d\n", m.fetches)
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func metric(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func labeled(b *strings.Builder, name, label string, values map[string]int64) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s{%s=%q} %d\n", name, label, k, values[k])
+	}
+}
+
+// Serve listens on addr and serves m at /metrics until the listener fails
+func (m *Metrics) Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return srv.ListenAndServe()
+}
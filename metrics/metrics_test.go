@@ -0,0 +1,48 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+	hooks := m.Hooks()
+	hooks.OnDecode(bsgf.DecodeStats{Format: "bsgf"})
+	hooks.OnDecode(bsgf.DecodeStats{Format: "bsgf", Err: errors.New("bad")})
+	hooks.OnDecode(bsgf.DecodeStats{Format: "jsonl"})
+	hooks.OnFetch(bsgf.FetchStats{Duration: 30 * time.Millisecond})
+	hooks.OnFetch(bsgf.FetchStats{Duration: 20 * time.Second, Err: errors.New("timeout")})
+	hooks.OnFrameProcessed(bsgf.FrameStats{Stage: bsgf.FrameStageRecord})
+	m.GameServed("game")
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+	for _, line := range []string{
+		`bsgf_games_served_total 1`,
+		`bsgf_frames_processed_total{stage="record"} 1`,
+		`bsgf_decodes_total{format="bsgf"} 2`,
+		`bsgf_decodes_total{format="jsonl"} 1`,
+		`bsgf_decode_errors_total{format="bsgf"} 1`,
+		`bsgf_fetch_errors_total 1`,
+		`bsgf_fetch_duration_seconds_bucket{le="0.025"} 0`,
+		`bsgf_fetch_duration_seconds_bucket{le="0.05"} 1`,
+		`bsgf_fetch_duration_seconds_bucket{le="10"} 1`,
+		`bsgf_fetch_duration_seconds_bucket{le="+Inf"} 2`,
+		`bsgf_fetch_duration_seconds_count 2`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %s in\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `bsgf_decode_errors_total{format="jsonl"}`) {
+		t.Error("formats without errors shouldn't be listed")
+	}
+}
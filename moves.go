@@ -0,0 +1,60 @@
+package battlesnakegameformat
+
+import (
+	"fmt"
+)
+
+// MoveAt derives the move snakeId made on turn from the head positions in
+// the recorded frames for turn and turn+1.
+func (game *ViewGame) MoveAt(turn int32, snakeId string) (Direction, error) {
+	frame, err := game.FrameAt(turn)
+	if err != nil {
+		return "", err
+	}
+	next, err := game.FrameAt(turn + 1)
+	if err != nil {
+		return "", err
+	}
+	before, ok := findSnake(frame, snakeId)
+	if !ok {
+		return "", fmt.Errorf("%w: no snake %s on turn %d", ErrSnakeNotFound, snakeId, turn)
+	}
+	if before.Death.Eliminated() {
+		return "", fmt.Errorf("snake %s is not alive on turn %d", snakeId, turn)
+	}
+	after, ok := findSnake(next, snakeId)
+	if !ok {
+		return "", fmt.Errorf("%w: no snake %s on turn %d", ErrSnakeNotFound, snakeId, turn+1)
+	}
+	if len(after.Body) == 0 || len(before.Body) == 0 {
+		return "", fmt.Errorf("no body found for snake %s on turn %d", snakeId, turn+1)
+	}
+	return moveBetween(before.Body[0], after.Body[0], game.Game.Width, game.Game.Height)
+}
+
+func moveBetween(from, to ViewCoord, width, height int32) (Direction, error) {
+	dx := to.X - from.X
+	dy := to.Y - from.Y
+	// wrapped boards let the head jump to the opposite edge
+	if width > 2 && (dx == width-1 || dx == -(width-1)) {
+		dx = -dx / (width - 1)
+	}
+	if height > 2 && (dy == height-1 || dy == -(height-1)) {
+		dy = -dy / (height - 1)
+	}
+	for _, d := range Directions {
+		if ddx, ddy := d.Delta(); dx == ddx && dy == ddy {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("head moved from (%d,%d) to (%d,%d), not a single step", from.X, from.Y, to.X, to.Y)
+}
+
+func findSnake(frame *ViewFrame, snakeId string) (*ViewSnake, bool) {
+	for i := range frame.Snakes {
+		if frame.Snakes[i].ID == snakeId {
+			return &frame.Snakes[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,62 @@
+package battlesnakegameformat
+
+import (
+	"sort"
+	"strings"
+)
+
+// Normalize rewrites game in place into a canonical form, so two downloads
+// of the same game encode to the same bytes: food and hazards are sorted by
+// position, snakes are ordered by ID, stray whitespace is trimmed from
+// snake metadata, colors are lowercased and FirstFrame and LastTurn are
+// derived from Frames.
+func Normalize(game *ViewGame) {
+	game.Game.ID = strings.TrimSpace(game.Game.ID)
+	game.Game.Status = strings.TrimSpace(game.Game.Status)
+	game.Game.Ruleset.Name = strings.TrimSpace(game.Game.Ruleset.Name)
+	game.Game.Ruleset.Map = strings.TrimSpace(game.Game.Ruleset.Map)
+	game.Game.Ruleset.MapAuthor = strings.TrimSpace(game.Game.Ruleset.MapAuthor)
+	for i := range game.Frames {
+		normalizeFrame(&game.Frames[i])
+	}
+	if len(game.Frames) > 0 {
+		game.FirstFrame = *game.Frames[0].Clone()
+		game.LastTurn = game.Frames[len(game.Frames)-1].Turn
+	} else {
+		normalizeFrame(&game.FirstFrame)
+	}
+}
+
+func normalizeFrame(frame *ViewFrame) {
+	sortCoords(frame.Food)
+	sortCoords(frame.Hazards)
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		s.ID = strings.TrimSpace(s.ID)
+		s.Name = strings.TrimSpace(s.Name)
+		s.URL = strings.TrimSpace(s.URL)
+		s.Color = strings.ToLower(strings.TrimSpace(s.Color))
+		s.HeadType = strings.TrimSpace(s.HeadType)
+		s.TailType = strings.TrimSpace(s.TailType)
+		s.Latency = strings.TrimSpace(s.Latency)
+		s.Shout = strings.TrimRight(s.Shout, " \t\r\n")
+		s.Squad = strings.TrimSpace(s.Squad)
+		s.APIVersion = strings.TrimSpace(s.APIVersion)
+		s.Author = strings.TrimSpace(s.Author)
+		s.Death.EliminatedBy = strings.TrimSpace(s.Death.EliminatedBy)
+	}
+	sort.SliceStable(frame.Snakes, func(i, j int) bool {
+		return frame.Snakes[i].ID < frame.Snakes[j].ID
+	})
+}
+
+// sortCoords orders by x then y. Duplicates are kept, stacked hazards
+// matter.
+func sortCoords(coords []ViewCoord) {
+	sort.Slice(coords, func(i, j int) bool {
+		if coords[i].X != coords[j].X {
+			return coords[i].X < coords[j].X
+		}
+		return coords[i].Y < coords[j].Y
+	})
+}
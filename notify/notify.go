@@ -0,0 +1,133 @@
+// Package notify tells other services when long running work finishes: a
+// recording is stored, a bulk download completes or validation finds
+// problems. Notifiers are either an HTTP webhook or a callback.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// EventType says what finished
+type EventType string
+
+const (
+	// EventRecorded is sent when a live recording is stored
+	EventRecorded EventType = "recorded"
+	// EventDownloaded is sent when a bulk download or pipeline run is done,
+	// whether or not every game succeeded
+	EventDownloaded EventType = "downloaded"
+	// EventInvalid is sent when validation finds problems
+	EventInvalid EventType = "invalid"
+)
+
+// Event is the json body posted to webhooks
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+	// GameIDs are the games the event is about. For EventDownloaded these
+	// are the games that were stored.
+	GameIDs []string `json:"gameIds,omitempty"`
+	// Failed counts games that couldn't be downloaded or read
+	Failed int `json:"failed,omitempty"`
+	// Findings counts validation problems
+	Findings int    `json:"findings,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Notifier delivers events
+type Notifier interface {
+	Notify(ctx context.Context, e Event) error
+}
+
+// Func is a Notifier that calls a function
+type Func func(ctx context.Context, e Event) error
+
+func (f Func) Notify(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// Header holding the hex HMAC-SHA256 of the body when Webhook.Secret is set
+const SignatureHeader = "X-Bsgf-Signature"
+
+// Webhook posts each event as json to URL. Anything but a 2xx response is
+// an error.
+type Webhook struct {
+	URL string
+	// Secret, when set, signs each body in SignatureHeader so the receiver
+	// can check where it came from
+	Secret     string
+	HTTPClient *http.Client
+}
+
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (w *Webhook) Notify(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("error marshalling event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+	client := w.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting %s event: %w", e.Type, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s for %s event", resp.Status, e.Type)
+	}
+	return nil
+}
+
+// Multi sends every event to each notifier in turn, returning the first
+// error after trying them all
+type Multi []Notifier
+
+func (m Multi) Notify(ctx context.Context, e Event) error {
+	var first error
+	for _, n := range m {
+		err := n.Notify(ctx, e)
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Send stamps e with the current time, when it has none, and delivers it
+// through n. A nil n does nothing, so callers don't need to check whether
+// notifications are configured.
+func Send(ctx context.Context, n Notifier, e Event) error {
+	if n == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	return n.Notify(ctx, e)
+}
@@ -0,0 +1,73 @@
+package notify_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jlafayette/battlesnake-game-format-go/notify"
+)
+
+func TestWebhook(t *testing.T) {
+	var got notify.Event
+	var signature string
+	var valid bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		signature = r.Header.Get(notify.SignatureHeader)
+		valid = signature == hex.EncodeToString(mac.Sum(nil))
+		json.Unmarshal(body, &got)
+		if got.Failed > 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	hook := notify.NewWebhook(srv.URL)
+	hook.Secret = "secret"
+	err := notify.Send(context.Background(), hook, notify.Event{Type: notify.EventDownloaded, GameIDs: []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Errorf("signature %q doesn't match the body", signature)
+	}
+	if got.Type != notify.EventDownloaded || len(got.GameIDs) != 2 || got.Time.IsZero() {
+		t.Errorf("unexpected event %+v", got)
+	}
+	err = notify.Send(context.Background(), hook, notify.Event{Type: notify.EventDownloaded, Failed: 1})
+	if err == nil {
+		t.Error("expected an error for a 500 response")
+	}
+}
+
+func TestMulti(t *testing.T) {
+	first := errors.New("first")
+	var calls []string
+	record := func(name string, err error) notify.Notifier {
+		return notify.Func(func(ctx context.Context, e notify.Event) error {
+			calls = append(calls, name)
+			return err
+		})
+	}
+	m := notify.Multi{record("a", nil), record("b", first), record("c", errors.New("second"))}
+	err := notify.Send(context.Background(), m, notify.Event{Type: notify.EventInvalid})
+	if err != first {
+		t.Errorf("expected the first error, got %v", err)
+	}
+	if len(calls) != 3 {
+		t.Errorf("expected every notifier to be called, got %v", calls)
+	}
+	if err := notify.Send(context.Background(), nil, notify.Event{}); err != nil {
+		t.Errorf("expected a nil notifier to do nothing, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package battlesnakegameformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// OpenAPISpec names one of the APIs there is an OpenAPI definition for
+type OpenAPISpec string
+
+const (
+	// OpenAPIMove is the API a snake serves, which the engine calls
+	OpenAPIMove OpenAPISpec = "move"
+	// OpenAPIView is the engine's games API that archives are downloaded from
+	OpenAPIView OpenAPISpec = "view"
+)
+
+// OpenAPISpecs lists every spec OpenAPI accepts
+var OpenAPISpecs = []OpenAPISpec{OpenAPIMove, OpenAPIView}
+
+// Where refs point inside an OpenAPI document
+const componentsRefPrefix = "#/components/schemas/"
+
+type openAPIDoc struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*schemaNode `json:"schemas"`
+}
+
+type openAPIOperation struct {
+	Summary     string                     `json:"summary"`
+	OperationID string                     `json:"operationId"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIBody               `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *schemaNode `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                    `json:"required"`
+	Content  map[string]openAPIMedia `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]openAPIMedia `json:"content,omitempty"`
+}
+
+type openAPIMedia struct {
+	Schema *schemaNode `json:"schema"`
+}
+
+// OpenAPI returns the OpenAPI 3.1 definition for spec. Like Schema, the
+// payload schemas are generated from the Go structs, and copies are kept
+// in the schemas directory for generating clients in other languages.
+func OpenAPI(spec OpenAPISpec) ([]byte, error) {
+	b := schemaBuilder{defs: make(map[string]*schemaNode), refPrefix: componentsRefPrefix}
+	doc := openAPIDoc{OpenAPI: "3.1.0", Paths: make(map[string]map[string]*openAPIOperation)}
+	switch spec {
+	case OpenAPIMove:
+		doc.Info = openAPIInfo{
+			Title:       "Battlesnake move API",
+			Description: "Requests the engine sends to a snake, as produced by ViewGame.ToMove.",
+			Version:     "1",
+		}
+		state := b.node(reflect.TypeOf(MoveGameState{}))
+		for _, name := range []string{"start", "end"} {
+			doc.Paths["/"+name] = map[string]*openAPIOperation{"post": {
+				Summary:     "Game " + name,
+				OperationID: name,
+				RequestBody: jsonBody(state),
+				Responses:   map[string]openAPIResponse{"200": {Description: "Ignored by the engine"}},
+			}}
+		}
+		doc.Paths["/move"] = map[string]*openAPIOperation{"post": {
+			Summary:     "Pick a move for this turn",
+			OperationID: "move",
+			RequestBody: jsonBody(state),
+			Responses: map[string]openAPIResponse{"200": {
+				Description: "The move",
+				Content:     jsonContent(b.node(reflect.TypeOf(MoveBattlesnakeResponse{}))),
+			}},
+		}}
+	case OpenAPIView:
+		doc.Info = openAPIInfo{
+			Title:       "Battlesnake engine games API",
+			Description: "Endpoints games are downloaded from and that bsgf serve replays archives over.",
+			Version:     "1",
+		}
+		id := openAPIParameter{Name: "id", In: "path", Required: true, Schema: withTypes(&schemaNode{}, "string")}
+		doc.Paths["/games/{id}"] = map[string]*openAPIOperation{"get": {
+			Summary:     "Game settings and last frame",
+			OperationID: "getGame",
+			Parameters:  []openAPIParameter{id},
+			Responses: map[string]openAPIResponse{
+				"200": {Description: "The game", Content: jsonContent(b.node(reflect.TypeOf(ViewGameResponse{})))},
+				"404": {Description: "No such game"},
+			},
+		}}
+		doc.Paths["/games/{id}/frames"] = map[string]*openAPIOperation{"get": {
+			Summary:     "A page of frames",
+			OperationID: "getFrames",
+			Parameters: []openAPIParameter{
+				id,
+				{Name: "offset", In: "query", Schema: withTypes(&schemaNode{}, "integer")},
+				{Name: "limit", In: "query", Schema: withTypes(&schemaNode{}, "integer")},
+			},
+			Responses: map[string]openAPIResponse{
+				"200": {Description: "Frames from offset", Content: jsonContent(b.node(reflect.TypeOf(ViewTurn{})))},
+				"404": {Description: "No such game"},
+			},
+		}}
+	default:
+		return nil, fmt.Errorf("unknown openapi spec %q", spec)
+	}
+	doc.Components.Schemas = b.defs
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling openapi spec to json: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func jsonContent(n *schemaNode) map[string]openAPIMedia {
+	return map[string]openAPIMedia{"application/json": {Schema: n}}
+}
+
+func jsonBody(n *schemaNode) *openAPIBody {
+	return &openAPIBody{Required: true, Content: jsonContent(n)}
+}
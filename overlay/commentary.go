@@ -0,0 +1,218 @@
+package overlay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+)
+
+// CommentType says what happened
+type CommentType string
+
+const (
+	CommentElimination CommentType = "elimination"
+	CommentFood        CommentType = "food"
+	// CommentHazard is a snake moving its head into a hazard from outside
+	CommentHazard CommentType = "hazard"
+	// CommentLengthLead and CommentTerritoryLead are a new snake alone in
+	// the lead
+	CommentLengthLead    CommentType = "length-lead"
+	CommentTerritoryLead CommentType = "territory-lead"
+)
+
+// Comment is one event worth calling out, for casters and chat bots
+type Comment struct {
+	Type CommentType `json:"type"`
+	Turn int32       `json:"turn"`
+	// Time is when the turn was played, when known
+	Time    *time.Time `json:"time,omitempty"`
+	SnakeID string     `json:"snakeId"`
+	Name    string     `json:"name"`
+	// Other is the snake responsible for an elimination, or the previous
+	// leader for lead changes
+	Other     string `json:"other,omitempty"`
+	OtherName string `json:"otherName,omitempty"`
+	// Value is the new length, or territory in cells, for lead changes
+	Value   int    `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// Commentator derives comments one frame at a time by comparing each frame
+// with the one before. Frames should be given in order.
+type Commentator struct {
+	// Clock stamps the comments of each turn, by default with the time
+	// Frame is called, for live games. Archives can use NoClock or
+	// TurnClock.
+	Clock func(turn int32) *time.Time
+
+	settings        bsgf.ViewGameSettings
+	prev            *bsgf.ViewFrame
+	names           map[string]string
+	lengthLeader    string
+	territoryLeader string
+}
+
+func NewCommentator(settings *bsgf.ViewGameSettings) *Commentator {
+	return &Commentator{
+		Clock: func(int32) *time.Time {
+			now := time.Now().UTC()
+			return &now
+		},
+		settings: *settings,
+		names:    make(map[string]string),
+	}
+}
+
+// NoClock leaves comments without a time
+func NoClock(int32) *time.Time {
+	return nil
+}
+
+// TurnClock stamps turns at fixed intervals from start, to replay an
+// archive as if it were live
+func TurnClock(start time.Time, perTurn time.Duration) func(turn int32) *time.Time {
+	return func(turn int32) *time.Time {
+		t := start.Add(time.Duration(turn) * perTurn)
+		return &t
+	}
+}
+
+// Frame returns the comments for frame
+func (c *Commentator) Frame(frame *bsgf.ViewFrame) []Comment {
+	var comments []Comment
+	at := c.Clock(frame.Turn)
+	add := func(t CommentType, s *bsgf.ViewSnake, message string) *Comment {
+		comments = append(comments, Comment{Type: t, Turn: frame.Turn, Time: at, SnakeID: s.ID, Name: s.Name, Message: message})
+		return &comments[len(comments)-1]
+	}
+	for _, s := range frame.Snakes {
+		c.names[s.ID] = s.Name
+	}
+	prev := c.prev
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		if !s.Alive() {
+			if s.Death.Turn != frame.Turn {
+				continue
+			}
+			m := fmt.Sprintf("%s is out (%s)", s.Name, s.Death.Cause)
+			by := s.Death.EliminatedBy
+			if by != "" && by != s.ID {
+				m = fmt.Sprintf("%s is out, %s by %s", s.Name, s.Death.Cause, c.names[by])
+			}
+			e := add(CommentElimination, s, m)
+			if by != "" && by != s.ID {
+				e.Other, e.OtherName = by, c.names[by]
+			}
+			continue
+		}
+		if prev == nil || len(s.Body) == 0 {
+			continue
+		}
+		head := s.Body[0]
+		if containsCoord(prev.Food, head) {
+			add(CommentFood, s, fmt.Sprintf("%s eats, now length %d", s.Name, len(s.Body)))
+		}
+		if containsCoord(frame.Hazards, head) && !c.wasInHazard(s.ID) {
+			add(CommentHazard, s, fmt.Sprintf("%s heads into the hazard with %d health", s.Name, s.Health))
+		}
+	}
+
+	lengths := make([]int, len(frame.Snakes))
+	for i, s := range frame.Snakes {
+		if s.Alive() {
+			lengths[i] = len(s.Body)
+		}
+	}
+	territory := analysis.Territory(c.settings.Width, c.settings.Height, frame)
+	leads := []struct {
+		t       CommentType
+		values  []int
+		leader  *string
+		message string
+	}{
+		{CommentLengthLead, lengths, &c.lengthLeader, "%s takes the lead at length %d"},
+		{CommentTerritoryLead, territory, &c.territoryLeader, "%s controls the most space, %d cells"},
+	}
+	for _, lead := range leads {
+		i, ok := soleLeader(lead.values)
+		if !ok || frame.Snakes[i].ID == *lead.leader {
+			continue
+		}
+		s := &frame.Snakes[i]
+		// the first frame only sets who leads
+		if prev != nil {
+			e := add(lead.t, s, fmt.Sprintf(lead.message, s.Name, lead.values[i]))
+			e.Other, e.OtherName, e.Value = *lead.leader, c.names[*lead.leader], lead.values[i]
+		}
+		*lead.leader = s.ID
+	}
+	c.prev = frame.Clone()
+	return comments
+}
+
+// wasInHazard is true if the snake's head was in a hazard on the previous
+// frame
+func (c *Commentator) wasInHazard(snakeId string) bool {
+	for _, s := range c.prev.Snakes {
+		if s.ID == snakeId && len(s.Body) > 0 {
+			return containsCoord(c.prev.Hazards, s.Body[0])
+		}
+	}
+	return false
+}
+
+// soleLeader is the index of the only highest positive value
+func soleLeader(values []int) (int, bool) {
+	best, count := -1, 0
+	for i, v := range values {
+		switch {
+		case v <= 0:
+		case best < 0 || v > values[best]:
+			best, count = i, 1
+		case v == values[best]:
+			count++
+		}
+	}
+	return best, count == 1
+}
+
+func containsCoord(coords []bsgf.ViewCoord, c bsgf.ViewCoord) bool {
+	for _, o := range coords {
+		if o == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Commentary derives the comments for every frame of game. clock can be
+// nil for comments without a time.
+func Commentary(game *bsgf.ViewGame, clock func(turn int32) *time.Time) []Comment {
+	c := NewCommentator(&game.Game)
+	c.Clock = NoClock
+	if clock != nil {
+		c.Clock = clock
+	}
+	var comments []Comment
+	for i := range game.Frames {
+		comments = append(comments, c.Frame(&game.Frames[i])...)
+	}
+	return comments
+}
+
+// WriteComments writes comments as json lines
+func WriteComments(w io.Writer, comments ...Comment) error {
+	enc := json.NewEncoder(w)
+	for i := range comments {
+		err := enc.Encode(&comments[i])
+		if err != nil {
+			return fmt.Errorf("error writing comment: %w", err)
+		}
+	}
+	return nil
+}
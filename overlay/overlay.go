@@ -0,0 +1,154 @@
+// Package overlay turns frames into presentation-ready data for broadcast
+// overlays: health bars, lengths, territory, a kill feed and latency
+// warnings, one json object per turn so overlay software can follow a game
+// as it's cast, live or from an archive.
+package overlay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+)
+
+// Health snakes start with, for health bars
+const MaxHealth = 100
+
+// Turn is everything an overlay shows for one turn
+type Turn struct {
+	GameID string  `json:"gameId"`
+	Turn   int32   `json:"turn"`
+	Alive  int     `json:"alive"`
+	Snakes []Snake `json:"snakes"`
+	// Kills are the eliminations on this turn
+	Kills []Kill `json:"kills"`
+	// KillFeed is the most recent eliminations, newest first
+	KillFeed []Kill `json:"killFeed"`
+}
+
+// Snake is one snake's row on the overlay, in the order of the frame
+type Snake struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Alive bool   `json:"alive"`
+	// Health and HealthPct are 0 once eliminated
+	Health    int32   `json:"health"`
+	HealthPct float64 `json:"healthPct"`
+	Length    int     `json:"length"`
+	// TerritoryPct is the share of the board the snake reaches first
+	TerritoryPct float64 `json:"territoryPct"`
+	LatencyMs    float64 `json:"latencyMs"`
+	// LatencyWarning is set when the snake responded slower than
+	// Options.LatencyWarning
+	LatencyWarning bool   `json:"latencyWarning,omitempty"`
+	Shout          string `json:"shout,omitempty"`
+}
+
+// Kill is one elimination
+type Kill struct {
+	Turn    int32           `json:"turn"`
+	SnakeID string          `json:"snakeId"`
+	Name    string          `json:"name"`
+	Cause   bsgf.DeathCause `json:"cause"`
+	// By and ByName are the snake responsible, for collisions
+	By     string `json:"by,omitempty"`
+	ByName string `json:"byName,omitempty"`
+}
+
+type Options struct {
+	// LatencyWarning is the latency in milliseconds to warn at. 0 warns at
+	// 80% of the game's timeout, or never when the game has none.
+	LatencyWarning float64
+	// FeedSize is how many eliminations KillFeed holds. 0 means 5.
+	FeedSize int
+}
+
+// Builder makes overlay turns one frame at a time, keeping the kill feed
+// across frames. Frames should be given in order.
+type Builder struct {
+	settings bsgf.ViewGameSettings
+	opts     Options
+	feed     []Kill
+	names    map[string]string
+}
+
+func NewBuilder(settings *bsgf.ViewGameSettings, opts Options) *Builder {
+	if opts.LatencyWarning <= 0 {
+		opts.LatencyWarning = 0.8 * float64(settings.Timeout)
+	}
+	if opts.FeedSize <= 0 {
+		opts.FeedSize = 5
+	}
+	return &Builder{settings: *settings, opts: opts, names: make(map[string]string)}
+}
+
+// Frame builds the overlay for frame
+func (b *Builder) Frame(frame *bsgf.ViewFrame) *Turn {
+	t := &Turn{GameID: b.settings.ID, Turn: frame.Turn, Snakes: make([]Snake, 0, len(frame.Snakes)), Kills: []Kill{}}
+	territory := analysis.Territory(b.settings.Width, b.settings.Height, frame)
+	cells := float64(b.settings.Width * b.settings.Height)
+	for _, s := range frame.Snakes {
+		b.names[s.ID] = s.Name
+	}
+	for i := range frame.Snakes {
+		s := &frame.Snakes[i]
+		row := Snake{ID: s.ID, Name: s.Name, Color: s.Color, Alive: s.Alive(), Length: len(s.Body), Shout: s.Shout}
+		if row.Alive {
+			t.Alive++
+			row.Health = s.Health
+			row.HealthPct = 100 * float64(s.Health) / MaxHealth
+			if cells > 0 {
+				row.TerritoryPct = 100 * float64(territory[i]) / cells
+			}
+			// the first frame has no moves yet, so no latency
+			if frame.Turn > 0 {
+				if ms, err := strconv.ParseFloat(s.Latency, 64); err == nil {
+					row.LatencyMs = ms
+					row.LatencyWarning = b.opts.LatencyWarning > 0 && ms >= b.opts.LatencyWarning
+				}
+			}
+		} else if s.Death.Turn == frame.Turn {
+			k := Kill{Turn: frame.Turn, SnakeID: s.ID, Name: s.Name, Cause: s.Death.Cause}
+			if s.Death.EliminatedBy != "" && s.Death.EliminatedBy != s.ID {
+				k.By = s.Death.EliminatedBy
+				k.ByName = b.names[k.By]
+			}
+			t.Kills = append(t.Kills, k)
+		}
+		t.Snakes = append(t.Snakes, row)
+	}
+	for _, k := range t.Kills {
+		b.feed = append([]Kill{k}, b.feed...)
+	}
+	if len(b.feed) > b.opts.FeedSize {
+		b.feed = b.feed[:b.opts.FeedSize]
+	}
+	t.KillFeed = append([]Kill{}, b.feed...)
+	return t
+}
+
+// Game builds the overlay for every frame of game
+func Game(game *bsgf.ViewGame, opts Options) []*Turn {
+	b := NewBuilder(&game.Game, opts)
+	turns := make([]*Turn, 0, len(game.Frames))
+	for i := range game.Frames {
+		turns = append(turns, b.Frame(&game.Frames[i]))
+	}
+	return turns
+}
+
+// Write writes turns as json lines
+func Write(w io.Writer, turns ...*Turn) error {
+	enc := json.NewEncoder(w)
+	for _, t := range turns {
+		err := enc.Encode(t)
+		if err != nil {
+			return fmt.Errorf("error writing overlay turn: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package overlay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+	"github.com/jlafayette/battlesnake-game-format-go/overlay"
+)
+
+func play(t *testing.T) *bsgf.ViewGame {
+	t.Helper()
+	var players []harness.Player
+	for i, name := range []string{"Alpha", "Beta", "Gamma", "Delta"} {
+		players = append(players, harness.Player{Name: name, Snake: harness.NewRandomSnake(int64(i + 1))})
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestGame(t *testing.T) {
+	game := play(t)
+	turns := overlay.Game(game, overlay.Options{FeedSize: 2, LatencyWarning: 1e9})
+	if len(turns) != len(game.Frames) {
+		t.Fatalf("expected a turn per frame, got %d", len(turns))
+	}
+	kills := 0
+	for i, turn := range turns {
+		frame := &game.Frames[i]
+		alive := 0
+		territory := 0.0
+		for j, s := range turn.Snakes {
+			if s.Alive {
+				alive++
+			} else if s.Health != 0 || s.HealthPct != 0 {
+				t.Errorf("turn %d: eliminated snake %s has health %d", turn.Turn, s.Name, s.Health)
+			}
+			if s.Alive && (s.Health != frame.Snakes[j].Health || s.Length != len(frame.Snakes[j].Body)) {
+				t.Errorf("turn %d: snake %s doesn't match the frame", turn.Turn, s.Name)
+			}
+			if s.LatencyWarning {
+				t.Errorf("turn %d: unexpected latency warning for %s", turn.Turn, s.Name)
+			}
+			territory += s.TerritoryPct
+		}
+		if turn.Alive != alive || territory > 100.01 {
+			t.Errorf("turn %d: %d alive with %.2f%% of the board", turn.Turn, turn.Alive, territory)
+		}
+		kills += len(turn.Kills)
+		if len(turn.KillFeed) > 2 {
+			t.Errorf("turn %d: kill feed has %d kills", turn.Turn, len(turn.KillFeed))
+		}
+		if len(turn.Kills) > 0 && turn.KillFeed[0] != turn.Kills[len(turn.Kills)-1] {
+			t.Errorf("turn %d: newest kill isn't first in the feed", turn.Turn)
+		}
+	}
+	if kills != 3 {
+		t.Errorf("expected 3 kills in a 4 snake game, got %d", kills)
+	}
+}
+
+func TestCommentary(t *testing.T) {
+	game := play(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	comments := overlay.Commentary(game, overlay.TurnClock(start, time.Second))
+	counts := make(map[overlay.CommentType]int)
+	for _, c := range comments {
+		counts[c.Type]++
+		if c.Time == nil || !c.Time.Equal(start.Add(time.Duration(c.Turn)*time.Second)) {
+			t.Errorf("turn %d comment has time %v", c.Turn, c.Time)
+		}
+		if c.Message == "" {
+			t.Errorf("turn %d %s comment has no message", c.Turn, c.Type)
+		}
+	}
+	eaten := 0
+	for _, st := range analysis.Game(game) {
+		eaten += st.FoodEaten
+	}
+	if counts[overlay.CommentFood] != eaten || counts[overlay.CommentElimination] != 3 {
+		t.Errorf("expected %d food and 3 elimination comments, got %v", eaten, counts)
+	}
+	for _, c := range overlay.Commentary(game, overlay.NoClock) {
+		if c.Time != nil {
+			t.Fatal("NoClock comment has a time")
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ParallelOptions controls how games are decoded across goroutines
+type ParallelOptions struct {
+	// Workers is the number of games decoded at once, defaulting to the
+	// number of CPUs
+	Workers int
+	// Ordered delivers games in the order they are stored. Otherwise each
+	// game is delivered as soon as it's decoded.
+	Ordered bool
+}
+
+func (o ParallelOptions) workers(n int) int {
+	w := o.Workers
+	if w < 1 {
+		w = runtime.NumCPU()
+	}
+	if w > n {
+		w = n
+	}
+	return w
+}
+
+// DecodeParallel calls decode for 0..n-1 across worker goroutines and passes
+// each game to fn along with its index. fn is only called from the calling
+// goroutine. At most twice as many games as workers are held in memory
+// waiting for fn. The first error from decode or fn stops the run and is
+// returned.
+func DecodeParallel(n int, decode func(i int) (*ViewGame, error), opts ParallelOptions, fn func(i int, game *ViewGame) error) error {
+	if n <= 0 {
+		return nil
+	}
+	type result struct {
+		i    int
+		game *ViewGame
+		err  error
+	}
+	workers := opts.workers(n)
+	jobs := make(chan int)
+	results := make(chan result, workers)
+	tokens := make(chan struct{}, 2*workers)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(jobs)
+		for i := 0; i < n; i++ {
+			select {
+			case tokens <- struct{}{}:
+			case <-done:
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				game, err := decode(i)
+				select {
+				case results <- result{i, game, err}:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var err error
+	pending := make(map[int]result)
+	next := 0
+	deliver := func(r result) error {
+		<-tokens
+		if r.err != nil {
+			return r.err
+		}
+		return fn(r.i, r.game)
+	}
+	for r := range results {
+		if !opts.Ordered {
+			err = deliver(r)
+		} else {
+			pending[r.i] = r
+			for ok := true; ok && err == nil; {
+				r, ok = pending[next]
+				if ok {
+					delete(pending, next)
+					next++
+					err = deliver(r)
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	close(done)
+	for range results {
+		// let the workers exit
+	}
+	return err
+}
+
+// DecodeMultiParallel uncompresses every game in a container across worker
+// goroutines, verifying each against the manifest, and passes them to fn.
+func DecodeMultiParallel(data []byte, opts ParallelOptions, fn func(game *ViewGame) error) error {
+	r, manifest, err := openContainer(data)
+	if err != nil {
+		return err
+	}
+	files := make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+	decode := func(i int) (*ViewGame, error) {
+		return decodeEntry(files, &manifest.Games[i])
+	}
+	return DecodeParallel(len(manifest.Games), decode, opts, func(i int, game *ViewGame) error {
+		return fn(game)
+	})
+}
+
+func decodeEntry(files map[string]*zip.File, entry *ManifestEntry) (*ViewGame, error) {
+	start := time.Now()
+	game, err := decodeEntryContents(files, entry)
+	reportDecode(DecodeFormatContainer, start, int(entry.Size), game, err)
+	return game, err
+}
+
+func decodeEntryContents(files map[string]*zip.File, entry *ManifestEntry) (*ViewGame, error) {
+	f, ok := files[entry.File]
+	if !ok {
+		return nil, fmt.Errorf("%w: manifest lists missing file %s", ErrCorruptArchive, entry.File)
+	}
+	contents, err := readZipFile(f)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyEntry(entry, contents)
+	if err != nil {
+		return nil, err
+	}
+	var game ViewGame
+	err = unmarshalJSON(contents, &game)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling game %s: %w", ErrCorruptArchive, entry.ID, err)
+	}
+	Intern(&game)
+	return &game, nil
+}
@@ -0,0 +1,66 @@
+package battlesnakegameformat
+
+import (
+	"sort"
+)
+
+// Placement is where a snake finished in a game
+type Placement struct {
+	SnakeID string `json:"snakeId"`
+	Name    string `json:"name"`
+	// Place starts at 1. Snakes eliminated on the same turn, or still
+	// alive when the game ended, share a place.
+	Place      int        `json:"place"`
+	Eliminated bool       `json:"eliminated"`
+	DeathTurn  int32      `json:"deathTurn,omitempty"`
+	Cause      DeathCause `json:"cause,omitempty"`
+}
+
+// Placements ranks every snake in the final frame: survivors first, then
+// by how long they lasted.
+func (game *ViewGame) Placements() []Placement {
+	last, err := game.FinalFrame()
+	if err != nil {
+		return nil
+	}
+	placements := make([]Placement, 0, len(last.Snakes))
+	for i := range last.Snakes {
+		s := &last.Snakes[i]
+		p := Placement{SnakeID: s.ID, Name: s.Name, Eliminated: !s.Alive()}
+		if p.Eliminated {
+			p.DeathTurn = s.Death.Turn
+			p.Cause = s.Death.Cause
+		}
+		placements = append(placements, p)
+	}
+	sort.SliceStable(placements, func(i, j int) bool {
+		return outlasted(&placements[i], &placements[j])
+	})
+	for i := range placements {
+		if i > 0 && !outlasted(&placements[i-1], &placements[i]) {
+			placements[i].Place = placements[i-1].Place
+		} else {
+			placements[i].Place = i + 1
+		}
+	}
+	return placements
+}
+
+// outlasted is true if a finished ahead of b
+func outlasted(a, b *Placement) bool {
+	if a.Eliminated != b.Eliminated {
+		return !a.Eliminated
+	}
+	return a.Eliminated && a.DeathTurn > b.DeathTurn
+}
+
+// Winner returns the ID of the snake that finished alone in first place, or
+// "" for draws, unfinished games where several snakes are still alive, and
+// solo games, which have no opponent to beat.
+func (game *ViewGame) Winner() string {
+	placements := game.Placements()
+	if len(placements) < 2 || placements[1].Place == 1 {
+		return ""
+	}
+	return placements[0].SnakeID
+}
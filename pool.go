@@ -0,0 +1,66 @@
+package battlesnakegameformat
+
+import (
+	"sync"
+)
+
+var (
+	framePool = sync.Pool{New: func() interface{} { return new(ViewFrame) }}
+	statePool = sync.Pool{New: func() interface{} { return new(MoveGameState) }}
+)
+
+// GetFrame returns an empty frame from a shared pool. Its slices may have
+// capacity left over from earlier use, which json decoding reuses.
+func GetFrame() *ViewFrame {
+	return framePool.Get().(*ViewFrame)
+}
+
+// PutFrame resets frame and returns it to the pool. Nothing may use frame or
+// its slices afterwards.
+func PutFrame(frame *ViewFrame) {
+	frame.Reset()
+	framePool.Put(frame)
+}
+
+// GetMoveState returns an empty move request from a shared pool
+func GetMoveState() *MoveGameState {
+	return statePool.Get().(*MoveGameState)
+}
+
+// PutMoveState resets state and returns it to the pool. Nothing may use
+// state or its slices afterwards.
+func PutMoveState(state *MoveGameState) {
+	state.Reset()
+	statePool.Put(state)
+}
+
+// Reset zeroes frame while keeping the capacity of its slices. The json
+// decoder decodes into slice elements as they are, so elements past the
+// length are zeroed too.
+func (frame *ViewFrame) Reset() {
+	snakes := frame.Snakes[:cap(frame.Snakes)]
+	for i := range snakes {
+		snakes[i] = ViewSnake{Body: snakes[i].Body[:0]}
+	}
+	*frame = ViewFrame{
+		Snakes:  snakes[:0],
+		Food:    frame.Food[:0],
+		Hazards: frame.Hazards[:0],
+	}
+}
+
+// Reset zeroes state while keeping the capacity of its slices.
+func (state *MoveGameState) Reset() {
+	snakes := state.Board.Snakes[:cap(state.Board.Snakes)]
+	for i := range snakes {
+		snakes[i] = MoveBattlesnake{Body: snakes[i].Body[:0]}
+	}
+	*state = MoveGameState{
+		Board: MoveBoard{
+			Snakes:  snakes[:0],
+			Food:    state.Board.Food[:0],
+			Hazards: state.Board.Hazards[:0],
+		},
+		You: MoveBattlesnake{Body: state.You.Body[:0]},
+	}
+}
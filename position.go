@@ -0,0 +1,201 @@
+package battlesnakegameformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position files are single positions written by hand or saved from a board
+// editor, in json or yaml. Coordinates are [x, y] pairs or {x, y} objects,
+// with y=0 at the bottom like the rest of the format:
+//
+//	id: corner-trap
+//	width: 7
+//	height: 7
+//	ruleset: standard
+//	snakes:
+//	  - id: me
+//	    health: 90
+//	    body: [[0, 2], [0, 1], [0, 0]]
+//	  - id: them
+//	    body: [[2, 2], [2, 3], [2, 4], [2, 5]]
+//	food: [[3, 3]]
+//	hazards: []
+//
+// Only width, height and snakes are required.
+
+type position struct {
+	ID           string                     `json:"id"`
+	Ruleset      string                     `json:"ruleset"`
+	Map          string                     `json:"map"`
+	Width        int32                      `json:"width"`
+	Height       int32                      `json:"height"`
+	Turn         int32                      `json:"turn"`
+	Timeout      int32                      `json:"timeout"`
+	HazardDamage *int32                     `json:"hazardDamage"`
+	Snakes       []positionSnake            `json:"snakes"`
+	Food         []positionCoord            `json:"food"`
+	Hazards      []positionCoord            `json:"hazards"`
+	Settings     map[string]json.RawMessage `json:"settings"`
+}
+
+type positionSnake struct {
+	ID     string          `json:"id"`
+	Name   string          `json:"name"`
+	Health *int32          `json:"health"`
+	Body   []positionCoord `json:"body"`
+	Color  string          `json:"color"`
+	Squad  string          `json:"squad"`
+}
+
+type positionCoord ViewCoord
+
+// UnmarshalJSON accepts [x, y] pairs as well as {"x": x, "y": y} objects
+func (c *positionCoord) UnmarshalJSON(data []byte) error {
+	var pair []int32
+	if json.Unmarshal(data, &pair) == nil {
+		if len(pair) != 2 {
+			return fmt.Errorf("coordinate %s doesn't have 2 values", data)
+		}
+		*c = positionCoord{X: pair[0], Y: pair[1]}
+		return nil
+	}
+	var point ViewCoord
+	err := json.Unmarshal(data, &point)
+	if err != nil {
+		return fmt.Errorf("invalid coordinate %s", data)
+	}
+	*c = positionCoord(point)
+	return nil
+}
+
+// IsPosition is true if data looks like a position file
+func IsPosition(data []byte) bool {
+	raw, err := positionJSON(data)
+	if err != nil {
+		return false
+	}
+	var probe map[string]json.RawMessage
+	if json.Unmarshal(raw, &probe) != nil {
+		return false
+	}
+	for _, key := range []string{"width", "height", "snakes"} {
+		if _, ok := probe[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// DecodePosition converts a position file to a game with a single frame, so
+// puzzles can be replayed and played like recorded games. Missing health
+// defaults to 100, missing snake IDs to snake-1, snake-2 and so on, and the
+// ruleset to standard. The result is checked with Validate.
+func DecodePosition(data []byte) (*ViewGame, error) {
+	raw, err := positionJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var p position
+	err = json.Unmarshal(raw, &p)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling position: %w", ErrCorruptArchive, err)
+	}
+	if p.ID == "" {
+		p.ID = "position"
+	}
+	if p.Ruleset == "" {
+		p.Ruleset = string(RulesetStandard)
+	}
+	if p.Timeout == 0 {
+		p.Timeout = 500
+	}
+	damage := DefaultHazardDamagePerTurn
+	if p.HazardDamage != nil {
+		damage = *p.HazardDamage
+	}
+	game := ViewGame{Game: ViewGameSettings{
+		ID: p.ID,
+		Ruleset: ViewRuleset{
+			Name:            p.Ruleset,
+			Map:             p.Map,
+			FoodSpawnChance: DefaultFoodSpawnChance,
+			MinimumFood:     DefaultMinimumFood,
+			DamagePerTurn:   damage,
+			Settings:        p.Settings,
+		},
+		Timeout: p.Timeout,
+		Status:  "running",
+		Width:   p.Width,
+		Height:  p.Height,
+	}}
+	frame := ViewFrame{
+		Turn:         p.Turn,
+		Snakes:       make([]ViewSnake, len(p.Snakes)),
+		Food:         positionCoords(p.Food),
+		Hazards:      positionCoords(p.Hazards),
+		HazardDamage: damage,
+	}
+	for i, s := range p.Snakes {
+		snake := ViewSnake{
+			ID:     s.ID,
+			Name:   s.Name,
+			Body:   positionCoords(s.Body),
+			Health: 100,
+			Color:  s.Color,
+			Squad:  s.Squad,
+		}
+		if snake.ID == "" {
+			snake.ID = fmt.Sprintf("snake-%d", i+1)
+		}
+		if snake.Name == "" {
+			snake.Name = snake.ID
+		}
+		if s.Health != nil {
+			snake.Health = *s.Health
+		}
+		frame.Snakes[i] = snake
+	}
+	game.Frames = []ViewFrame{frame}
+	game.FirstFrame = frame
+	game.LastTurn = frame.Turn
+	err = game.Validate()
+	if err != nil {
+		return nil, err
+	}
+	Intern(&game)
+	return &game, nil
+}
+
+// positionJSON returns data as json, converting it from yaml when it isn't
+// a json object already
+func positionJSON(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return trimmed, nil
+	}
+	var doc interface{}
+	err := yaml.Unmarshal(trimmed, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling position yaml: %w", ErrCorruptArchive, err)
+	}
+	if _, ok := doc.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("%w: position yaml is not a mapping", ErrCorruptArchive)
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error converting position yaml: %w", ErrCorruptArchive, err)
+	}
+	return raw, nil
+}
+
+func positionCoords(coords []positionCoord) []ViewCoord {
+	result := make([]ViewCoord, len(coords))
+	for i, c := range coords {
+		result[i] = ViewCoord(c)
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DecodeReaderAt is Decode for an archive of size bytes read through r, such
+// as an *os.File or a range reader over object storage, so the archive never
+// has to be in memory as a whole. The zip directory is read from the end and
+// the game is uncompressed and parsed a frame at a time as it's read. When r
+// fails the error is an *IOError rather than ErrCorruptArchive.
+func DecodeReaderAt(r io.ReaderAt, size int64) (*ViewGame, error) {
+	start := time.Now()
+	game, err := decodeReaderAt(r, size)
+	reportDecode(DecodeFormatArchive, start, int(size), game, err)
+	return game, err
+}
+
+func decodeReaderAt(r io.ReaderAt, size int64) (*ViewGame, error) {
+	in := &failureReaderAt{r: r, ioFailure: ioFailure{op: "read"}}
+	zr, err := zip.NewReader(in, size)
+	if err != nil {
+		return nil, in.blame(fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err))
+	}
+	if isChunkedZip(zr) {
+		game, err := decodeChunkedZip(zr, ParallelOptions{})
+		if err != nil {
+			return nil, in.blame(err)
+		}
+		return game, nil
+	}
+	rc, err := openGameFile(zr)
+	if err != nil {
+		return nil, in.blame(err)
+	}
+	defer rc.Close()
+	game, err := decodeGameJSON(rc)
+	if err != nil {
+		return nil, in.blame(fmt.Errorf("%w: error unmarshalling compressed game: %w", ErrCorruptArchive, err))
+	}
+	Intern(game)
+	return game, nil
+}
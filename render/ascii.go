@@ -0,0 +1,76 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+const (
+	asciiEmpty  = '.'
+	asciiFood   = '*'
+	asciiHazard = '~'
+)
+
+// ASCII draws a frame as text, one row per line with y=0 at the bottom. Each
+// snake is drawn with a letter, uppercase for the head. Eliminated snakes are
+// left out.
+func ASCII(width, height int32, frame *bsgf.ViewFrame) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	grid := make([][]byte, height)
+	for y := range grid {
+		grid[y] = []byte(strings.Repeat(string(asciiEmpty), int(width)))
+	}
+	set := func(c bsgf.ViewCoord, b byte) {
+		if c.X >= 0 && c.X < width && c.Y >= 0 && c.Y < height {
+			grid[c.Y][c.X] = b
+		}
+	}
+	for _, c := range frame.Hazards {
+		set(c, asciiHazard)
+	}
+	for _, c := range frame.Food {
+		set(c, asciiFood)
+	}
+	for i, s := range frame.Snakes {
+		if s.Death.Eliminated() {
+			continue
+		}
+		letter := snakeLetter(i)
+		for j := len(s.Body) - 1; j >= 0; j-- {
+			if j == 0 {
+				set(s.Body[j], letter-'a'+'A')
+			} else {
+				set(s.Body[j], letter)
+			}
+		}
+	}
+	var b strings.Builder
+	for y := height - 1; y >= 0; y-- {
+		b.Write(grid[y])
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Legend lists the letter used for each snake in frame with its health and
+// length, or how it was eliminated
+func Legend(frame *bsgf.ViewFrame) string {
+	var b strings.Builder
+	for i, s := range frame.Snakes {
+		letter := snakeLetter(i) - 'a' + 'A'
+		if s.Death.Eliminated() {
+			fmt.Fprintf(&b, "%c %s: eliminated turn %d (%s)\n", letter, s.Name, s.Death.Turn, s.Death.Cause)
+			continue
+		}
+		fmt.Fprintf(&b, "%c %s: health %d, length %d\n", letter, s.Name, s.Health, len(s.Body))
+	}
+	return b.String()
+}
+
+func snakeLetter(i int) byte {
+	return byte('a' + i%26)
+}
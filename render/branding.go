@@ -0,0 +1,184 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Branding dresses renders up for broadcast. The banner goes in a strip
+// above the board, the lower third in one below it and the watermark over
+// the board's bottom right corner, so every image of a GIF has the same
+// size.
+type Branding struct {
+	// Watermark is drawn at its own size, usually a png logo
+	Watermark image.Image
+	// Opacity of the watermark, 0 means fully opaque
+	Opacity float64
+	// Banner is text across the top
+	Banner string
+	// Title is the event title, the first line of the lower third
+	Title string
+	// LowerThird lists each snake's name next to its color. Eliminated
+	// snakes get an empty swatch.
+	LowerThird bool
+}
+
+// brandItem is one thing to draw in a branding strip
+type brandItem struct {
+	rect image.Rectangle
+	fill color.RGBA
+	// text is drawn in the theme's text color from the top left of rect,
+	// scale pixels to a font pixel
+	text  string
+	scale int
+}
+
+// fitScale shrinks scale until s fits in width, down to 1
+func fitScale(s string, width, scale int) int {
+	for scale > 1 && textWidth(s, scale) > width {
+		scale--
+	}
+	return scale
+}
+
+// brandLayout places the banner and lower third around a board of the given
+// size in pixels. top and bottom are the heights of the strips.
+func brandLayout(boardWidth, boardHeight int, frame *bsgf.ViewFrame, opts Options) (top, bottom int, items []brandItem) {
+	b := opts.Branding
+	theme := opts.theme()
+	scale := opts.textScale()
+	pad := 2 * scale
+	line := glyphHeight*scale + 2*pad
+	strip := func(y, height int) {
+		items = append(items, brandItem{rect: image.Rect(0, y, boardWidth, y+height), fill: theme.Grid})
+	}
+
+	if b.Banner != "" {
+		top = line
+		strip(0, top)
+		fit := fitScale(b.Banner, boardWidth-2*pad, scale)
+		x := (boardWidth - textWidth(b.Banner, fit)) / 2
+		if x < pad {
+			x = pad
+		}
+		y := pad + (glyphHeight*(scale-fit))/2
+		items = append(items, brandItem{rect: image.Rect(x, y, boardWidth, line-pad), text: b.Banner, scale: fit})
+	}
+
+	var rows []brandItem
+	y := 0
+	if b.Title != "" {
+		fit := fitScale(b.Title, boardWidth-2*pad, scale)
+		rows = append(rows, brandItem{rect: image.Rect(pad, y+pad+(glyphHeight*(scale-fit))/2, boardWidth, y+line-pad), text: b.Title, scale: fit})
+		y += line
+	}
+	if b.LowerThird && frame != nil {
+		// names wrap onto more rows when they don't fit across the board
+		x := pad
+		swatch := glyphHeight * scale
+		gap := 2 * glyphAdvance * scale
+		for i, s := range frame.Snakes {
+			w := swatch + pad + textWidth(s.Name, scale)
+			if x > pad && x+w > boardWidth-pad {
+				x = pad
+				y += line
+			}
+			col := snakeColor(s.Color, i, theme)
+			r := image.Rect(x, y+pad, x+swatch, y+pad+swatch)
+			rows = append(rows, brandItem{rect: r, fill: col})
+			if s.Death.Eliminated() {
+				rows = append(rows, brandItem{rect: r.Inset(scale), fill: theme.Grid})
+			}
+			rows = append(rows, brandItem{rect: image.Rect(x+swatch+pad, y+pad, boardWidth, y+line-pad), text: s.Name, scale: scale})
+			x += w + gap
+		}
+		y += line
+	}
+	if y > 0 {
+		bottom = y
+		offset := top + boardHeight
+		strip(offset, bottom)
+		for _, it := range rows {
+			it.rect = it.rect.Add(image.Pt(0, offset))
+			items = append(items, it)
+		}
+	}
+	return top, bottom, items
+}
+
+// brand returns board with opts.Branding added, or board itself when there's
+// no branding
+func brand(board *image.RGBA, frame *bsgf.ViewFrame, opts Options) *image.RGBA {
+	b := opts.Branding
+	if b == nil {
+		return board
+	}
+	theme := opts.theme()
+	size := board.Bounds().Size()
+	top, bottom, items := brandLayout(size.X, size.Y, frame, opts)
+	img := board
+	if top > 0 || bottom > 0 {
+		img = image.NewRGBA(image.Rect(0, 0, size.X, top+size.Y+bottom))
+		draw.Draw(img, board.Bounds().Add(image.Pt(0, top)), board, image.Point{}, draw.Src)
+	}
+	for _, it := range items {
+		if it.text != "" {
+			drawText(img, it.rect.Min.X, it.rect.Min.Y, it.scale, it.text, theme.text())
+		} else {
+			draw.Draw(img, it.rect, image.NewUniform(it.fill), image.Point{}, draw.Src)
+		}
+	}
+	if b.Watermark != nil {
+		wm := b.Watermark.Bounds()
+		margin := opts.cellSize() / 2
+		at := image.Rect(size.X-margin-wm.Dx(), top+size.Y-margin-wm.Dy(), size.X-margin, top+size.Y-margin)
+		var mask image.Image
+		if b.Opacity > 0 && b.Opacity < 1 {
+			mask = image.NewUniform(color.Alpha{uint8(b.Opacity * 0xff)})
+		}
+		draw.DrawMask(img, at, b.Watermark, wm.Min, mask, image.Point{}, draw.Over)
+	}
+	return img
+}
+
+// svgBranding writes the branding strips of an svg, the board having been
+// moved down by top
+func svgBranding(out io.Writer, boardWidth, boardHeight, top int, items []brandItem, opts Options) error {
+	b := opts.Branding
+	theme := opts.theme()
+	for _, it := range items {
+		r := it.rect
+		if it.text == "" {
+			fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", r.Min.X, r.Min.Y, r.Dx(), r.Dy(), svgColor(it.fill))
+			continue
+		}
+		// monospace glyphs are about 0.6em wide, the same advance as the
+		// bitmap font at 10 pixels a scale
+		fmt.Fprintf(out, `<text x="%d" y="%d" font-family="monospace" font-size="%d" fill="%s">%s</text>`+"\n",
+			r.Min.X, r.Min.Y+glyphHeight*it.scale, 10*it.scale, svgColor(theme.text()), html.EscapeString(it.text))
+	}
+	if b.Watermark != nil {
+		var buf bytes.Buffer
+		err := png.Encode(&buf, b.Watermark)
+		if err != nil {
+			return fmt.Errorf("error encoding watermark: %w", err)
+		}
+		wm := b.Watermark.Bounds()
+		margin := opts.cellSize() / 2
+		fmt.Fprintf(out, `<image x="%d" y="%d" width="%d" height="%d"`, boardWidth-margin-wm.Dx(), top+boardHeight-margin-wm.Dy(), wm.Dx(), wm.Dy())
+		if b.Opacity > 0 && b.Opacity < 1 {
+			fmt.Fprintf(out, ` opacity="%.2f"`, b.Opacity)
+		}
+		fmt.Fprintf(out, ` href="data:image/png;base64,%s"/>`+"\n", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	}
+	return nil
+}
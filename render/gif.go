@@ -0,0 +1,53 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// GIF animates frames from..to (inclusive, by frame index) with delay between
+// each frame. With opts.Substeps above 1 the delay is split between
+// interpolated images.
+func GIF(game *bsgf.ViewGame, from, to int, delay time.Duration, opts Options) (*gif.GIF, error) {
+	if from < 0 || to >= len(game.Frames) || from > to {
+		return nil, fmt.Errorf("invalid frame range %d-%d for game with %d frames", from, to, len(game.Frames))
+	}
+	substeps := opts.Substeps
+	if substeps < 1 {
+		substeps = 1
+	}
+	anim := &gif.GIF{}
+	// gif delays are in hundredths of a second
+	centiseconds := int(delay / (10 * time.Millisecond))
+	step := centiseconds / substeps
+	if step < 1 {
+		step = 1
+	}
+	width, height := game.Game.Width, game.Game.Height
+	for i := from; i <= to; i++ {
+		if i == to {
+			anim.Image = append(anim.Image, paletted(Frame(width, height, &game.Frames[i], opts)))
+			anim.Delay = append(anim.Delay, centiseconds)
+			break
+		}
+		for s := 0; s < substeps; s++ {
+			t := float64(s) / float64(substeps)
+			img := InterpolatedFrame(width, height, &game.Frames[i], &game.Frames[i+1], t, opts)
+			anim.Image = append(anim.Image, paletted(img))
+			anim.Delay = append(anim.Delay, step)
+		}
+	}
+	return anim, nil
+}
+
+func paletted(img *image.RGBA) *image.Paletted {
+	p := image.NewPaletted(img.Bounds(), palette.Plan9)
+	draw.Draw(p, img.Bounds(), img, image.Point{}, draw.Src)
+	return p
+}
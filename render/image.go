@@ -0,0 +1,234 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+)
+
+const DefaultCellSize = 20
+
+type Options struct {
+	// CellSize is the width and height of a board cell in pixels
+	CellSize int
+	// Theme defaults to ThemeDark
+	Theme    *Theme
+	Overlays []Overlay
+	// Substeps is how many images GIF draws per turn, interpolating snake
+	// movement between turns. 0 and 1 draw one image per turn.
+	Substeps int
+	// Branding adds a watermark, banner and lower third
+	Branding *Branding
+}
+
+type Theme struct {
+	Name       string
+	Background color.RGBA
+	Grid       color.RGBA
+	Food       color.RGBA
+	Hazard     color.RGBA
+	// Text is for captions, drawn over Grid
+	Text color.RGBA
+	// Snakes are colored from this palette when their own color can't be parsed
+	Palette []color.RGBA
+}
+
+var ThemeDark = &Theme{
+	Name:       "dark",
+	Background: color.RGBA{0x22, 0x22, 0x22, 0xff},
+	Grid:       color.RGBA{0x33, 0x33, 0x33, 0xff},
+	Food:       color.RGBA{0xff, 0x5c, 0x75, 0xff},
+	Hazard:     color.RGBA{0x00, 0x00, 0x00, 0x80},
+	Text:       color.RGBA{0xee, 0xee, 0xee, 0xff},
+	Palette: []color.RGBA{
+		{0x3e, 0x99, 0xef, 0xff},
+		{0x7b, 0xd3, 0x4e, 0xff},
+		{0xf5, 0xa6, 0x23, 0xff},
+		{0xb9, 0x6c, 0xf0, 0xff},
+	},
+}
+
+var ThemeLight = &Theme{
+	Name:       "light",
+	Background: color.RGBA{0xf4, 0xf4, 0xf4, 0xff},
+	Grid:       color.RGBA{0xdd, 0xdd, 0xdd, 0xff},
+	Food:       color.RGBA{0xe8, 0x30, 0x50, 0xff},
+	Hazard:     color.RGBA{0x40, 0x40, 0x40, 0x60},
+	Text:       color.RGBA{0x22, 0x22, 0x22, 0xff},
+	Palette: []color.RGBA{
+		{0x1f, 0x6f, 0xc4, 0xff},
+		{0x3d, 0x9a, 0x1a, 0xff},
+		{0xd4, 0x80, 0x00, 0xff},
+		{0x8a, 0x3c, 0xc8, 0xff},
+	},
+}
+
+var Themes = []*Theme{ThemeDark, ThemeLight}
+
+func ThemeByName(name string) (*Theme, error) {
+	for _, t := range Themes {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown theme %q", name)
+}
+
+// Overlay is extra information drawn over the board
+type Overlay int
+
+const (
+	// OverlayVoronoi tints each cell with the color of the snake that can
+	// reach it first
+	OverlayVoronoi Overlay = iota + 1
+)
+
+func ParseOverlay(name string) (Overlay, error) {
+	switch name {
+	case "voronoi":
+		return OverlayVoronoi, nil
+	}
+	return 0, fmt.Errorf("unknown overlay %q", name)
+}
+
+func (o *Options) theme() *Theme {
+	if o.Theme == nil {
+		return ThemeDark
+	}
+	return o.Theme
+}
+
+func (o *Options) cellSize() int {
+	if o.CellSize <= 0 {
+		return DefaultCellSize
+	}
+	return o.CellSize
+}
+
+// textScale is the size of a caption font pixel, growing with the cells
+func (o *Options) textScale() int {
+	if s := o.cellSize() / 10; s > 1 {
+		return s
+	}
+	return 1
+}
+
+// text falls back on white for themes without a text color
+func (t *Theme) text() color.RGBA {
+	if t.Text.A == 0 {
+		return color.RGBA{0xff, 0xff, 0xff, 0xff}
+	}
+	return t.Text
+}
+
+// Frame draws a frame as an image with y=0 at the bottom. Snakes use their
+// own color when it can be parsed. Eliminated snakes are left out. With
+// opts.Branding the image is taller than the board by the branding strips.
+func Frame(width, height int32, frame *bsgf.ViewFrame, opts Options) *image.RGBA {
+	cell := opts.cellSize()
+	theme := opts.theme()
+	img := drawBoard(width, height, frame, opts)
+	for i, s := range frame.Snakes {
+		if s.Death.Eliminated() {
+			continue
+		}
+		col := snakeColor(s.Color, i, theme)
+		for j := len(s.Body) - 1; j >= 0; j-- {
+			if j == 0 {
+				fillCell(img, cell, height, s.Body[j], 0, col)
+			} else {
+				fillCell(img, cell, height, s.Body[j], cell/8, col)
+			}
+		}
+	}
+	drawHazards(img, cell, height, frame, theme)
+	return brand(img, frame, opts)
+}
+
+// drawBoard draws the background, grid, overlays and food
+func drawBoard(width, height int32, frame *bsgf.ViewFrame, opts Options) *image.RGBA {
+	cell := opts.cellSize()
+	theme := opts.theme()
+	img := image.NewRGBA(image.Rect(0, 0, int(width)*cell, int(height)*cell))
+	draw.Draw(img, img.Bounds(), image.NewUniform(theme.Background), image.Point{}, draw.Src)
+	for x := 0; x < int(width); x++ {
+		for y := 0; y < int(height); y++ {
+			fillCell(img, cell, height, bsgf.ViewCoord{X: int32(x), Y: int32(y)}, 1, theme.Grid)
+			fillCell(img, cell, height, bsgf.ViewCoord{X: int32(x), Y: int32(y)}, 2, theme.Background)
+		}
+	}
+	for _, o := range opts.Overlays {
+		if o == OverlayVoronoi {
+			drawVoronoi(img, cell, width, height, frame, theme)
+		}
+	}
+	for _, c := range frame.Food {
+		fillCell(img, cell, height, c, cell/3, theme.Food)
+	}
+	return img
+}
+
+func drawHazards(img draw.Image, cell int, height int32, frame *bsgf.ViewFrame, theme *Theme) {
+	for _, c := range frame.Hazards {
+		blendCell(img, cell, height, c, theme.Hazard)
+	}
+}
+
+func drawVoronoi(img draw.Image, cell int, width, height int32, frame *bsgf.ViewFrame, theme *Theme) {
+	owners := analysis.Voronoi(width, height, frame)
+	for i, o := range owners {
+		if o < 0 {
+			continue
+		}
+		col := snakeColor(frame.Snakes[o].Color, o, theme)
+		col.A = 0x40
+		c := bsgf.ViewCoord{X: int32(i) % width, Y: int32(i) / width}
+		blendCell(img, cell, height, c, premultiply(col))
+	}
+}
+
+func cellRect(cell int, height int32, c bsgf.ViewCoord, inset int) image.Rectangle {
+	x := int(c.X) * cell
+	y := int(height-1-c.Y) * cell
+	return image.Rect(x+inset, y+inset, x+cell-inset, y+cell-inset)
+}
+
+func fillCell(img draw.Image, cell int, height int32, c bsgf.ViewCoord, inset int, col color.Color) {
+	draw.Draw(img, cellRect(cell, height, c, inset), image.NewUniform(col), image.Point{}, draw.Src)
+}
+
+func blendCell(img draw.Image, cell int, height int32, c bsgf.ViewCoord, col color.Color) {
+	draw.Draw(img, cellRect(cell, height, c, 0), image.NewUniform(col), image.Point{}, draw.Over)
+}
+
+// premultiply converts a straight alpha color to the premultiplied form
+// color.RGBA expects
+func premultiply(c color.RGBA) color.RGBA {
+	a := uint16(c.A)
+	return color.RGBA{
+		R: uint8(uint16(c.R) * a / 0xff),
+		G: uint8(uint16(c.G) * a / 0xff),
+		B: uint8(uint16(c.B) * a / 0xff),
+		A: c.A,
+	}
+}
+
+// snakeColor parses #rgb and #rrggbb colors, falling back on the theme palette
+func snakeColor(hex string, i int, theme *Theme) color.RGBA {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+		}
+	}
+	return theme.Palette[i%len(theme.Palette)]
+}
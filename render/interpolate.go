@@ -0,0 +1,105 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Point is a position on the board in cells, which may fall between cells
+type Point struct {
+	X, Y float64
+}
+
+// SnakePath is a snake's body part way through a move, head first
+type SnakePath struct {
+	ID    string
+	Color string
+	// Index of the snake in the from frame, used to pick palette colors
+	Index  int
+	Points []Point
+}
+
+// Interpolate returns the bodies of the snakes alive in from at fraction t
+// (0 to 1) of the way to the next frame. The head advances towards its new
+// cell while the tail retracts, and segments that jumped across the edge of
+// a wrapped board snap at the halfway point.
+func Interpolate(from, to *bsgf.ViewFrame, t float64) []SnakePath {
+	t = math.Max(0, math.Min(1, t))
+	var paths []SnakePath
+	for i := range from.Snakes {
+		s := &from.Snakes[i]
+		if !s.Alive() || len(s.Body) == 0 {
+			continue
+		}
+		path := SnakePath{ID: s.ID, Color: s.Color, Index: i}
+		after := findSnake(to, s.ID)
+		if after == nil || !after.Alive() || len(after.Body) == 0 {
+			for _, c := range s.Body {
+				path.Points = append(path.Points, Point{float64(c.X), float64(c.Y)})
+			}
+			paths = append(paths, path)
+			continue
+		}
+		for k := range after.Body {
+			a := s.Body[len(s.Body)-1]
+			if k < len(s.Body) {
+				a = s.Body[k]
+			}
+			path.Points = append(path.Points, lerp(a, after.Body[k], t))
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func lerp(a, b bsgf.ViewCoord, t float64) Point {
+	if a.Chebyshev(b) > 1 {
+		// wrapped around the board
+		if t < 0.5 {
+			return Point{float64(a.X), float64(a.Y)}
+		}
+		return Point{float64(b.X), float64(b.Y)}
+	}
+	return Point{
+		X: float64(a.X) + (float64(b.X)-float64(a.X))*t,
+		Y: float64(a.Y) + (float64(b.Y)-float64(a.Y))*t,
+	}
+}
+
+func findSnake(frame *bsgf.ViewFrame, snakeId string) *bsgf.ViewSnake {
+	for i := range frame.Snakes {
+		if frame.Snakes[i].ID == snakeId {
+			return &frame.Snakes[i]
+		}
+	}
+	return nil
+}
+
+// InterpolatedFrame draws from with its snakes at fraction t of the way to
+// the next frame. Food and hazards are drawn as they are in from.
+func InterpolatedFrame(width, height int32, from, to *bsgf.ViewFrame, t float64, opts Options) *image.RGBA {
+	cell := opts.cellSize()
+	theme := opts.theme()
+	img := drawBoard(width, height, from, opts)
+	for _, path := range Interpolate(from, to, t) {
+		col := image.NewUniform(snakeColor(path.Color, path.Index, theme))
+		for j := len(path.Points) - 1; j >= 0; j-- {
+			inset := cell / 8
+			if j == 0 {
+				inset = 0
+			}
+			draw.Draw(img, pointRect(cell, height, path.Points[j], inset), col, image.Point{}, draw.Src)
+		}
+	}
+	drawHazards(img, cell, height, from, theme)
+	return brand(img, from, opts)
+}
+
+func pointRect(cell int, height int32, p Point, inset int) image.Rectangle {
+	x := int(math.Round(p.X * float64(cell)))
+	y := int(math.Round((float64(height-1) - p.Y) * float64(cell)))
+	return image.Rect(x+inset, y+inset, x+cell-inset, y+cell-inset)
+}
@@ -0,0 +1,118 @@
+package render_test
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/render"
+)
+
+func testFrame() *bsgf.ViewFrame {
+	return &bsgf.ViewFrame{
+		Turn:    2,
+		Food:    []bsgf.ViewCoord{{X: 3, Y: 0}},
+		Hazards: []bsgf.ViewCoord{{X: 0, Y: 2}, {X: 3, Y: 0}},
+		Snakes: []bsgf.ViewSnake{
+			{ID: "a", Name: "Alpha", Health: 90, Color: "#ff0000", Body: []bsgf.ViewCoord{{X: 1, Y: 1}, {X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "b", Name: "Beta", Body: []bsgf.ViewCoord{{X: 2, Y: 2}}, Death: bsgf.ViewDeath{Cause: bsgf.CauseOutOfHealth, Turn: 1}},
+			{ID: "c", Name: "Gamma", Health: 50, Body: []bsgf.ViewCoord{{X: 3, Y: 2}, {X: 3, Y: 1}}},
+		},
+	}
+}
+
+func TestASCII(t *testing.T) {
+	want := "~..C\n" +
+		".A.c\n" +
+		"aa.*\n"
+	if got := render.ASCII(4, 3, testFrame()); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+	legend := render.Legend(testFrame())
+	for _, line := range []string{"A Alpha: health 90, length 3", "B Beta: eliminated turn 1 (out-of-health)", "C Gamma: health 50, length 2"} {
+		if !strings.Contains(legend, line) {
+			t.Errorf("expected %q in legend\n%s", line, legend)
+		}
+	}
+}
+
+func TestFrame(t *testing.T) {
+	opts := render.Options{CellSize: 10, Theme: render.ThemeLight}
+	img := render.Frame(4, 3, testFrame(), opts)
+	if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 30 {
+		t.Fatalf("expected a 40x30 image, got %v", b)
+	}
+	// y=0 is at the bottom, and the centre of a cell is its snake's color
+	center := func(x, y int) color.RGBA {
+		return img.RGBAAt(x*10+5, (2-y)*10+5)
+	}
+	if got := center(1, 1); got != (color.RGBA{0xff, 0, 0, 0xff}) {
+		t.Errorf("expected Alpha's head to be red, got %v", got)
+	}
+	if got := center(2, 2); got != render.ThemeLight.Background {
+		t.Errorf("expected the eliminated snake to be left out, got %v", got)
+	}
+	if got := center(3, 2); got != render.ThemeLight.Palette[2] {
+		t.Errorf("expected Gamma to get its palette color, got %v", got)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	from := &bsgf.ViewFrame{Snakes: []bsgf.ViewSnake{
+		{ID: "a", Body: []bsgf.ViewCoord{{X: 1, Y: 1}, {X: 1, Y: 0}}},
+		{ID: "w", Body: []bsgf.ViewCoord{{X: 0, Y: 3}, {X: 1, Y: 3}}},
+	}}
+	to := &bsgf.ViewFrame{Snakes: []bsgf.ViewSnake{
+		{ID: "a", Body: []bsgf.ViewCoord{{X: 2, Y: 1}, {X: 1, Y: 1}}},
+		// wrapped off the left edge
+		{ID: "w", Body: []bsgf.ViewCoord{{X: 4, Y: 3}, {X: 0, Y: 3}}},
+	}}
+	paths := render.Interpolate(from, to, 0.25)
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 paths, got %d", len(paths))
+	}
+	if want := []render.Point{{X: 1.25, Y: 1}, {X: 1, Y: 0.25}}; paths[0].Points[0] != want[0] || paths[0].Points[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, paths[0].Points)
+	}
+	if head := paths[1].Points[0]; head != (render.Point{X: 0, Y: 3}) {
+		t.Errorf("expected the wrapped head to stay until halfway, got %v", head)
+	}
+	if head := render.Interpolate(from, to, 0.75)[1].Points[0]; head != (render.Point{X: 4, Y: 3}) {
+		t.Errorf("expected the wrapped head to snap after halfway, got %v", head)
+	}
+}
+
+func TestGIF(t *testing.T) {
+	frames := []bsgf.ViewFrame{*testFrame(), *testFrame(), *testFrame()}
+	for i := range frames {
+		frames[i].Turn = int32(i)
+	}
+	game := &bsgf.ViewGame{Game: bsgf.ViewGameSettings{Width: 4, Height: 3}, Frames: frames}
+	anim, err := render.GIF(game, 0, 2, 200*time.Millisecond, render.Options{CellSize: 4, Substeps: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// two substeps between each pair of turns, then the last turn
+	if len(anim.Image) != 5 || len(anim.Delay) != 5 || anim.Delay[0] != 10 || anim.Delay[4] != 20 {
+		t.Errorf("expected 5 images, got %d with delays %v", len(anim.Image), anim.Delay)
+	}
+	_, err = render.GIF(game, 1, 3, time.Second, render.Options{})
+	if err == nil {
+		t.Error("expected an error for frames past the end")
+	}
+}
+
+func TestSVG(t *testing.T) {
+	var buf bytes.Buffer
+	err := render.SVG(&buf, 4, 3, testFrame(), render.Options{CellSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, `width="40"`) || !strings.Contains(svg, "#ff0000") {
+		t.Errorf("unexpected svg %s", svg)
+	}
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/analysis"
+)
+
+// SVG draws a frame the same way as Frame, as an svg document
+func SVG(w io.Writer, width, height int32, frame *bsgf.ViewFrame, opts Options) error {
+	cell := opts.cellSize()
+	theme := opts.theme()
+	out := bufio.NewWriter(w)
+	boardWidth, boardHeight := int(width)*cell, int(height)*cell
+	var top, bottom int
+	var items []brandItem
+	if opts.Branding != nil {
+		top, bottom, items = brandLayout(boardWidth, boardHeight, frame, opts)
+	}
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		boardWidth, top+boardHeight+bottom, boardWidth, top+boardHeight+bottom)
+	fmt.Fprintf(out, `<rect y="%d" width="%d" height="%d" fill="%s"/>`+"\n", top, boardWidth, boardHeight, svgColor(theme.Background))
+	if top > 0 {
+		fmt.Fprintf(out, `<g transform="translate(0 %d)">`+"\n", top)
+	}
+	rect := func(c bsgf.ViewCoord, inset int, col color.RGBA, opacity float64) {
+		r := cellRect(cell, height, c, inset)
+		fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"`, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), svgColor(col))
+		if opacity < 1 {
+			fmt.Fprintf(out, ` fill-opacity="%.2f"`, opacity)
+		}
+		fmt.Fprintln(out, "/>")
+	}
+	for x := int32(0); x < width; x++ {
+		for y := int32(0); y < height; y++ {
+			r := cellRect(cell, height, bsgf.ViewCoord{X: x, Y: y}, 1)
+			fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s"/>`+"\n",
+				r.Min.X, r.Min.Y, r.Dx(), r.Dy(), svgColor(theme.Grid))
+		}
+	}
+	for _, o := range opts.Overlays {
+		if o != OverlayVoronoi {
+			continue
+		}
+		for i, owner := range analysis.Voronoi(width, height, frame) {
+			if owner >= 0 {
+				c := bsgf.ViewCoord{X: int32(i) % width, Y: int32(i) / width}
+				rect(c, 0, snakeColor(frame.Snakes[owner].Color, owner, theme), 0.25)
+			}
+		}
+	}
+	for _, c := range frame.Food {
+		rect(c, cell/3, theme.Food, 1)
+	}
+	for i, s := range frame.Snakes {
+		if s.Death.Eliminated() {
+			continue
+		}
+		col := snakeColor(s.Color, i, theme)
+		for j := len(s.Body) - 1; j >= 0; j-- {
+			if j == 0 {
+				rect(s.Body[j], 0, col, 1)
+			} else {
+				rect(s.Body[j], cell/8, col, 1)
+			}
+		}
+	}
+	for _, c := range frame.Hazards {
+		rect(c, 0, theme.Hazard, float64(theme.Hazard.A)/0xff)
+	}
+	if top > 0 {
+		fmt.Fprintln(out, "</g>")
+	}
+	if opts.Branding != nil {
+		err := svgBranding(out, boardWidth, boardHeight, top, items, opts)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(out, "</svg>")
+	return out.Flush()
+}
+
+func svgColor(c color.RGBA) string {
+	if c.A == 0 || c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	// undo the premultiplied alpha, opacity is set separately
+	a := uint16(c.A)
+	return fmt.Sprintf("#%02x%02x%02x", uint16(c.R)*0xff/a, uint16(c.G)*0xff/a, uint16(c.B)*0xff/a)
+}
@@ -0,0 +1,197 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/rpc/bsgfpb"
+)
+
+// GameToProto converts a game to its protobuf message. FirstFrame and
+// LastTurn aren't sent, they come from the frames.
+func GameToProto(game *bsgf.ViewGame) *bsgfpb.Game {
+	msg := &bsgfpb.Game{
+		Game:   settingsToProto(&game.Game),
+		Frames: make([]*bsgfpb.Frame, len(game.Frames)),
+	}
+	for i := range game.Frames {
+		msg.Frames[i] = FrameToProto(&game.Frames[i])
+	}
+	for _, e := range game.Events {
+		msg.Events = append(msg.Events, &bsgfpb.Event{
+			Type:         string(e.Type),
+			Turn:         e.Turn,
+			SnakeId:      e.SnakeID,
+			Cause:        string(e.Cause),
+			EliminatedBy: e.EliminatedBy,
+		})
+	}
+	return msg
+}
+
+// GameFromProto converts a protobuf game back, failing when a ruleset
+// setting isn't valid json
+func GameFromProto(msg *bsgfpb.Game) (*bsgf.ViewGame, error) {
+	settings, err := settingsFromProto(msg.GetGame())
+	if err != nil {
+		return nil, err
+	}
+	game := &bsgf.ViewGame{Game: settings, Frames: make([]bsgf.ViewFrame, len(msg.GetFrames()))}
+	for i, f := range msg.GetFrames() {
+		game.Frames[i] = *FrameFromProto(f)
+	}
+	for _, e := range msg.GetEvents() {
+		game.Events = append(game.Events, bsgf.ViewEvent{
+			Type:         bsgf.EventType(e.GetType()),
+			Turn:         e.GetTurn(),
+			SnakeID:      e.GetSnakeId(),
+			Cause:        bsgf.DeathCause(e.GetCause()),
+			EliminatedBy: e.GetEliminatedBy(),
+		})
+	}
+	if len(game.Frames) > 0 {
+		game.FirstFrame = game.Frames[0]
+		game.LastTurn = game.Frames[len(game.Frames)-1].Turn
+	}
+	bsgf.Intern(game)
+	return game, nil
+}
+
+// FrameToProto converts a frame to its protobuf message
+func FrameToProto(frame *bsgf.ViewFrame) *bsgfpb.Frame {
+	msg := &bsgfpb.Frame{
+		Turn:         frame.Turn,
+		Snakes:       make([]*bsgfpb.Snake, len(frame.Snakes)),
+		Food:         coordsToProto(frame.Food),
+		Hazards:      coordsToProto(frame.Hazards),
+		HazardDamage: frame.HazardDamage,
+	}
+	for i, s := range frame.Snakes {
+		msg.Snakes[i] = &bsgfpb.Snake{
+			Id:         s.ID,
+			Name:       s.Name,
+			Url:        s.URL,
+			Body:       coordsToProto(s.Body),
+			Health:     s.Health,
+			Color:      s.Color,
+			HeadType:   s.HeadType,
+			TailType:   s.TailType,
+			Latency:    s.Latency,
+			Shout:      s.Shout,
+			Squad:      s.Squad,
+			ApiVersion: s.APIVersion,
+			Author:     s.Author,
+			Death: &bsgfpb.Death{
+				Cause:        string(s.Death.Cause),
+				Turn:         s.Death.Turn,
+				EliminatedBy: s.Death.EliminatedBy,
+			},
+		}
+	}
+	return msg
+}
+
+// FrameFromProto converts a protobuf frame back
+func FrameFromProto(msg *bsgfpb.Frame) *bsgf.ViewFrame {
+	frame := &bsgf.ViewFrame{
+		Turn:         msg.GetTurn(),
+		Snakes:       make([]bsgf.ViewSnake, len(msg.GetSnakes())),
+		Food:         coordsFromProto(msg.GetFood()),
+		Hazards:      coordsFromProto(msg.GetHazards()),
+		HazardDamage: msg.GetHazardDamage(),
+	}
+	for i, s := range msg.GetSnakes() {
+		frame.Snakes[i] = bsgf.ViewSnake{
+			ID:         s.GetId(),
+			Name:       s.GetName(),
+			URL:        s.GetUrl(),
+			Body:       coordsFromProto(s.GetBody()),
+			Health:     s.GetHealth(),
+			Color:      s.GetColor(),
+			HeadType:   s.GetHeadType(),
+			TailType:   s.GetTailType(),
+			Latency:    s.GetLatency(),
+			Shout:      s.GetShout(),
+			Squad:      s.GetSquad(),
+			APIVersion: s.GetApiVersion(),
+			Author:     s.GetAuthor(),
+			Death: bsgf.ViewDeath{
+				Cause:        bsgf.DeathCause(s.GetDeath().GetCause()),
+				Turn:         s.GetDeath().GetTurn(),
+				EliminatedBy: s.GetDeath().GetEliminatedBy(),
+			},
+		}
+	}
+	return frame
+}
+
+func settingsToProto(s *bsgf.ViewGameSettings) *bsgfpb.GameSettings {
+	msg := &bsgfpb.GameSettings{
+		Id: s.ID,
+		Ruleset: &bsgfpb.Ruleset{
+			Name:            s.Ruleset.Name,
+			Map:             s.Ruleset.Map,
+			MapAuthor:       s.Ruleset.MapAuthor,
+			FoodSpawnChance: s.Ruleset.FoodSpawnChance,
+			MinimumFood:     s.Ruleset.MinimumFood,
+			DamagePerTurn:   s.Ruleset.DamagePerTurn,
+		},
+		SnakeTimeout: s.Timeout,
+		Status:       s.Status,
+		Width:        s.Width,
+		Height:       s.Height,
+	}
+	if len(s.Ruleset.Settings) > 0 {
+		msg.Ruleset.Settings = make(map[string]string, len(s.Ruleset.Settings))
+		for k, v := range s.Ruleset.Settings {
+			msg.Ruleset.Settings[k] = string(v)
+		}
+	}
+	return msg
+}
+
+func settingsFromProto(msg *bsgfpb.GameSettings) (bsgf.ViewGameSettings, error) {
+	r := msg.GetRuleset()
+	s := bsgf.ViewGameSettings{
+		ID: msg.GetId(),
+		Ruleset: bsgf.ViewRuleset{
+			Name:            r.GetName(),
+			Map:             r.GetMap(),
+			MapAuthor:       r.GetMapAuthor(),
+			FoodSpawnChance: r.GetFoodSpawnChance(),
+			MinimumFood:     r.GetMinimumFood(),
+			DamagePerTurn:   r.GetDamagePerTurn(),
+		},
+		Timeout: msg.GetSnakeTimeout(),
+		Status:  msg.GetStatus(),
+		Width:   msg.GetWidth(),
+		Height:  msg.GetHeight(),
+	}
+	if len(r.GetSettings()) > 0 {
+		s.Ruleset.Settings = make(map[string]json.RawMessage, len(r.GetSettings()))
+		for k, v := range r.GetSettings() {
+			if !json.Valid([]byte(v)) {
+				return s, fmt.Errorf("invalid json for setting %s", k)
+			}
+			s.Ruleset.Settings[k] = json.RawMessage(v)
+		}
+	}
+	return s, nil
+}
+
+func coordsToProto(coords []bsgf.ViewCoord) []*bsgfpb.Coord {
+	msgs := make([]*bsgfpb.Coord, len(coords))
+	for i, c := range coords {
+		msgs[i] = &bsgfpb.Coord{X: c.X, Y: c.Y}
+	}
+	return msgs
+}
+
+func coordsFromProto(msgs []*bsgfpb.Coord) []bsgf.ViewCoord {
+	coords := make([]bsgf.ViewCoord, len(msgs))
+	for i, c := range msgs {
+		coords[i] = bsgf.ViewCoord{X: c.GetX(), Y: c.GetY()}
+	}
+	return coords
+}
@@ -0,0 +1,112 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+	"github.com/jlafayette/battlesnake-game-format-go/rpc"
+	"github.com/jlafayette/battlesnake-game-format-go/rpc/bsgfpb"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func play(t *testing.T) *bsgf.ViewGame {
+	t.Helper()
+	ruleset := bsgf.ViewRuleset{Name: string(bsgf.RulesetRoyale), Settings: map[string]json.RawMessage{"shrinkEveryNTurns": json.RawMessage(`"5"`)}}
+	players := []harness.Player{
+		{Name: "Alpha", Snake: harness.NewRandomSnake(1)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(2)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: 1, Ruleset: ruleset})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestProtoRoundTrip(t *testing.T) {
+	game := play(t)
+	data, err := proto.Marshal(rpc.GameToProto(game))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg bsgfpb.Game
+	err = proto.Unmarshal(data, &msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := rpc.GameFromProto(&msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range bsgf.DiffGames(game, got) {
+		t.Error(d)
+	}
+}
+
+func TestServer(t *testing.T) {
+	d, err := store.NewDir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := rpc.New(d)
+	ctx := context.Background()
+	game := play(t)
+	put, err := s.PutGame(ctx, &bsgfpb.PutGameRequest{Game: rpc.GameToProto(game)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if put.GetId() != game.Game.ID {
+		t.Errorf("expected ID %s, got %s", game.Game.ID, put.GetId())
+	}
+	list, err := s.ListGames(ctx, &bsgfpb.ListGamesRequest{})
+	if err != nil || len(list.GetIds()) != 1 {
+		t.Fatalf("expected one game, got %v, %v", list.GetIds(), err)
+	}
+	frame, err := s.GetFrame(ctx, &bsgfpb.GetFrameRequest{Id: game.Game.ID, Turn: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := bsgf.DiffFrames(&game.Frames[3], rpc.FrameFromProto(frame)); len(diffs) > 0 {
+		t.Errorf("turn 3: %v", diffs)
+	}
+	move, err := s.TranslateMove(ctx, &bsgfpb.TranslateMoveRequest{Id: game.Game.ID, Turn: 3, SnakeId: game.Frames[3].Snakes[0].ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state bsgf.MoveGameState
+	if err := json.Unmarshal(move.GetRequest(), &state); err != nil || state.Turn != 3 || state.You.ID != game.Frames[3].Snakes[0].ID {
+		t.Errorf("unexpected move request %s, %v", move.GetRequest(), err)
+	}
+	for _, test := range []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"missing game", func() error {
+			_, err := s.GetGame(ctx, &bsgfpb.GetGameRequest{Id: "missing"})
+			return err
+		}, codes.NotFound},
+		{"missing frame", func() error {
+			_, err := s.GetFrame(ctx, &bsgfpb.GetFrameRequest{Id: game.Game.ID, Turn: game.LastTurn + 1})
+			return err
+		}, codes.NotFound},
+		{"missing snake", func() error {
+			_, err := s.TranslateMove(ctx, &bsgfpb.TranslateMoveRequest{Id: game.Game.ID, Turn: 3, SnakeId: "nobody"})
+			return err
+		}, codes.NotFound},
+		{"no game", func() error {
+			_, err := s.PutGame(ctx, &bsgfpb.PutGameRequest{})
+			return err
+		}, codes.InvalidArgument},
+	} {
+		if code := status.Code(test.call()); code != test.code {
+			t.Errorf("%s: expected %s, got %s", test.name, test.code, code)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package rpc serves a store over gRPC, for services that aren't written
+// in Go. The service is defined in bsgf.proto, the generated code is in
+// bsgfpb.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/rpc/bsgfpb"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/jlafayette/battlesnake-game-format-go/rpc --go-grpc_out=. --go-grpc_opt=module=github.com/jlafayette/battlesnake-game-format-go/rpc bsgf.proto
+
+// Server implements bsgfpb.GameServiceServer on top of a Store. Register
+// it with bsgfpb.RegisterGameServiceServer.
+type Server struct {
+	bsgfpb.UnimplementedGameServiceServer
+	Store store.Store
+}
+
+func New(s store.Store) *Server {
+	return &Server{Store: s}
+}
+
+func (s *Server) GetGame(ctx context.Context, req *bsgfpb.GetGameRequest) (*bsgfpb.Game, error) {
+	game, err := s.game(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return GameToProto(game), nil
+}
+
+func (s *Server) PutGame(ctx context.Context, req *bsgfpb.PutGameRequest) (*bsgfpb.PutGameResponse, error) {
+	if req.GetGame() == nil {
+		return nil, status.Error(codes.InvalidArgument, "no game given")
+	}
+	game, err := GameFromProto(req.GetGame())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err = s.Store.Put(game)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &bsgfpb.PutGameResponse{Id: game.Game.ID}, nil
+}
+
+func (s *Server) GetFrame(ctx context.Context, req *bsgfpb.GetFrameRequest) (*bsgfpb.Frame, error) {
+	game, err := s.game(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	frame, err := game.FrameAt(req.GetTurn())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return FrameToProto(frame), nil
+}
+
+func (s *Server) TranslateMove(ctx context.Context, req *bsgfpb.TranslateMoveRequest) (*bsgfpb.TranslateMoveResponse, error) {
+	game, err := s.game(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	state, err := game.ToMove(req.GetTurn(), req.GetSnakeId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &bsgfpb.TranslateMoveResponse{Request: data}, nil
+}
+
+func (s *Server) ListGames(ctx context.Context, req *bsgfpb.ListGamesRequest) (*bsgfpb.ListGamesResponse, error) {
+	ids, err := s.Store.List()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &bsgfpb.ListGamesResponse{Ids: ids}, nil
+}
+
+// game loads a game from the store, with NotFound when it isn't there
+func (s *Server) game(id string) (*bsgf.ViewGame, error) {
+	has, err := s.Store.Has(id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if !has {
+		return nil, status.Errorf(codes.NotFound, "game %s not found", id)
+	}
+	game, err := s.Store.Get(id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return game, nil
+}
+
+// statusError picks the gRPC code for an error from this module
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, bsgf.ErrFrameNotFound), errors.Is(err, bsgf.ErrSnakeNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, bsgf.ErrInvalidGame):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, bsgf.ErrCorruptArchive), errors.Is(err, bsgf.ErrUnsupportedFormat):
+		return status.Error(codes.DataLoss, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
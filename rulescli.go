@@ -0,0 +1,274 @@
+package battlesnakegameformat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Rules CLI format - what `battlesnake play --output` from
+// github.com/BattlesnakeOfficial/rules writes. A line with the game, then
+// one move request per turn with an arbitrary snake as you, then a line
+// with the result. Eliminated snakes are dropped from the board instead of
+// being marked dead.
+
+// Ruleset version the rules CLI reports for its games
+const rulesCLIVersion = "cli"
+
+type rulesCLIGame struct {
+	ID      string      `json:"id"`
+	Ruleset MoveRuleset `json:"ruleset"`
+	Map     string      `json:"map"`
+	Timeout int32       `json:"timeout"`
+	Source  string      `json:"source"`
+}
+
+type rulesCLIResult struct {
+	WinnerID   string `json:"winnerId"`
+	WinnerName string `json:"winnerName"`
+	IsDraw     bool   `json:"isDraw"`
+}
+
+// EncodeRulesCLI writes game in the rules CLI format, so it can be used with
+// tools built around that output. Each turn's request is sent to the first
+// snake still alive, or the first snake when none are. The result line is
+// only written for finished games.
+func EncodeRulesCLI(game *ViewGame, w io.Writer) error {
+	out := &failureWriter{w: w, ioFailure: ioFailure{op: "write"}}
+	enc := json.NewEncoder(out)
+	err := enc.Encode(rulesCLIGame{
+		ID: game.Game.ID,
+		Ruleset: MoveRuleset{
+			Name:     game.Game.Ruleset.Name,
+			Version:  rulesCLIVersion,
+			Settings: game.Game.Ruleset.moveSettings(),
+		},
+		Map:     game.Game.Ruleset.Map,
+		Timeout: game.Game.Timeout,
+	})
+	if err != nil {
+		return out.blame(fmt.Errorf("error writing game line: %w", err))
+	}
+	for i := range game.Frames {
+		frame := &game.Frames[i]
+		if len(frame.Snakes) == 0 {
+			return fmt.Errorf("%w: frame %d has no snakes", ErrSnakeNotFound, i)
+		}
+		you := &frame.Snakes[0]
+		for j := range frame.Snakes {
+			if !frame.Snakes[j].Death.Eliminated() {
+				you = &frame.Snakes[j]
+				break
+			}
+		}
+		state := game.moveState(frame, you, nil)
+		state.Game.Ruleset.Version = rulesCLIVersion
+		err = enc.Encode(state)
+		if err != nil {
+			return out.blame(fmt.Errorf("error writing turn %d: %w", frame.Turn, err))
+		}
+	}
+	if game.Game.Status != "complete" {
+		return nil
+	}
+	result := rulesCLIResult{WinnerID: game.Winner(), IsDraw: true}
+	for _, p := range game.Placements() {
+		if result.WinnerID != "" && p.SnakeID == result.WinnerID {
+			result.WinnerName = p.Name
+			result.IsDraw = false
+		}
+	}
+	err = enc.Encode(result)
+	if err != nil {
+		return out.blame(fmt.Errorf("error writing result line: %w", err))
+	}
+	return nil
+}
+
+// DecodeRulesCLI reads a game written by the rules CLI. Snakes that drop off
+// the board are kept, marked dead on the turn they disappeared. The CLI
+// doesn't record why a snake died, so starving and losing a head to head
+// are worked out from the frames and anything else is CauseUnknown.
+func DecodeRulesCLI(data []byte) (*ViewGame, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	var lines [][]byte
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error reading rules cli output: %w", ErrCorruptArchive, err)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: missing game line", ErrCorruptArchive)
+	}
+	var header rulesCLIGame
+	err := json.Unmarshal(lines[0], &header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error unmarshalling game line: %w", ErrCorruptArchive, err)
+	}
+	game := ViewGame{
+		Game: ViewGameSettings{
+			ID:      header.ID,
+			Ruleset: viewRuleset(header.Ruleset, header.Map),
+			Timeout: header.Timeout,
+			Status:  "running",
+		},
+	}
+	for i, line := range lines[1:] {
+		var probe struct {
+			Board json.RawMessage `json:"board"`
+		}
+		err = json.Unmarshal(line, &probe)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error unmarshalling line %d: %w", ErrCorruptArchive, i+2, err)
+		}
+		if probe.Board == nil {
+			// only the result line, which is always last, has no board
+			if i != len(lines)-2 {
+				return nil, fmt.Errorf("%w: line %d has no board", ErrCorruptArchive, i+2)
+			}
+			var result rulesCLIResult
+			err = json.Unmarshal(line, &result)
+			if err != nil {
+				return nil, fmt.Errorf("%w: error unmarshalling result line: %w", ErrCorruptArchive, err)
+			}
+			game.Game.Status = "complete"
+			break
+		}
+		var state MoveGameState
+		err = json.Unmarshal(line, &state)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error unmarshalling turn line %d: %w", ErrCorruptArchive, i+2, err)
+		}
+		if len(game.Frames) == 0 {
+			game.Game.Width = state.Board.Width
+			game.Game.Height = state.Board.Height
+		}
+		game.Frames = append(game.Frames, game.frameFromRulesCLI(&state))
+	}
+	if len(game.Frames) > 0 {
+		game.FirstFrame = game.Frames[0]
+		game.LastTurn = game.Frames[len(game.Frames)-1].Turn
+	}
+	Intern(&game)
+	return &game, nil
+}
+
+// frameFromRulesCLI converts a move request to the next frame of game,
+// carrying over snakes that are no longer on the board
+func (game *ViewGame) frameFromRulesCLI(state *MoveGameState) ViewFrame {
+	frame := ViewFrame{
+		Turn:    state.Turn,
+		Food:    viewCoords(state.Board.Food),
+		Hazards: viewCoords(state.Board.Hazards),
+	}
+	onBoard := make(map[string]*MoveBattlesnake, len(state.Board.Snakes))
+	for i := range state.Board.Snakes {
+		onBoard[state.Board.Snakes[i].ID] = &state.Board.Snakes[i]
+	}
+	if len(game.Frames) == 0 {
+		for _, s := range state.Board.Snakes {
+			frame.Snakes = append(frame.Snakes, viewSnake(&s))
+		}
+		return frame
+	}
+	prev := &game.Frames[len(game.Frames)-1]
+	for _, before := range prev.Snakes {
+		s, ok := onBoard[before.ID]
+		switch {
+		case ok:
+			frame.Snakes = append(frame.Snakes, viewSnake(s))
+		case before.Death.Eliminated():
+			frame.Snakes = append(frame.Snakes, before)
+		default:
+			before.Death = inferDeath(&before, prev, state)
+			frame.Snakes = append(frame.Snakes, before)
+		}
+	}
+	return frame
+}
+
+// inferDeath guesses why snake, alive in prev, isn't on the board in state
+func inferDeath(snake *ViewSnake, prev *ViewFrame, state *MoveGameState) ViewDeath {
+	death := ViewDeath{Cause: CauseUnknown, Turn: state.Turn}
+	if snake.Health <= 1 {
+		death.Cause = CauseOutOfHealth
+		return death
+	}
+	if len(snake.Body) == 0 {
+		return death
+	}
+	head := snake.Body[0]
+	for _, other := range state.Board.Snakes {
+		before, ok := findSnake(prev, other.ID)
+		if !ok || len(before.Body) < len(snake.Body) {
+			continue
+		}
+		dx, dy := other.Head.X-head.X, other.Head.Y-head.Y
+		if dx*dx+dy*dy == 1 {
+			death.Cause = CauseHeadCollision
+			death.EliminatedBy = other.ID
+			return death
+		}
+	}
+	return death
+}
+
+func viewSnake(s *MoveBattlesnake) ViewSnake {
+	return ViewSnake{
+		ID:       s.ID,
+		Name:     s.Name,
+		Body:     viewCoords(s.Body),
+		Health:   s.Health,
+		Color:    s.Customizations.Color,
+		HeadType: s.Customizations.Head,
+		TailType: s.Customizations.Tail,
+		Latency:  s.Latency,
+		Shout:    s.Shout,
+		Squad:    s.Squad,
+	}
+}
+
+// viewRuleset is the reverse of ViewRuleset.moveSettings
+func viewRuleset(r MoveRuleset, mapName string) ViewRuleset {
+	s := r.Settings
+	ruleset := ViewRuleset{
+		Name:            r.Name,
+		FoodSpawnChance: s.FoodSpawnChance,
+		MinimumFood:     s.MinimumFood,
+		DamagePerTurn:   s.HazardDamagePerTurn,
+		Map:             mapName,
+		MapAuthor:       s.HazardMapAuthor,
+	}
+	if ruleset.Map == "" {
+		ruleset.Map = s.HazardMap
+	}
+	settings := make(map[string]json.RawMessage)
+	for k, v := range s.Extra {
+		settings[k] = v
+	}
+	if s.Royale.ShrinkEveryNTurns != 0 {
+		settings[settingShrinkEveryNTurns] = json.RawMessage(strconv.Itoa(int(s.Royale.ShrinkEveryNTurns)))
+	}
+	for k, v := range map[string]bool{
+		settingAllowBodyCollisions: s.Squad.AllowBodyCollisions,
+		settingSharedElimination:   s.Squad.SharedElimination,
+		settingSharedHealth:        s.Squad.SharedHealth,
+		settingSharedLength:        s.Squad.SharedLength,
+	} {
+		if v {
+			settings[k] = json.RawMessage("true")
+		}
+	}
+	if len(settings) > 0 {
+		ruleset.Settings = settings
+	}
+	return ruleset
+}
@@ -0,0 +1,65 @@
+package battlesnakegameformat_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// The rules CLI drops eliminated snakes, so only what's on the board is
+// expected to survive a round trip
+func TestRulesCLIRoundTrip(t *testing.T) {
+	for _, name := range []bsgf.RulesetName{bsgf.RulesetStandard, bsgf.RulesetRoyale} {
+		t.Run(string(name), func(t *testing.T) {
+			game := testGame(t, name)
+			var buf bytes.Buffer
+			err := bsgf.EncodeRulesCLI(game, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := bsgf.DecodeRulesCLI(buf.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Game.ID != game.Game.ID || got.Game.Ruleset.Name != game.Game.Ruleset.Name || got.Game.Width != game.Game.Width || got.LastTurn != game.LastTurn {
+				t.Fatalf("expected game %+v to turn %d, got %+v to turn %d", game.Game, game.LastTurn, got.Game, got.LastTurn)
+			}
+			if len(got.Frames) != len(game.Frames) {
+				t.Fatalf("expected %d frames, got %d", len(game.Frames), len(got.Frames))
+			}
+			for i := range game.Frames {
+				want, frame := &game.Frames[i], &got.Frames[i]
+				if frame.Turn != want.Turn || len(frame.Food) != len(want.Food) {
+					t.Errorf("turn %d: expected %d food, got turn %d with %d", want.Turn, len(want.Food), frame.Turn, len(frame.Food))
+				}
+				for _, s := range want.Snakes {
+					if s.Death.Eliminated() {
+						continue
+					}
+					snake := snakeByID(frame, s.ID)
+					if snake == nil || snake.Death.Eliminated() {
+						t.Errorf("turn %d: snake %s is missing or dead", want.Turn, s.ID)
+						continue
+					}
+					if !reflect.DeepEqual(snake.Body, s.Body) || snake.Health != s.Health {
+						t.Errorf("turn %d: snake %s is %v with %d health, expected %v with %d", want.Turn, s.ID, snake.Body, snake.Health, s.Body, s.Health)
+					}
+				}
+			}
+			if got.Winner() != game.Winner() {
+				t.Errorf("expected winner %q, got %q", game.Winner(), got.Winner())
+			}
+		})
+	}
+}
+
+func snakeByID(frame *bsgf.ViewFrame, id string) *bsgf.ViewSnake {
+	for i := range frame.Snakes {
+		if frame.Snakes[i].ID == id {
+			return &frame.Snakes[i]
+		}
+	}
+	return nil
+}
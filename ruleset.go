@@ -0,0 +1,137 @@
+package battlesnakegameformat
+
+import (
+	"strings"
+)
+
+// RulesetName identifies the game mode a game was played with.
+type RulesetName string
+
+// Rulesets known to the engine
+const (
+	RulesetStandard           RulesetName = "standard"
+	RulesetSolo               RulesetName = "solo"
+	RulesetRoyale             RulesetName = "royale"
+	RulesetSquad              RulesetName = "squad"
+	RulesetConstrictor        RulesetName = "constrictor"
+	RulesetWrapped            RulesetName = "wrapped"
+	RulesetWrappedConstrictor RulesetName = "wrapped_constrictor"
+)
+
+var knownRulesets = []RulesetName{
+	RulesetStandard,
+	RulesetSolo,
+	RulesetRoyale,
+	RulesetSquad,
+	RulesetConstrictor,
+	RulesetWrapped,
+	RulesetWrappedConstrictor,
+}
+
+// ParseRuleset normalizes name and reports whether it is a known ruleset.
+// Unknown names are still returned so newer game modes pass through.
+func ParseRuleset(name string) (RulesetName, bool) {
+	r := RulesetName(strings.ToLower(strings.TrimSpace(name)))
+	return r, r.Known()
+}
+
+// Known is true if r is one of the Ruleset constants.
+func (r RulesetName) Known() bool {
+	for _, k := range knownRulesets {
+		if r == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrapped is true for rulesets where snakes can move off one edge of the
+// board and come back on the opposite edge.
+func (r RulesetName) Wrapped() bool {
+	return r == RulesetWrapped || r == RulesetWrappedConstrictor
+}
+
+// Constrictor is true for rulesets where snakes grow every turn and never
+// lose health.
+func (r RulesetName) Constrictor() bool {
+	return r == RulesetConstrictor || r == RulesetWrappedConstrictor
+}
+
+// MapName identifies the board layout a game was played on.
+type MapName string
+
+// Official maps
+const (
+	MapStandard         MapName = "standard"
+	MapEmpty            MapName = "empty"
+	MapArcadeMaze       MapName = "arcade_maze"
+	MapRoyale           MapName = "royale"
+	MapSoloMaze         MapName = "solo_maze"
+	MapCastleWall       MapName = "hz_castle_wall"
+	MapColumns          MapName = "hz_columns"
+	MapExpandBox        MapName = "hz_expand_box"
+	MapExpandScatter    MapName = "hz_expand_scatter"
+	MapGrowBox          MapName = "hz_grow_box"
+	MapInnerWall        MapName = "hz_inner_wall"
+	MapIslandsBridges   MapName = "hz_islands_bridges"
+	MapRings            MapName = "hz_rings"
+	MapRiversAndBridges MapName = "hz_rivers_bridges"
+	MapScatter          MapName = "hz_scatter"
+	MapSpiral           MapName = "hz_spiral"
+	MapSinkholes        MapName = "sinkholes"
+	MapSnailMode        MapName = "snail_mode"
+)
+
+var knownMaps = []MapName{
+	MapStandard,
+	MapEmpty,
+	MapArcadeMaze,
+	MapRoyale,
+	MapSoloMaze,
+	MapCastleWall,
+	MapColumns,
+	MapExpandBox,
+	MapExpandScatter,
+	MapGrowBox,
+	MapInnerWall,
+	MapIslandsBridges,
+	MapRings,
+	MapRiversAndBridges,
+	MapScatter,
+	MapSpiral,
+	MapSinkholes,
+	MapSnailMode,
+}
+
+// ParseMap normalizes name and reports whether it is a known map. Unknown
+// names are still returned so new maps pass through.
+func ParseMap(name string) (MapName, bool) {
+	m := MapName(strings.ToLower(strings.TrimSpace(name)))
+	return m, m.Known()
+}
+
+// Known is true if m is one of the Map constants.
+func (m MapName) Known() bool {
+	for _, k := range knownMaps {
+		if m == k {
+			return true
+		}
+	}
+	return false
+}
+
+// RulesetName returns the parsed ruleset name.
+func (r ViewRuleset) RulesetName() RulesetName {
+	name, _ := ParseRuleset(r.Name)
+	return name
+}
+
+// MapName returns the parsed map name. Games recorded before maps were
+// added have no map and are treated as the standard map.
+func (r ViewRuleset) MapName() MapName {
+	if r.Map == "" {
+		return MapStandard
+	}
+	name, _ := ParseMap(r.Map)
+	return name
+}
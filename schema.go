@@ -0,0 +1,343 @@
+package battlesnakegameformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:generate go run ./internal/genschema
+
+// SchemaKind names one of the payloads there is a JSON Schema for
+type SchemaKind string
+
+const (
+	SchemaViewGame         SchemaKind = "view-game"
+	SchemaViewGameResponse SchemaKind = "view-game-response"
+	SchemaViewTurn         SchemaKind = "view-turn"
+	SchemaViewFrame        SchemaKind = "view-frame"
+	SchemaMoveGameState    SchemaKind = "move-game-state"
+	SchemaMoveResponse     SchemaKind = "move-response"
+)
+
+// SchemaKinds lists every kind Schema and ValidateJSON accept
+var SchemaKinds = []SchemaKind{
+	SchemaViewGame,
+	SchemaViewGameResponse,
+	SchemaViewTurn,
+	SchemaViewFrame,
+	SchemaMoveGameState,
+	SchemaMoveResponse,
+}
+
+var schemaTypes = map[SchemaKind]reflect.Type{
+	SchemaViewGame:         reflect.TypeOf(ViewGame{}),
+	SchemaViewGameResponse: reflect.TypeOf(ViewGameResponse{}),
+	SchemaViewTurn:         reflect.TypeOf(ViewTurn{}),
+	SchemaViewFrame:        reflect.TypeOf(ViewFrame{}),
+	SchemaMoveGameState:    reflect.TypeOf(MoveGameState{}),
+	SchemaMoveResponse:     reflect.TypeOf(MoveBattlesnakeResponse{}),
+}
+
+// Shorter names ParseSchemaKind accepts, named after the endpoints
+var schemaAliases = map[string]SchemaKind{
+	"move":     SchemaMoveGameState,
+	"start":    SchemaMoveGameState,
+	"end":      SchemaMoveGameState,
+	"game":     SchemaViewGameResponse,
+	"frames":   SchemaViewTurn,
+	"frame":    SchemaViewFrame,
+	"archive":  SchemaViewGame,
+	"response": SchemaMoveResponse,
+}
+
+// ParseSchemaKind reads a schema kind by name, or by the API endpoint the
+// payload is for: move, start and end for the requests a snake receives,
+// response for its reply, and game, frames and frame for the engine API.
+func ParseSchemaKind(name string) (SchemaKind, bool) {
+	if kind, ok := schemaAliases[name]; ok {
+		return kind, true
+	}
+	_, ok := schemaTypes[SchemaKind(name)]
+	return SchemaKind(name), ok
+}
+
+// SchemaError lists every way a payload didn't match its schema
+type SchemaError struct {
+	Kind     SchemaKind
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s schema: %s", e.Kind, strings.Join(e.Problems, "; "))
+}
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Integers encoded as strings, used by the ruleset settings. An empty
+// string leaves the setting unset, as null does.
+var stringIntPattern = regexp.MustCompile(`^(-?[0-9]+)?$`)
+
+type schemaNode struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	AnyOf                []*schemaNode          `json:"anyOf,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Properties           map[string]*schemaNode `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *schemaNode            `json:"items,omitempty"`
+	AdditionalProperties *schemaNode            `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*schemaNode `json:"$defs,omitempty"`
+
+	types []string
+}
+
+// Schema returns the JSON Schema for kind. Schemas are generated from the Go
+// structs so they can't drift; copies are kept in the schemas directory for
+// other languages.
+func Schema(kind SchemaKind) ([]byte, error) {
+	root, err := buildSchema(kind)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling schema to json: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ValidateJSON checks data against the schema for kind, returning a
+// *SchemaError listing every problem found.
+func ValidateJSON(data []byte, kind SchemaKind) error {
+	root, err := buildSchema(kind)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	err = dec.Decode(&v)
+	if err != nil {
+		return &SchemaError{Kind: kind, Problems: []string{"invalid json: " + err.Error()}}
+	}
+	s := schemaValidator{root: root}
+	s.check(v, root, "$")
+	if len(s.problems) > 0 {
+		return &SchemaError{Kind: kind, Problems: s.problems}
+	}
+	return nil
+}
+
+// ValidateValue is ValidateJSON for a value that has already been decoded,
+// by any json library, or built in code. It's marshalled back to json
+// first, so v can be anything json.Marshal accepts.
+func ValidateValue(v interface{}, kind SchemaKind) error {
+	switch v := v.(type) {
+	case []byte:
+		return ValidateJSON(v, kind)
+	case json.RawMessage:
+		return ValidateJSON(v, kind)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return &SchemaError{Kind: kind, Problems: []string{"can't be marshalled to json: " + err.Error()}}
+	}
+	return ValidateJSON(data, kind)
+}
+
+func buildSchema(kind SchemaKind) (*schemaNode, error) {
+	t, ok := schemaTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q", kind)
+	}
+	b := schemaBuilder{defs: make(map[string]*schemaNode), refPrefix: defsRefPrefix}
+	root := b.node(t)
+	root.Schema = schemaDialect
+	root.Title = t.Name()
+	root.Defs = b.defs
+	return root, nil
+}
+
+// Where refs point in a standalone schema
+const defsRefPrefix = "#/$defs/"
+
+type schemaBuilder struct {
+	defs      map[string]*schemaNode
+	refPrefix string
+}
+
+func (b *schemaBuilder) node(t reflect.Type) *schemaNode {
+	if t.Kind() != reflect.Struct && reflect.PtrTo(t).Implements(unmarshalerType) {
+		// custom decoding, anything goes
+		return &schemaNode{}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		n := b.node(t.Elem())
+		if n.Ref != "" {
+			return &schemaNode{AnyOf: []*schemaNode{n, withTypes(&schemaNode{}, "null")}}
+		}
+		return withTypes(n, append(n.types, "null")...)
+	case reflect.Struct:
+		if _, ok := b.defs[t.Name()]; !ok {
+			// placeholder first so recursive types terminate
+			b.defs[t.Name()] = &schemaNode{}
+			*b.defs[t.Name()] = *b.object(t)
+		}
+		return &schemaNode{Ref: b.refPrefix + t.Name()}
+	case reflect.Slice, reflect.Array:
+		n := withTypes(&schemaNode{}, "array", "null")
+		n.Items = b.node(t.Elem())
+		return n
+	case reflect.Map:
+		n := withTypes(&schemaNode{}, "object", "null")
+		n.AdditionalProperties = b.node(t.Elem())
+		return n
+	case reflect.String:
+		return withTypes(&schemaNode{}, "string")
+	case reflect.Bool:
+		return withTypes(&schemaNode{}, "boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return withTypes(&schemaNode{}, "integer")
+	case reflect.Float32, reflect.Float64:
+		return withTypes(&schemaNode{}, "number")
+	}
+	return &schemaNode{}
+}
+
+func (b *schemaBuilder) object(t reflect.Type) *schemaNode {
+	n := withTypes(&schemaNode{}, "object")
+	n.Properties = make(map[string]*schemaNode)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if hasOption(opts, "string") {
+			// integers that older engine versions also sent as numbers, or
+			// left empty
+			n.Properties[name] = withTypes(&schemaNode{Pattern: stringIntPattern.String()}, "string", "integer", "null")
+		} else {
+			n.Properties[name] = b.node(f.Type)
+		}
+		if !hasOption(opts, "omitempty") {
+			n.Required = append(n.Required, name)
+		}
+	}
+	sort.Strings(n.Required)
+	return n
+}
+
+func withTypes(n *schemaNode, types ...string) *schemaNode {
+	n.types = types
+	if len(types) == 1 {
+		n.Type = types[0]
+	} else {
+		n.Type = types
+	}
+	return n
+}
+
+type schemaValidator struct {
+	root     *schemaNode
+	problems []string
+}
+
+func (s *schemaValidator) fail(path, format string, args ...interface{}) {
+	s.problems = append(s.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (s *schemaValidator) check(v interface{}, n *schemaNode, path string) {
+	if n.Ref != "" {
+		n = s.root.Defs[strings.TrimPrefix(n.Ref, defsRefPrefix)]
+	}
+	if len(n.AnyOf) > 0 {
+		for _, option := range n.AnyOf {
+			sub := schemaValidator{root: s.root}
+			sub.check(v, option, path)
+			if len(sub.problems) == 0 {
+				return
+			}
+		}
+		s.fail(path, "doesn't match any allowed schema")
+		return
+	}
+	if len(n.types) == 0 {
+		return
+	}
+	actual := jsonType(v)
+	ok := false
+	for _, t := range n.types {
+		if t == actual || (t == "number" && actual == "integer") {
+			ok = true
+		}
+	}
+	if !ok {
+		s.fail(path, "expected %s, found %s", strings.Join(n.types, " or "), actual)
+		return
+	}
+	switch v := v.(type) {
+	case string:
+		if n.Pattern != "" && !stringIntPattern.MatchString(v) {
+			s.fail(path, "%q doesn't match %s", v, n.Pattern)
+		}
+	case []interface{}:
+		for i, item := range v {
+			s.check(item, n.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case map[string]interface{}:
+		for _, name := range n.Required {
+			if _, ok := v[name]; !ok {
+				s.fail(path, "missing required property %s", name)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if prop, ok := n.Properties[k]; ok {
+				s.check(v[k], prop, path+"."+k)
+			} else if n.AdditionalProperties != nil {
+				s.check(v[k], n.AdditionalProperties, path+"."+k)
+			}
+		}
+	}
+}
+
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
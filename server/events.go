@@ -0,0 +1,93 @@
+package server
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Delay between frame events, roughly the pace of a live game
+const eventInterval = 50 * time.Millisecond
+
+type event struct {
+	Type string      `json:"Type"`
+	Data interface{} `json:"Data"`
+}
+
+// serveEvents upgrades to a websocket and streams every frame of the game
+// followed by a game_end event, like the engine does for a live game
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request, id string) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "expected websocket upgrade", http.StatusBadRequest)
+		return
+	}
+	game, ok := s.game(w, id)
+	if !ok {
+		return
+	}
+	s.served(id)
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websockets not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	for i := range game.Frames {
+		err = writeEvent(rw, event{Type: "frame", Data: &game.Frames[i]})
+		if err != nil {
+			return
+		}
+		time.Sleep(eventInterval)
+	}
+	err = writeEvent(rw, event{Type: "game_end", Data: &game.Game})
+	if err != nil {
+		return
+	}
+	// close frame
+	rw.Write([]byte{0x88, 0x00})
+	rw.Flush()
+}
+
+// writeEvent sends an unmasked text frame, as servers do
+func writeEvent(w *bufio.ReadWriter, e event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	header := []byte{0x81}
+	switch {
+	case len(payload) < 126:
+		header = append(header, byte(len(payload)))
+	case len(payload) <= 0xffff:
+		header = append(header, 126, byte(len(payload)>>8), byte(len(payload)))
+	default:
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(len(payload)))
+		header = append(header, 127)
+		header = append(header, ext[:]...)
+	}
+	_, err = w.Write(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(payload)
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+// Number of decoded games kept in memory
+const cacheSize = 16
+
+// Largest page served by the frames endpoint
+const maxFrameLimit = 100
+
+// Server replays stored games over the same endpoints as the battlesnake
+// engine, so the board UI and other viewers can browse a local archive:
+//
+//	GET /games                      index of available games
+//	GET /games/{id}                 game settings and last frame
+//	GET /games/{id}/frames          frames, paged with offset and limit
+//	GET /games/{id}/events          websocket stream of frame events
+type Server struct {
+	Store store.Store
+	// OnGameServed, when set, is called each time a game's settings or
+	// event stream is sent to a viewer
+	OnGameServed func(id string)
+
+	mu    sync.Mutex
+	cache map[string]*bsgf.ViewGame
+	order []string
+}
+
+func New(s store.Store) *Server {
+	return &Server{Store: s, cache: make(map[string]*bsgf.ViewGame, cacheSize)}
+}
+
+type gameResponse struct {
+	Game      bsgf.ViewGameSettings `json:"Game"`
+	LastFrame *bsgf.ViewFrame       `json:"LastFrame"`
+}
+
+type indexer interface {
+	Index() ([]store.IndexEntry, error)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "games" {
+		http.NotFound(w, r)
+		return
+	}
+	switch {
+	case len(parts) == 1:
+		s.serveIndex(w)
+	case len(parts) == 2:
+		s.serveGame(w, parts[1])
+	case len(parts) == 3 && parts[2] == "frames":
+		s.serveFrames(w, r, parts[1])
+	case len(parts) == 3 && parts[2] == "events":
+		s.serveEvents(w, r, parts[1])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveIndex(w http.ResponseWriter) {
+	if ix, ok := s.Store.(indexer); ok {
+		entries, err := ix.Index()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, entries)
+		return
+	}
+	ids, err := s.Store.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entries := make([]store.IndexEntry, len(ids))
+	for i, id := range ids {
+		entries[i].ID = id
+	}
+	writeJSON(w, entries)
+}
+
+func (s *Server) serveGame(w http.ResponseWriter, id string) {
+	game, ok := s.game(w, id)
+	if !ok {
+		return
+	}
+	resp := gameResponse{Game: game.Game}
+	resp.LastFrame, _ = game.FinalFrame()
+	writeJSON(w, resp)
+	s.served(id)
+}
+
+func (s *Server) served(id string) {
+	if s.OnGameServed != nil {
+		s.OnGameServed(id)
+	}
+}
+
+func (s *Server) serveFrames(w http.ResponseWriter, r *http.Request, id string) {
+	game, ok := s.game(w, id)
+	if !ok {
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", maxFrameLimit)
+	if err != nil || limit < 0 {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+		return
+	}
+	if limit > maxFrameLimit {
+		limit = maxFrameLimit
+	}
+	frames := []bsgf.ViewFrame{}
+	if offset < len(game.Frames) {
+		end := offset + limit
+		if end > len(game.Frames) {
+			end = len(game.Frames)
+		}
+		frames = game.Frames[offset:end]
+	}
+	writeJSON(w, bsgf.ViewTurn{Frames: frames, Count: int32(len(frames))})
+}
+
+// game loads a game through the cache, writing an error response on failure
+func (s *Server) game(w http.ResponseWriter, id string) (*bsgf.ViewGame, bool) {
+	s.mu.Lock()
+	game, ok := s.cache[id]
+	s.mu.Unlock()
+	if ok {
+		return game, true
+	}
+	has, err := s.Store.Has(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	if !has {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return nil, false
+	}
+	game, err = s.Store.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	s.mu.Lock()
+	if _, ok := s.cache[id]; !ok {
+		if len(s.order) >= cacheSize {
+			delete(s.cache, s.order[0])
+			s.order = s.order[1:]
+		}
+		s.cache[id] = game
+		s.order = append(s.order, id)
+	}
+	s.mu.Unlock()
+	return game, true
+}
+
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,154 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+	"github.com/jlafayette/battlesnake-game-format-go/server"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+func newServer(t *testing.T) (*httptest.Server, *bsgf.ViewGame, *[]string) {
+	t.Helper()
+	d, err := store.NewDir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := []harness.Player{
+		{Name: "Alpha", Snake: harness.NewRandomSnake(1)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(2)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: 1, MaxTurns: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Put(game); err != nil {
+		t.Fatal(err)
+	}
+	s := server.New(d)
+	var served []string
+	s.OnGameServed = func(id string) { served = append(served, id) }
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return ts, game, &served
+}
+
+func get(t *testing.T, url string, v interface{}) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK && v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestServeGames(t *testing.T) {
+	ts, game, served := newServer(t)
+	var index []store.IndexEntry
+	get(t, ts.URL+"/games", &index)
+	if len(index) != 1 || index[0].ID != game.Game.ID || len(index[0].Snakes) != 2 {
+		t.Errorf("unexpected index %+v", index)
+	}
+	var resp bsgf.ViewGameResponse
+	get(t, ts.URL+"/games/"+game.Game.ID, &resp)
+	if resp.Game.ID != game.Game.ID || resp.LastFrame == nil || resp.LastFrame.Turn != game.LastTurn {
+		t.Errorf("unexpected game %+v", resp)
+	}
+	if len(*served) != 1 {
+		t.Errorf("expected the game to be served once, got %v", *served)
+	}
+	var turn bsgf.ViewTurn
+	get(t, ts.URL+"/games/"+game.Game.ID+"/frames?offset=1&limit=2", &turn)
+	if turn.Count != 2 || len(turn.Frames) != 2 || turn.Frames[0].Turn != 1 {
+		t.Errorf("expected turns 1 and 2, got %+v", turn)
+	}
+	get(t, ts.URL+"/games/"+game.Game.ID+"/frames?offset=100", &turn)
+	if turn.Count != 0 || len(turn.Frames) != 0 {
+		t.Errorf("expected no frames past the end, got %+v", turn)
+	}
+	for path, code := range map[string]int{
+		"/games/missing": http.StatusNotFound,
+		"/nothing":       http.StatusNotFound,
+		"/games/" + game.Game.ID + "/frames?limit=x": http.StatusBadRequest,
+		"/games/" + game.Game.ID + "/events":         http.StatusBadRequest,
+	} {
+		if got := get(t, ts.URL+path, nil); got != code {
+			t.Errorf("%s: expected %d, got %d", path, code, got)
+		}
+	}
+	resp2, err := http.Post(ts.URL+"/games", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp2.Body.Close()
+	if resp2.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected POST to not be allowed, got %d", resp2.StatusCode)
+	}
+}
+
+func TestServeEvents(t *testing.T) {
+	ts, game, _ := newServer(t)
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	io.WriteString(conn, "GET /games/"+game.Game.ID+"/events HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected handshake %d %v", resp.StatusCode, resp.Header)
+	}
+	var types []string
+	for {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(r, header); err != nil {
+			t.Fatal(err)
+		}
+		if header[0] == 0x88 {
+			break
+		}
+		n := uint64(header[1])
+		switch n {
+		case 126:
+			ext := make([]byte, 2)
+			io.ReadFull(r, ext)
+			n = uint64(binary.BigEndian.Uint16(ext))
+		case 127:
+			ext := make([]byte, 8)
+			io.ReadFull(r, ext)
+			n = binary.BigEndian.Uint64(ext)
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			t.Fatal(err)
+		}
+		var e struct{ Type string }
+		if err := json.Unmarshal(payload, &e); err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, e.Type)
+	}
+	if len(types) != len(game.Frames)+1 || types[len(types)-1] != "game_end" {
+		t.Errorf("expected %d frames and a game_end, got %v", len(game.Frames), types)
+	}
+}
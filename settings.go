@@ -0,0 +1,341 @@
+package battlesnakegameformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Keys of the ruleset object that have their own ViewRuleset field
+var viewRulesetKeys = []string{"foodSpawnChance", "minimumFood", "name", "map", "map_author", "damagePerTurn"}
+
+// Ruleset settings the move API nests under royale and squad
+const (
+	settingShrinkEveryNTurns   = "shrinkEveryNTurns"
+	settingAllowBodyCollisions = "allowBodyCollisions"
+	settingSharedElimination   = "sharedElimination"
+	settingSharedHealth        = "sharedHealth"
+	settingSharedLength        = "sharedLength"
+)
+
+// Ruleset keys holding integers. The engine has sent these as strings in
+// some versions and as numbers in others.
+var viewRulesetIntKeys = []string{"foodSpawnChance", "minimumFood", "damagePerTurn"}
+
+// Rules defaults for integer settings, used when translating games that
+// don't have them
+const (
+	DefaultFoodSpawnChance     int32 = 15
+	DefaultMinimumFood         int32 = 1
+	DefaultHazardDamagePerTurn int32 = 14
+)
+
+// Same fields as ViewRuleset without the json methods, to avoid recursion
+type plainViewRuleset ViewRuleset
+
+// viewRulesetJSON is how ViewRuleset is written, with unset integers left
+// out and genuine zeros kept
+type viewRulesetJSON struct {
+	FoodSpawnChance *int32 `json:"foodSpawnChance,omitempty,string"`
+	MinimumFood     *int32 `json:"minimumFood,omitempty,string"`
+	Name            string `json:"name"`
+	Map             string `json:"map"`
+	MapAuthor       string `json:"map_author"`
+	DamagePerTurn   *int32 `json:"damagePerTurn,omitempty,string"`
+}
+
+// UnmarshalJSON decodes the typed fields and keeps every other key in
+// Settings. Integer fields are accepted as numbers or strings, and missing,
+// null or empty ones are noted in Unset.
+func (r *ViewRuleset) UnmarshalJSON(data []byte) error {
+	var all map[string]json.RawMessage
+	err := json.Unmarshal(data, &all)
+	if err != nil {
+		return err
+	}
+	normalized := false
+	var unset []string
+	for _, k := range viewRulesetIntKeys {
+		raw, ok := all[k]
+		if !ok || unsetInt(raw) {
+			unset = append(unset, k)
+			if ok {
+				delete(all, k)
+				normalized = true
+			}
+			continue
+		}
+		fixed, err := stringInt(raw)
+		if err != nil {
+			return fmt.Errorf("error decoding ruleset %s: %w", k, err)
+		}
+		if !bytes.Equal(fixed, raw) {
+			all[k] = fixed
+			normalized = true
+		}
+	}
+	if normalized {
+		data, err = json.Marshal(all)
+		if err != nil {
+			return err
+		}
+	}
+	var p plainViewRuleset
+	err = json.Unmarshal(data, &p)
+	if err != nil {
+		return err
+	}
+	for _, k := range viewRulesetKeys {
+		delete(all, k)
+	}
+	p.Settings = nil
+	if len(all) > 0 {
+		p.Settings = all
+	}
+	p.Unset = unset
+	*r = ViewRuleset(p)
+	return nil
+}
+
+// unsetInt is true for integer settings sent without a value
+func unsetInt(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return true
+	}
+	var text string
+	if trimmed[0] != '"' || json.Unmarshal(trimmed, &text) != nil {
+		return false
+	}
+	switch strings.TrimSpace(text) {
+	case "", "null", "undefined":
+		return true
+	}
+	return false
+}
+
+// IsSet is false for the integer settings in Unset that are still 0. key is
+// the json key, such as "foodSpawnChance".
+func (r ViewRuleset) IsSet(key string) bool {
+	var v int32
+	switch key {
+	case "foodSpawnChance":
+		v = r.FoodSpawnChance
+	case "minimumFood":
+		v = r.MinimumFood
+	case "damagePerTurn":
+		v = r.DamagePerTurn
+	default:
+		_, ok := r.Settings[key]
+		return ok
+	}
+	return v != 0 || !containsString(r.Unset, key)
+}
+
+// intSetting is a pointer to v for writing, nil when key isn't set
+func (r ViewRuleset) intSetting(key string, v int32) *int32 {
+	if !r.IsSet(key) {
+		return nil
+	}
+	return &v
+}
+
+// intOr is v, or def when key isn't set
+func (r ViewRuleset) intOr(key string, v, def int32) int32 {
+	if !r.IsSet(key) {
+		return def
+	}
+	return v
+}
+
+// stringInt rewrites an integer sent as a number, a float or an empty
+// string into the quoted form the ,string struct tags expect
+func stringInt(raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return raw, nil
+	}
+	text := string(trimmed)
+	if trimmed[0] == '"' {
+		err := json.Unmarshal(trimmed, &text)
+		if err != nil {
+			return nil, err
+		}
+		text = strings.TrimSpace(text)
+	}
+	if _, err := strconv.ParseInt(text, 10, 32); err == nil {
+		if trimmed[0] == '"' && text == string(trimmed[1:len(trimmed)-1]) {
+			return raw, nil
+		}
+		return json.RawMessage(strconv.Quote(text)), nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || f != float64(int32(f)) {
+		return nil, fmt.Errorf("%s is not an integer", text)
+	}
+	return json.RawMessage(strconv.Quote(strconv.Itoa(int(f)))), nil
+}
+
+// MarshalJSON writes the typed fields followed by Settings. Integers that
+// aren't set are left out.
+func (r ViewRuleset) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(viewRulesetJSON{
+		FoodSpawnChance: r.intSetting("foodSpawnChance", r.FoodSpawnChance),
+		MinimumFood:     r.intSetting("minimumFood", r.MinimumFood),
+		Name:            r.Name,
+		Map:             r.Map,
+		MapAuthor:       r.MapAuthor,
+		DamagePerTurn:   r.intSetting("damagePerTurn", r.DamagePerTurn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return appendSettings(data, r.Settings, viewRulesetKeys)
+}
+
+// Setting returns the value of an extra ruleset setting. String values are
+// unquoted, anything else is returned as json.
+func (r ViewRuleset) Setting(key string) (string, bool) {
+	raw, ok := r.Settings[key]
+	if !ok {
+		return "", false
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, true
+	}
+	return string(raw), true
+}
+
+func (r ViewRuleset) settingInt(key string) int32 {
+	s, _ := r.Setting(key)
+	v, _ := strconv.ParseInt(s, 10, 32)
+	return int32(v)
+}
+
+func (r ViewRuleset) settingBool(key string) bool {
+	s, _ := r.Setting(key)
+	v, _ := strconv.ParseBool(s)
+	return v
+}
+
+// moveSettings translates the ruleset to the move API, which nests royale
+// and squad settings and gets the remaining settings in Extra. Integers
+// that aren't set get the rules defaults, as the move API always has them.
+func (r ViewRuleset) moveSettings() MoveSettings {
+	settings := MoveSettings{
+		FoodSpawnChance:     r.intOr("foodSpawnChance", r.FoodSpawnChance, DefaultFoodSpawnChance),
+		MinimumFood:         r.intOr("minimumFood", r.MinimumFood, DefaultMinimumFood),
+		HazardDamagePerTurn: r.intOr("damagePerTurn", r.DamagePerTurn, DefaultHazardDamagePerTurn),
+		HazardMap:           r.Map,
+		HazardMapAuthor:     r.MapAuthor,
+		Royale: MoveRoyale{
+			ShrinkEveryNTurns: r.settingInt(settingShrinkEveryNTurns),
+		},
+		Squad: MoveSquad{
+			AllowBodyCollisions: r.settingBool(settingAllowBodyCollisions),
+			SharedElimination:   r.settingBool(settingSharedElimination),
+			SharedHealth:        r.settingBool(settingSharedHealth),
+			SharedLength:        r.settingBool(settingSharedLength),
+		},
+	}
+	for k, v := range r.Settings {
+		switch k {
+		case settingShrinkEveryNTurns, settingAllowBodyCollisions, settingSharedElimination, settingSharedHealth, settingSharedLength:
+			continue
+		}
+		if containsString(moveSettingsKeys, k) {
+			continue
+		}
+		if settings.Extra == nil {
+			settings.Extra = make(map[string]json.RawMessage)
+		}
+		settings.Extra[k] = v
+	}
+	return settings
+}
+
+// Keys of the settings object that have their own MoveSettings field
+var moveSettingsKeys = []string{"foodSpawnChance", "minimumFood", "hazardDamagePerTurn", "hazardMap", "hazardMapAuthor", "royale", "squad"}
+
+type plainMoveSettings MoveSettings
+
+// UnmarshalJSON decodes the typed fields and keeps every other key in
+// Extra.
+func (s *MoveSettings) UnmarshalJSON(data []byte) error {
+	var p plainMoveSettings
+	err := json.Unmarshal(data, &p)
+	if err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	err = json.Unmarshal(data, &all)
+	if err != nil {
+		return err
+	}
+	for _, k := range moveSettingsKeys {
+		delete(all, k)
+	}
+	p.Extra = nil
+	if len(all) > 0 {
+		p.Extra = all
+	}
+	*s = MoveSettings(p)
+	return nil
+}
+
+// MarshalJSON writes the typed fields followed by Extra.
+func (s MoveSettings) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(plainMoveSettings(s))
+	if err != nil {
+		return nil, err
+	}
+	return appendSettings(data, s.Extra, moveSettingsKeys)
+}
+
+// appendSettings adds extra keys to the end of a marshaled object, sorted
+// so output is stable. Keys that collide with a typed field are skipped.
+func appendSettings(object []byte, extra map[string]json.RawMessage, known []string) ([]byte, error) {
+	if len(extra) == 0 {
+		return object, nil
+	}
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if !containsString(known, k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.Write(object[:len(object)-1])
+	for _, k := range keys {
+		name, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value := extra[k]
+		if !json.Valid(value) {
+			return nil, fmt.Errorf("invalid json for setting %s", k)
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
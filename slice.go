@@ -0,0 +1,127 @@
+package battlesnakegameformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Slice returns a copy of the game containing only turns from..to
+// (inclusive), renumbered to start at turn 0. Settings and snake metadata
+// are kept as is, and the clip is adjusted so it validates wherever the
+// game did:
+//
+//   - snakes eliminated before from stay in the clip as eliminated on turn
+//     0, and their elimination events are moved to turn 0 with them
+//   - snakes eliminated after to are alive throughout, as they were
+//   - bodies and health are as they were on from, so a stacked tail from
+//     eating the turn before is kept rather than reset to a starting stack
+//   - a complete game cut before its last turn becomes "running", so its
+//     result isn't reported as final
+//   - Unknown fields of the kept frames and events move with them
+func (game *ViewGame) Slice(from, to int32) (*ViewGame, error) {
+	if from < 0 || to < from || int(to) >= len(game.Frames) {
+		return nil, fmt.Errorf("invalid turn range %d-%d for game with %d frames", from, to, len(game.Frames))
+	}
+	clip := &ViewGame{
+		Game:   game.Game,
+		Frames: make([]ViewFrame, 0, to-from+1),
+	}
+	clip.Game.Ruleset.Settings = maps.Clone(game.Game.Ruleset.Settings)
+	clip.Game.Ruleset.Unset = slices.Clone(game.Game.Ruleset.Unset)
+	if int(to) < len(game.Frames)-1 && clip.Game.Status == "complete" {
+		clip.Game.Status = "running"
+	}
+	for i := from; i <= to; i++ {
+		frame := *game.Frames[i].Clone()
+		frame.Turn -= from
+		for j := range frame.Snakes {
+			death := &frame.Snakes[j].Death
+			if death.Eliminated() {
+				death.Turn = clipTurn(death.Turn, from)
+			}
+		}
+		clip.Frames = append(clip.Frames, frame)
+	}
+	// index of each kept event in the clip, for moving its unknown fields
+	events := make(map[int]int)
+	for i, e := range game.Events {
+		switch {
+		case e.Turn > to:
+			continue
+		case e.Turn < from:
+			if e.Type != EventElimination {
+				continue
+			}
+		}
+		e.Turn = clipTurn(e.Turn, from)
+		events[i] = len(clip.Events)
+		clip.Events = append(clip.Events, e)
+	}
+	clip.Unknown = sliceUnknown(game.Unknown, from, to, events)
+	clip.FirstFrame = clip.Frames[0]
+	clip.LastTurn = clip.Frames[len(clip.Frames)-1].Turn
+	return clip, nil
+}
+
+// clipTurn renumbers turn for a clip starting at from, turns before the
+// clip becoming 0
+func clipTurn(turn, from int32) int32 {
+	if turn < from {
+		return 0
+	}
+	return turn - from
+}
+
+// sliceUnknown moves the unknown fields of a game to the paths they have in
+// a clip of turns from..to, dropping those of frames and events that aren't
+// in it. FirstFrame's only stay when the clip starts at turn 0.
+func sliceUnknown(unknown map[string]map[string]json.RawMessage, from, to int32, events map[int]int) map[string]map[string]json.RawMessage {
+	var out map[string]map[string]json.RawMessage
+	for path, fields := range unknown {
+		moved, ok := path, true
+		switch {
+		case strings.HasPrefix(path, "Frames["):
+			moved, ok = reindexPath(path, "Frames", func(i int) (int, bool) {
+				return i - int(from), i >= int(from) && i <= int(to)
+			})
+		case strings.HasPrefix(path, "Events["):
+			moved, ok = reindexPath(path, "Events", func(i int) (int, bool) {
+				j, kept := events[i]
+				return j, kept
+			})
+		case path == "FirstFrame" || strings.HasPrefix(path, "FirstFrame."):
+			ok = from == 0
+		}
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]map[string]json.RawMessage)
+		}
+		out[moved] = maps.Clone(fields)
+	}
+	return out
+}
+
+// reindexPath replaces the index in a path starting with field[i] by the
+// one index gives, or reports false when index drops it
+func reindexPath(path, field string, index func(i int) (int, bool)) (string, bool) {
+	rest := path[len(field)+1:]
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return "", false
+	}
+	i, err := strconv.Atoi(rest[:end])
+	if err != nil {
+		return "", false
+	}
+	j, ok := index(i)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%s[%d]%s", field, j, rest[end+1:]), true
+}
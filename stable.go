@@ -0,0 +1,55 @@
+package battlesnakegameformat
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalStable encodes game to json that is byte for byte the same for the
+// same game: struct fields keep their declared order, map keys are sorted
+// and raw ruleset settings are compacted so whitespace from the source
+// doesn't leak through.
+func MarshalStable(game *ViewGame) ([]byte, error) {
+	c := game.Clone()
+	for k, raw := range c.Game.Ruleset.Settings {
+		var buf bytes.Buffer
+		err := json.Compact(&buf, raw)
+		if err != nil {
+			return nil, fmt.Errorf("error compacting setting %s: %w", k, err)
+		}
+		c.Game.Ruleset.Settings[k] = buf.Bytes()
+	}
+	contents, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling ViewGame to json: %w", err)
+	}
+	return withUnknown(contents, c.Unknown)
+}
+
+// EncodeStable is like Encode but uses MarshalStable and leaves the encoder
+// and creation time out of the metadata, so the archive bytes only change
+// when the game does.
+func EncodeStable(game *ViewGame, buf *bytes.Buffer) error {
+	contents, err := MarshalStable(game)
+	if err != nil {
+		return err
+	}
+	return writeArchive(contents, newMetadata(game), buf)
+}
+
+// ContentHash returns the hex sha256 of the normalized, stable encoding of
+// game. Two downloads of the same game hash the same even if the engine
+// listed food or snakes in a different order.
+func ContentHash(game *ViewGame) (string, error) {
+	c := game.Clone()
+	Normalize(c)
+	contents, err := MarshalStable(c)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,166 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Cached index, hidden so List skips it
+const indexFile = ".index.json"
+
+// IndexEntry summarizes a stored game for searching without decoding it
+type IndexEntry struct {
+	ID       string       `json:"id"`
+	Path     string       `json:"path"`
+	Ruleset  string       `json:"ruleset"`
+	Map      string       `json:"map"`
+	LastTurn int32        `json:"lastTurn"`
+	Snakes   []IndexSnake `json:"snakes"`
+	// ModTime and Size of the archive when it was indexed
+	ModTime int64 `json:"modTime"`
+	Size    int64 `json:"size"`
+}
+
+type IndexSnake struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Author       string `json:"author"`
+	DeathCause   string `json:"deathCause,omitempty"`
+	DeathTurn    int32  `json:"deathTurn,omitempty"`
+	EliminatedBy string `json:"eliminatedBy,omitempty"`
+}
+
+// Index returns an entry for every game in the directory. Entries are cached
+// in a hidden file and only archives that changed since the last call are
+// read again.
+func (d *Dir) Index() ([]IndexEntry, error) {
+	cached := make(map[string]IndexEntry)
+	data, err := ioutil.ReadFile(filepath.Join(d.Path, indexFile))
+	if err == nil {
+		var entries []IndexEntry
+		if json.Unmarshal(data, &entries) == nil {
+			for _, e := range entries {
+				cached[e.ID] = e
+			}
+		}
+	}
+	ids, err := d.List()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]IndexEntry, 0, len(ids))
+	changed := len(cached) != len(ids)
+	for _, id := range ids {
+		path := d.file(id)
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("error indexing game %s: %w", id, err)
+		}
+		e, ok := cached[id]
+		if ok && e.ModTime == stat.ModTime().UnixNano() && e.Size == stat.Size() {
+			entries = append(entries, e)
+			continue
+		}
+		e, err = indexArchive(id, path)
+		if err != nil {
+			return nil, err
+		}
+		e.ModTime = stat.ModTime().UnixNano()
+		e.Size = stat.Size()
+		entries = append(entries, e)
+		changed = true
+	}
+	if changed {
+		data, err := json.Marshal(entries)
+		if err == nil {
+			// the index is only a cache, failing to save it isn't fatal
+			ioutil.WriteFile(filepath.Join(d.Path, indexFile), data, 0644)
+		}
+	}
+	return entries, nil
+}
+
+func indexArchive(id string, path string) (IndexEntry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return IndexEntry{}, fmt.Errorf("error reading game %s: %w", id, err)
+	}
+	m, err := bsgf.DecodeMetadata(data)
+	if err != nil {
+		return IndexEntry{}, fmt.Errorf("error indexing game %s: %w", id, err)
+	}
+	e := IndexEntry{
+		ID:       id,
+		Path:     path,
+		Ruleset:  m.Ruleset,
+		Map:      m.Map,
+		LastTurn: m.LastTurn,
+	}
+	for _, s := range m.Snakes {
+		e.Snakes = append(e.Snakes, IndexSnake{
+			ID:           s.ID,
+			Name:         s.Name,
+			Author:       s.Author,
+			DeathCause:   string(s.Cause),
+			DeathTurn:    s.DeathTurn,
+			EliminatedBy: s.EliminatedBy,
+		})
+	}
+	return e, nil
+}
+
+// Query matches index entries. Empty fields match anything.
+type Query struct {
+	// Snake matches a snake name or ID, case insensitively for names
+	Snake   string
+	Ruleset string
+	Map     string
+	// DiedTo matches a death cause. Combined with Snake it only matches
+	// games where that snake died this way.
+	DiedTo string
+}
+
+func (q *Query) Match(e *IndexEntry) bool {
+	if q.Ruleset != "" && !strings.EqualFold(q.Ruleset, e.Ruleset) {
+		return false
+	}
+	if q.Map != "" && !strings.EqualFold(q.Map, e.Map) {
+		return false
+	}
+	if q.Snake == "" && q.DiedTo == "" {
+		return true
+	}
+	for _, s := range e.Snakes {
+		if q.Snake != "" && s.ID != q.Snake && !strings.EqualFold(s.Name, q.Snake) {
+			continue
+		}
+		if q.DiedTo != "" && s.DeathCause != q.DiedTo {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Search returns the index entries matching q, sorted by ID
+func (d *Dir) Search(q Query) ([]IndexEntry, error) {
+	entries, err := d.Index()
+	if err != nil {
+		return nil, err
+	}
+	var matches []IndexEntry
+	for i := range entries {
+		if q.Match(&entries[i]) {
+			matches = append(matches, entries[i])
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches, nil
+}
@@ -0,0 +1,136 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Store holds encoded games by ID
+type Store interface {
+	Get(id string) (*bsgf.ViewGame, error)
+	Put(game *bsgf.ViewGame) error
+	Has(id string) (bool, error)
+	List() ([]string, error)
+}
+
+// Extension of encoded game files
+const Extension = ".bsgf"
+
+// Dir stores each game as <id>.bsgf in a directory
+type Dir struct {
+	Path string
+}
+
+func NewDir(path string) (*Dir, error) {
+	err := os.MkdirAll(path, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating store directory: %w", err)
+	}
+	return &Dir{Path: path}, nil
+}
+
+func (d *Dir) file(id string) string {
+	return filepath.Join(d.Path, id+Extension)
+}
+
+// Get decodes the game straight from its file, without reading it all into
+// memory first
+func (d *Dir) Get(id string) (*bsgf.ViewGame, error) {
+	f, err := os.Open(d.file(id))
+	if err != nil {
+		return nil, fmt.Errorf("error reading game %s: %w", id, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading game %s: %w", id, err)
+	}
+	return bsgf.DecodeReaderAt(f, info.Size())
+}
+
+// Put encodes the game and writes it atomically, replacing any existing
+// copy. Games that fail (*bsgf.ViewGame).Validate are rejected.
+func (d *Dir) Put(game *bsgf.ViewGame) error {
+	if game.Game.ID == "" || strings.ContainsAny(game.Game.ID, `/\`) {
+		return fmt.Errorf("invalid game ID %q", game.Game.ID)
+	}
+	err := game.Validate()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	err = bsgf.Encode(game, &buf)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(d.Path, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(buf.Bytes())
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing game %s: %w", game.Game.ID, err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("error writing game %s: %w", game.Game.ID, err)
+	}
+	err = os.Rename(tmp.Name(), d.file(game.Game.ID))
+	if err != nil {
+		return fmt.Errorf("error writing game %s: %w", game.Game.ID, err)
+	}
+	return nil
+}
+
+func (d *Dir) Has(id string) (bool, error) {
+	_, err := os.Stat(d.file(id))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking for game %s: %w", id, err)
+	}
+	return true, nil
+}
+
+// List returns the IDs of all stored games in sorted order
+func (d *Dir) List() ([]string, error) {
+	entries, err := ioutil.ReadDir(d.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error listing store directory: %w", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, Extension) {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, Extension))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Each decodes every stored game across worker goroutines and passes them to
+// fn, in ID order when opts.Ordered is set.
+func (d *Dir) Each(opts bsgf.ParallelOptions, fn func(game *bsgf.ViewGame) error) error {
+	ids, err := d.List()
+	if err != nil {
+		return err
+	}
+	decode := func(i int) (*bsgf.ViewGame, error) {
+		return d.Get(ids[i])
+	}
+	return bsgf.DecodeParallel(len(ids), decode, opts, func(i int, game *bsgf.ViewGame) error {
+		return fn(game)
+	})
+}
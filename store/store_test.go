@@ -0,0 +1,171 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+func play(t *testing.T, seed int64, ruleset string) *bsgf.ViewGame {
+	t.Helper()
+	players := []harness.Player{
+		{Name: "Alpha", Snake: harness.NewRandomSnake(seed)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(seed + 1)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: seed, Ruleset: bsgf.ViewRuleset{Name: ruleset}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestDir(t *testing.T) {
+	d, err := store.NewDir(filepath.Join(t.TempDir(), "games"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	games := []*bsgf.ViewGame{play(t, 1, "standard"), play(t, 2, "royale"), play(t, 3, "standard")}
+	var ids []string
+	for _, game := range games {
+		err = d.Put(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, game.Game.ID)
+	}
+	// replacing a game keeps one copy
+	err = d.Put(games[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, game := range games {
+		got, err := d.Get(game.Game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diffs := bsgf.DiffGames(game, got); len(diffs) > 0 {
+			t.Errorf("%s: %v", game.Game.ID, diffs[0])
+		}
+	}
+	listed, err := d.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	if !reflect.DeepEqual(listed, sorted) {
+		t.Errorf("expected %v listed, got %v", sorted, listed)
+	}
+	if ok, err := d.Has(ids[1]); !ok || err != nil {
+		t.Errorf("expected to have %s, got %v, %v", ids[1], ok, err)
+	}
+	if ok, err := d.Has("missing"); ok || err != nil {
+		t.Errorf("expected not to have missing, got %v, %v", ok, err)
+	}
+	if _, err := d.Get("missing"); err == nil {
+		t.Error("expected an error getting a missing game")
+	}
+	var mu sync.Mutex
+	var each []string
+	err = d.Each(bsgf.ParallelOptions{Ordered: true}, func(game *bsgf.ViewGame) error {
+		mu.Lock()
+		defer mu.Unlock()
+		each = append(each, game.Game.ID)
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(each, sorted) {
+		t.Errorf("expected Each to visit %v, got %v, %v", sorted, each, err)
+	}
+}
+
+func TestDirPutInvalid(t *testing.T) {
+	d, err := store.NewDir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	game := play(t, 1, "standard")
+	game.Game.ID = "../escape"
+	if d.Put(game) == nil {
+		t.Error("expected an error for an ID with a slash")
+	}
+	game = play(t, 1, "standard")
+	game.Frames[1].Snakes[0].Body = nil
+	if d.Put(game) == nil {
+		t.Error("expected an error for an invalid game")
+	}
+	if ids, _ := d.List(); len(ids) != 0 {
+		t.Errorf("expected nothing stored, got %v", ids)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	d, err := store.NewDir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	games := []*bsgf.ViewGame{play(t, 1, "standard"), play(t, 2, "royale")}
+	for _, game := range games {
+		if err := d.Put(game); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loser := func(game *bsgf.ViewGame) bsgf.ViewSnake {
+		for _, s := range game.Frames[len(game.Frames)-1].Snakes {
+			if s.Death.Eliminated() {
+				return s
+			}
+		}
+		t.Fatalf("nobody died in %s", game.Game.ID)
+		return bsgf.ViewSnake{}
+	}
+	dead := loser(games[0])
+	tests := []struct {
+		q    store.Query
+		want []string
+	}{
+		{store.Query{}, []string{games[0].Game.ID, games[1].Game.ID}},
+		{store.Query{Ruleset: "ROYALE"}, []string{games[1].Game.ID}},
+		{store.Query{Snake: "nobody"}, nil},
+		{store.Query{Snake: "alpha", Ruleset: "standard"}, []string{games[0].Game.ID}},
+		{store.Query{Snake: dead.Name, DiedTo: string(dead.Death.Cause), Ruleset: "standard"}, []string{games[0].Game.ID}},
+	}
+	for i, test := range tests {
+		// the second pass reads the cached index
+		for pass := 0; pass < 2; pass++ {
+			entries, err := d.Search(test.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.ID)
+			}
+			sort.Strings(test.want)
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Errorf("query %d %+v: expected %v, got %v", i, test.q, test.want, got)
+			}
+		}
+	}
+	// changed archives are indexed again
+	changed := games[1].Clone()
+	changed.Game.Ruleset.Name = "standard"
+	if err := d.Put(changed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(d.Path, ".index.json")); err != nil {
+		t.Errorf("expected a cached index, got %v", err)
+	}
+	entries, err := d.Search(store.Query{Ruleset: "royale"})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected the stale entry to be gone, got %v, %v", entries, err)
+	}
+}
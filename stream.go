@@ -0,0 +1,140 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"time"
+)
+
+// Returned by decodeFrames when its callback asks to stop
+var errStopped = errors.New("stopped")
+
+// decodeGameStream parses game json from r one frame at a time, so the json
+// is never held in memory as a whole. Frames are passed to onFrame when it
+// isn't nil, along with the game's settings so far, otherwise they're added
+// to the game's Frames. Keys are matched without regard to case, the same as
+// json.Unmarshal.
+func decodeGameStream(r io.Reader, onFrame func(settings *ViewGameSettings, frame *ViewFrame) bool) (*ViewGame, error) {
+	dec := json.NewDecoder(r)
+	err := expectDelim(dec, '{')
+	if err != nil {
+		return nil, err
+	}
+	var game ViewGame
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected object key, found %v", tok)
+		}
+		switch {
+		case strings.EqualFold(key, "Game"):
+			err = dec.Decode(&game.Game)
+		case strings.EqualFold(key, "FirstFrame"):
+			err = dec.Decode(&game.FirstFrame)
+		case strings.EqualFold(key, "LastTurn"):
+			err = dec.Decode(&game.LastTurn)
+		case strings.EqualFold(key, "Events"):
+			err = dec.Decode(&game.Events)
+		case strings.EqualFold(key, "Frames"):
+			game.Frames = nil
+			err = decodeFrames(dec, func(frame *ViewFrame) bool {
+				if onFrame != nil {
+					return onFrame(&game.Game, frame)
+				}
+				game.Frames = append(game.Frames, *frame)
+				return true
+			})
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err == errStopped {
+			return &game, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", key, err)
+		}
+	}
+	err = expectDelim(dec, '}')
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after game")
+	}
+	return &game, nil
+}
+
+// StreamFrames yields the frames of an archive as they're uncompressed and
+// parsed, without keeping earlier frames in memory. Chunked archives are
+// read a chunk at a time. A failure is yielded once as a nil frame with the
+// error, after which the sequence ends.
+func StreamFrames(data []byte) iter.Seq2[*ViewFrame, error] {
+	return func(yield func(*ViewFrame, error) bool) {
+		r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			yield(nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err))
+			return
+		}
+		if isChunkedZip(r) {
+			streamChunked(r, yield)
+			return
+		}
+		rc, err := openGameFile(r)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer rc.Close()
+		// frames are parsed as they're read, so a frame's time is the time
+		// since the one before it was handed over
+		start := time.Now()
+		_, err = decodeGameStream(rc, func(_ *ViewGameSettings, frame *ViewFrame) bool {
+			internFrame(frame)
+			reportFrame(FrameStageStream, "", start, 0, frame)
+			if !yield(frame, nil) {
+				return false
+			}
+			start = time.Now()
+			return true
+		})
+		if err != nil && err != errStopped {
+			yield(nil, fmt.Errorf("%w: error unmarshalling compressed game: %w", ErrCorruptArchive, err))
+		}
+	}
+}
+
+// streamChunked is StreamFrames for the chunked layout
+func streamChunked(r *zip.Reader, yield func(*ViewFrame, error) bool) {
+	manifest, files, err := chunkManifest(r)
+	if err != nil {
+		yield(nil, err)
+		return
+	}
+	for i := range manifest.Chunks {
+		start := time.Now()
+		frames, err := readChunk(files, &manifest.Chunks[i])
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for j := range frames {
+			internFrame(&frames[j])
+			reportFrame(FrameStageStream, "", start, 0, &frames[j])
+			if !yield(&frames[j], nil) {
+				return
+			}
+			start = time.Now()
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FieldError lists the JSON fields that didn't match the Go structs when
+// decoding strictly. Paths are dotted, with slice indexes in brackets.
+type FieldError struct {
+	Unknown []string
+	Missing []string
+}
+
+func (e *FieldError) Error() string {
+	var parts []string
+	if len(e.Unknown) > 0 {
+		parts = append(parts, "unknown fields "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing fields "+strings.Join(e.Missing, ", "))
+	}
+	return "strict decode: " + strings.Join(parts, "; ")
+}
+
+var (
+	unmarshalerType      = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	viewRulesetType      = reflect.TypeOf(ViewRuleset{})
+	plainViewRulesetType = reflect.TypeOf(plainViewRuleset{})
+)
+
+// UnmarshalStrict is like json.Unmarshal but returns a *FieldError if data
+// has fields that v doesn't, or is missing fields of v that aren't
+// omitempty. Field names must match the json tags exactly.
+func UnmarshalStrict(data []byte, v interface{}) error {
+	var fe FieldError
+	err := checkFields(data, reflect.TypeOf(v), "", &fe, nil)
+	if err != nil {
+		return err
+	}
+	if len(fe.Unknown) > 0 || len(fe.Missing) > 0 {
+		return &fe
+	}
+	return json.Unmarshal(data, v)
+}
+
+// DecodeStrict is like Decode but rejects games with unknown or missing
+// fields, see UnmarshalStrict.
+func DecodeStrict(data []byte) (*ViewGame, error) {
+	start := time.Now()
+	game, err := decodeStrict(data)
+	reportDecode(DecodeFormatArchive, start, len(data), game, err)
+	return game, err
+}
+
+func decodeStrict(data []byte) (*ViewGame, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	if isChunkedZip(r) {
+		fe := &FieldError{}
+		err = checkChunked(r, fe, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling compressed game: %w", err)
+		}
+		if len(fe.Unknown) > 0 || len(fe.Missing) > 0 {
+			return nil, fmt.Errorf("error unmarshalling compressed game: %w", fe)
+		}
+		return decodeChunkedZip(r, ParallelOptions{})
+	}
+	var buf bytes.Buffer
+	err = readArchiveInto(r, &buf)
+	if err != nil {
+		return nil, err
+	}
+	var game ViewGame
+	err = UnmarshalStrict(buf.Bytes(), &game)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling compressed game: %w", err)
+	}
+	return &game, nil
+}
+
+// checkChunked is checkFields for the game in a chunked archive, with the
+// paths the same fields would have in the json of the whole game. The
+// manifest's own keys aren't part of the game and aren't checked.
+func checkChunked(r *zip.Reader, fe *FieldError, unknown map[string]map[string]json.RawMessage) error {
+	files := zipFiles(r)
+	f, ok := files[chunkManifestFile]
+	if !ok {
+		return fmt.Errorf("%w: no %s found in archive", ErrCorruptArchive, chunkManifestFile)
+	}
+	contents, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+	manifest, err := parseChunkManifest(contents, files)
+	if err != nil {
+		return err
+	}
+	var raw struct {
+		Game   json.RawMessage `json:"game"`
+		Events json.RawMessage `json:"events"`
+	}
+	err = json.Unmarshal(contents, &raw)
+	if err != nil {
+		return fmt.Errorf("error decoding %s: %w", chunkManifestFile, err)
+	}
+	err = checkFields(raw.Game, reflect.TypeOf(ViewGameSettings{}), "Game", fe, unknown)
+	if err != nil {
+		return err
+	}
+	if raw.Events != nil {
+		err = checkFields(raw.Events, reflect.TypeOf([]ViewEvent{}), "Events", fe, unknown)
+		if err != nil {
+			return err
+		}
+	}
+	first := 0
+	for i := range manifest.Chunks {
+		entry := &manifest.Chunks[i]
+		contents, err := readZipFile(files[entry.File])
+		if err != nil {
+			return err
+		}
+		var frames []json.RawMessage
+		err = json.Unmarshal(contents, &frames)
+		if err != nil {
+			return fmt.Errorf("error decoding %s: %w", entry.File, err)
+		}
+		for j, frame := range frames {
+			err = checkFields(frame, reflect.TypeOf(ViewFrame{}), fmt.Sprintf("Frames[%d]", first+j), fe, unknown)
+			if err != nil {
+				return err
+			}
+		}
+		first += len(frames)
+	}
+	return nil
+}
+
+// checkFields notes unknown and missing fields of data in fe. When unknown
+// isn't nil the raw json of unknown fields is kept there too, by the path
+// of the struct they were found in.
+func checkFields(data []byte, t reflect.Type, path string, fe *FieldError, unknown map[string]map[string]json.RawMessage) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	if t == viewRulesetType {
+		// the ruleset decodes itself to keep other keys in Settings. They're
+		// still unknown to a strict decode, but aren't collected as Settings
+		// already keeps them.
+		return checkFields(data, plainViewRulesetType, path, fe, nil)
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		err := json.Unmarshal(data, &obj)
+		if err != nil {
+			return fmt.Errorf("error decoding %s: %w", fieldPath(path, t.Name()), err)
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" && opts == "" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			raw, ok := obj[name]
+			if !ok {
+				if !hasOption(opts, "omitempty") {
+					fe.Missing = append(fe.Missing, fieldPath(path, name))
+				}
+				continue
+			}
+			delete(obj, name)
+			if hasOption(opts, "string") {
+				continue
+			}
+			err = checkFields(raw, f.Type, fieldPath(path, name), fe, unknown)
+			if err != nil {
+				return err
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, fieldPath(path, name))
+		}
+		sort.Strings(names)
+		fe.Unknown = append(fe.Unknown, names...)
+		if unknown != nil && len(obj) > 0 {
+			unknown[path] = obj
+		}
+	case reflect.Slice, reflect.Array:
+		if !hasFields(t.Elem()) {
+			return nil
+		}
+		var items []json.RawMessage
+		err := json.Unmarshal(data, &items)
+		if err != nil {
+			return fmt.Errorf("error decoding %s: %w", path, err)
+		}
+		for i, item := range items {
+			err = checkFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), fe, unknown)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !hasFields(t.Elem()) {
+			return nil
+		}
+		var items map[string]json.RawMessage
+		err := json.Unmarshal(data, &items)
+		if err != nil {
+			return fmt.Errorf("error decoding %s: %w", path, err)
+		}
+		for k, item := range items {
+			err = checkFields(item, t.Elem(), fmt.Sprintf("%s[%s]", path, k), fe, unknown)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasFields is true for types that can hold struct fields to check
+func hasFields(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasFields(t.Elem())
+	}
+	return false
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
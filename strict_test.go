@@ -0,0 +1,40 @@
+package battlesnakegameformat_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func TestUnmarshalStrict(t *testing.T) {
+	tests := []struct {
+		data    string
+		unknown []string
+		missing []string
+	}{
+		{`{"X":1,"Y":2}`, nil, nil},
+		{`{"X":1,"Y":2,"Z":3}`, []string{"Z"}, nil},
+		{`{"X":1}`, nil, []string{"Y"}},
+		{`{"x":1,"Y":2}`, []string{"x"}, []string{"X"}},
+	}
+	for _, test := range tests {
+		var c bsgf.ViewCoord
+		err := bsgf.UnmarshalStrict([]byte(test.data), &c)
+		if test.unknown == nil && test.missing == nil {
+			if err != nil || c != (bsgf.ViewCoord{X: 1, Y: 2}) {
+				t.Errorf("%s: got %v, %v", test.data, c, err)
+			}
+			continue
+		}
+		var fe *bsgf.FieldError
+		if !errors.As(err, &fe) {
+			t.Errorf("%s: expected a FieldError, got %v", test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(fe.Unknown, test.unknown) || !reflect.DeepEqual(fe.Missing, test.missing) {
+			t.Errorf("%s: expected unknown %v and missing %v, got %v and %v", test.data, test.unknown, test.missing, fe.Unknown, fe.Missing)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AddGroups seeds every entry into n round robin groups, named A, B and so
+// on, snaking through the seeds so each group gets a similar spread, and
+// adds a match for every pair within each group.
+func (t *Tournament) AddGroups(n, bestOf int) error {
+	if n < 1 || n > 26 || len(t.Entries) < 2*n {
+		return fmt.Errorf("can't split %d entries into %d groups", len(t.Entries), n)
+	}
+	groups := make([]Group, n)
+	for i := range groups {
+		groups[i].Name = string(rune('A' + i))
+	}
+	for i, e := range t.Entries {
+		g := i % n
+		if (i/n)%2 == 1 {
+			g = n - 1 - g
+		}
+		groups[g].Entries = append(groups[g].Entries, e.ID)
+	}
+	for _, g := range groups {
+		for r, pairs := range roundRobin(g.Entries) {
+			for k, pair := range pairs {
+				t.Matches = append(t.Matches, &Match{
+					ID:      fmt.Sprintf("%s-r%d-m%d", g.Name, r+1, k+1),
+					Stage:   "group " + g.Name,
+					Round:   r + 1,
+					Entries: []string{pair[0], pair[1]},
+					BestOf:  bestOf,
+					GameIDs: []string{},
+				})
+			}
+		}
+	}
+	t.Groups = append(t.Groups, groups...)
+	return nil
+}
+
+// roundRobin pairs every entry with every other using the circle method,
+// one round at a time, so nobody plays twice in a round
+func roundRobin(ids []string) [][][2]string {
+	players := append([]string(nil), ids...)
+	if len(players)%2 == 1 {
+		players = append(players, "")
+	}
+	n := len(players)
+	var rounds [][][2]string
+	for r := 0; r < n-1; r++ {
+		var pairs [][2]string
+		for i := 0; i < n/2; i++ {
+			a, b := players[i], players[n-1-i]
+			if a != "" && b != "" {
+				pairs = append(pairs, [2]string{a, b})
+			}
+		}
+		rounds = append(rounds, pairs)
+		// keep the first player in place and rotate the rest
+		last := players[n-1]
+		copy(players[2:], players[1:n-1])
+		players[1] = last
+	}
+	return rounds
+}
+
+// AddBracket adds a single elimination bracket for ids, given best seed
+// first. Seeds are placed so the top two can only meet in the final, and
+// when the field isn't a power of two the top seeds get byes.
+func (t *Tournament) AddBracket(ids []string, bestOf int) error {
+	if len(ids) < 2 {
+		return fmt.Errorf("a bracket needs at least 2 entries, got %d", len(ids))
+	}
+	for _, id := range ids {
+		if _, err := t.Entry(id); err != nil {
+			return err
+		}
+	}
+	size, rounds := 1, 0
+	for size < len(ids) {
+		size *= 2
+		rounds++
+	}
+	matchId := func(round, k int) string {
+		return fmt.Sprintf("bracket-r%d-m%d", round, k+1)
+	}
+	var matches []*Match
+	for r := 1; r <= rounds; r++ {
+		count := size >> r
+		for k := 0; k < count; k++ {
+			m := &Match{
+				ID:      matchId(r, k),
+				Stage:   "bracket",
+				Round:   r,
+				Entries: []string{"", ""},
+				BestOf:  bestOf,
+				GameIDs: []string{},
+			}
+			if r < rounds {
+				m.Next = matchId(r+1, k/2)
+				m.NextSlot = k % 2
+			}
+			matches = append(matches, m)
+		}
+	}
+	t.Matches = append(t.Matches, matches...)
+	order := seedOrder(size)
+	for k := 0; k < size/2; k++ {
+		m := matches[k]
+		for slot, seed := range order[2*k : 2*k+2] {
+			if seed <= len(ids) {
+				m.Entries[slot] = ids[seed-1]
+			}
+		}
+		if m.Entries[1] == "" {
+			m.Bye = true
+			t.finish(m, m.Entries[0])
+		} else if m.Entries[0] == "" {
+			m.Bye = true
+			t.finish(m, m.Entries[1])
+		}
+	}
+	return nil
+}
+
+// seedOrder lists seeds 1 to size in bracket order, so pairs of
+// neighbours are the first round matches
+func seedOrder(size int) []int {
+	order := []int{1}
+	for n := 2; n <= size; n *= 2 {
+		next := make([]int, 0, n)
+		for _, s := range order {
+			next = append(next, s, n+1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+// Schedule gives every match that isn't done yet a start time. Up to
+// parallel matches share a slot of the given length. Groups play their
+// rounds side by side, before the bracket, and each round starts in a new
+// slot after the round before it.
+func (t *Tournament) Schedule(start time.Time, slot time.Duration, parallel int) {
+	if parallel < 1 {
+		parallel = 1
+	}
+	var pending []*Match
+	for _, m := range t.Matches {
+		if !m.Done() {
+			pending = append(pending, m)
+		}
+	}
+	key := func(m *Match) [2]int {
+		if strings.HasPrefix(m.Stage, "group ") {
+			return [2]int{0, m.Round}
+		}
+		return [2]int{1, m.Round}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := key(pending[i]), key(pending[j])
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	})
+	at := start
+	used := 0
+	for i, m := range pending {
+		if i > 0 && (used == parallel || key(m) != key(pending[i-1])) {
+			at = at.Add(slot)
+			used = 0
+		}
+		scheduled := at
+		m.Time = &scheduled
+		used++
+	}
+}
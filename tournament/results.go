@@ -0,0 +1,166 @@
+package tournament
+
+import (
+	"fmt"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Points scores a place in a game with the given number of snakes
+type Points func(place, snakes int) int
+
+// DefaultPoints gives one point for every snake finished ahead of, so last
+// place scores 0 and the winner of a duel scores 1
+func DefaultPoints(place, snakes int) int {
+	return snakes - place
+}
+
+// Placement is how one participant finished one game
+type Placement struct {
+	// Participant is the entry ID, or the snake's name outside a
+	// tournament
+	Participant string          `json:"participant"`
+	SnakeID     string          `json:"snakeId"`
+	Name        string          `json:"name"`
+	Place       int             `json:"place"`
+	Points      int             `json:"points"`
+	DeathTurn   int32           `json:"deathTurn,omitempty"`
+	Cause       bsgf.DeathCause `json:"cause,omitempty"`
+}
+
+// MatchResult is the result of one game, identified by participant
+// rather than by the snake IDs, which change from game to game
+type MatchResult struct {
+	GameID       string      `json:"gameId"`
+	Participants []string    `json:"participants"`
+	Placements   []Placement `json:"placements"`
+	// Winner is the participant that finished alone in first place, ""
+	// for draws
+	Winner string `json:"winner,omitempty"`
+}
+
+// ResultOf derives the result of game. participant names the participant
+// each snake plays for, and can be nil to use snake names. points can be
+// nil for DefaultPoints.
+func ResultOf(game *bsgf.ViewGame, participant func(s *bsgf.ViewSnake) (string, bool), points Points) (*MatchResult, error) {
+	last, err := game.FinalFrame()
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		participant = func(s *bsgf.ViewSnake) (string, bool) { return s.Name, true }
+	}
+	if points == nil {
+		points = DefaultPoints
+	}
+	ids := make(map[string]string, len(last.Snakes))
+	for i := range last.Snakes {
+		s := &last.Snakes[i]
+		id, ok := participant(s)
+		if !ok {
+			return nil, fmt.Errorf("%w: no participant for snake %q in game %s", ErrUnknownEntry, s.Name, game.Game.ID)
+		}
+		ids[s.ID] = id
+	}
+	r := &MatchResult{GameID: game.Game.ID, Winner: ids[game.Winner()]}
+	for _, p := range game.Placements() {
+		id := ids[p.SnakeID]
+		r.Participants = append(r.Participants, id)
+		r.Placements = append(r.Placements, Placement{
+			Participant: id,
+			SnakeID:     p.SnakeID,
+			Name:        p.Name,
+			Place:       p.Place,
+			Points:      points(p.Place, len(last.Snakes)),
+			DeathTurn:   p.DeathTurn,
+			Cause:       p.Cause,
+		})
+	}
+	return r, nil
+}
+
+// Placement finds how participant finished, false if they didn't play
+func (r *MatchResult) Placement(participant string) (Placement, bool) {
+	for _, p := range r.Placements {
+		if p.Participant == participant {
+			return p, true
+		}
+	}
+	return Placement{}, false
+}
+
+// SeriesResult aggregates the games of a best-of-N series
+type SeriesResult struct {
+	BestOf       int            `json:"bestOf"`
+	GameIDs      []string       `json:"gameIds"`
+	Participants []string       `json:"participants"`
+	Wins         map[string]int `json:"wins"`
+	Points       map[string]int `json:"points"`
+	// Results are the games that counted towards the series
+	Results []MatchResult `json:"results"`
+	// Winner is the first participant to win more than half of BestOf
+	// games. Once BestOf games are played without that, it's whoever won
+	// the most, then scored the most points. A series still tied after
+	// that goes on until one game breaks the tie.
+	Winner string `json:"winner,omitempty"`
+	Done   bool   `json:"done"`
+}
+
+// Series aggregates game results, in the order they were played, into a
+// best-of-N series. Games after the series was decided are left out.
+func Series(bestOf int, results []MatchResult) *SeriesResult {
+	if bestOf < 1 {
+		bestOf = 1
+	}
+	s := &SeriesResult{BestOf: bestOf, GameIDs: []string{}, Participants: []string{}, Wins: make(map[string]int), Points: make(map[string]int), Results: []MatchResult{}}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if s.Done {
+			break
+		}
+		s.GameIDs = append(s.GameIDs, r.GameID)
+		s.Results = append(s.Results, r)
+		for _, p := range r.Placements {
+			if !seen[p.Participant] {
+				seen[p.Participant] = true
+				s.Participants = append(s.Participants, p.Participant)
+			}
+			s.Points[p.Participant] += p.Points
+		}
+		if r.Winner != "" {
+			s.Wins[r.Winner]++
+			if s.Wins[r.Winner] > bestOf/2 {
+				s.Winner = r.Winner
+				s.Done = true
+			}
+		}
+		if !s.Done && len(s.GameIDs) >= bestOf {
+			s.Winner = s.leader()
+			s.Done = s.Winner != ""
+		}
+	}
+	return s
+}
+
+// leader is the participant with the most wins, then points, or "" for a
+// tie
+func (s *SeriesResult) leader() string {
+	best, tied := "", false
+	for _, id := range s.Participants {
+		if best == "" {
+			best = id
+			continue
+		}
+		a, b := [2]int{s.Wins[id], s.Points[id]}, [2]int{s.Wins[best], s.Points[best]}
+		switch {
+		case a[0] > b[0] || (a[0] == b[0] && a[1] > b[1]):
+			best, tied = id, false
+		case a == b:
+			tied = true
+		}
+	}
+	if tied {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,203 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Standing is one participant's line in a table
+type Standing struct {
+	Participant string `json:"participant"`
+	// Rank starts at 1, shared by participants that can't be separated
+	Rank    int `json:"rank"`
+	Matches int `json:"matches"`
+	// MatchWins counts series won
+	MatchWins int `json:"matchWins"`
+	Games     int `json:"games"`
+	GameWins  int `json:"gameWins"`
+	Points    int `json:"points"`
+	// HeadToHead is the number of participants tied on points this one
+	// finished ahead of, summed over the games they played together
+	HeadToHead int     `json:"headToHead"`
+	AvgPlace   float64 `json:"avgPlace"`
+}
+
+// Standings ranks participants by the points they scored in every game of
+// series. Ties on points are broken by head to head results between the
+// tied participants, then by average place. participants lists anyone to
+// include who hasn't played yet.
+func Standings(series []*SeriesResult, participants ...string) []Standing {
+	index := make(map[string]int)
+	var table []Standing
+	add := func(id string) *Standing {
+		i, ok := index[id]
+		if !ok {
+			i = len(table)
+			index[id] = i
+			table = append(table, Standing{Participant: id})
+		}
+		return &table[i]
+	}
+	for _, id := range participants {
+		add(id)
+	}
+	places := make(map[string]int)
+	for _, s := range series {
+		for _, id := range s.Participants {
+			add(id).Matches++
+		}
+		if s.Done && s.Winner != "" {
+			add(s.Winner).MatchWins++
+		}
+		for _, r := range s.Results {
+			for _, p := range r.Placements {
+				st := add(p.Participant)
+				st.Games++
+				st.Points += p.Points
+				places[p.Participant] += p.Place
+			}
+			if r.Winner != "" {
+				add(r.Winner).GameWins++
+			}
+		}
+	}
+	for i := range table {
+		if table[i].Games > 0 {
+			table[i].AvgPlace = float64(places[table[i].Participant]) / float64(table[i].Games)
+		}
+	}
+
+	// head to head within each group tied on points
+	tied := make(map[int][]string)
+	for _, st := range table {
+		tied[st.Points] = append(tied[st.Points], st.Participant)
+	}
+	for _, ids := range tied {
+		if len(ids) < 2 {
+			continue
+		}
+		group := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			group[id] = true
+		}
+		for _, s := range series {
+			for _, r := range s.Results {
+				for _, a := range r.Placements {
+					if !group[a.Participant] {
+						continue
+					}
+					for _, b := range r.Placements {
+						if group[b.Participant] && a.Place < b.Place {
+							table[index[a.Participant]].HeadToHead++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		if c := compareStandings(&table[i], &table[j]); c != 0 {
+			return c < 0
+		}
+		return table[i].Participant < table[j].Participant
+	})
+	for i := range table {
+		if i > 0 && compareStandings(&table[i-1], &table[i]) == 0 {
+			table[i].Rank = table[i-1].Rank
+		} else {
+			table[i].Rank = i + 1
+		}
+	}
+	return table
+}
+
+// compareStandings is negative when a ranks above b, 0 when they can't be
+// separated
+func compareStandings(a, b *Standing) int {
+	switch {
+	case a.Points != b.Points:
+		return b.Points - a.Points
+	case a.HeadToHead != b.HeadToHead:
+		return b.HeadToHead - a.HeadToHead
+	case (a.Games == 0) != (b.Games == 0):
+		// nobody who has played ranks below someone who hasn't
+		if a.Games == 0 {
+			return 1
+		}
+		return -1
+	case a.AvgPlace < b.AvgPlace:
+		return -1
+	case a.AvgPlace > b.AvgPlace:
+		return 1
+	}
+	return 0
+}
+
+// Seeds lists the participants of a table from the top, for AddBracket or
+// the next round's groups
+func Seeds(table []Standing) []string {
+	ids := make([]string, len(table))
+	for i, st := range table {
+		ids[i] = st.Participant
+	}
+	return ids
+}
+
+// Standings ranks the entries of one stage, like "group A" or "bracket",
+// or of every stage when stage is ""
+func (t *Tournament) Standings(stage string) []Standing {
+	var series []*SeriesResult
+	for _, m := range t.Matches {
+		if stage == "" || m.Stage == stage {
+			series = append(series, m.Series())
+		}
+	}
+	var participants []string
+	if strings.HasPrefix(stage, "group ") {
+		for _, g := range t.Groups {
+			if "group "+g.Name == stage {
+				participants = g.Entries
+			}
+		}
+	} else if stage == "" {
+		for _, e := range t.Entries {
+			participants = append(participants, e.ID)
+		}
+	}
+	return Standings(series, participants...)
+}
+
+// Advance seeds the top perGroup entries of every group into a new
+// bracket: group winners first, then runners up and so on, ordered within
+// each place by the same tie-breaks across groups. Every group match has
+// to be done.
+func (t *Tournament) Advance(perGroup, bestOf int) error {
+	if len(t.Groups) == 0 {
+		return fmt.Errorf("tournament has no groups")
+	}
+	for _, m := range t.Matches {
+		if strings.HasPrefix(m.Stage, "group ") && !m.Done() {
+			return fmt.Errorf("%w: group match %s isn't done", ErrMatchNotReady, m.ID)
+		}
+	}
+	var ids []string
+	for place := 0; place < perGroup; place++ {
+		var tier []Standing
+		for _, g := range t.Groups {
+			table := t.Standings("group " + g.Name)
+			if place < len(table) {
+				tier = append(tier, table[place])
+			}
+		}
+		sort.SliceStable(tier, func(i, j int) bool {
+			// head to head doesn't apply across groups
+			a, b := tier[i], tier[j]
+			a.HeadToHead, b.HeadToHead = 0, 0
+			return compareStandings(&a, &b) < 0
+		})
+		ids = append(ids, Seeds(tier)...)
+	}
+	return t.AddBracket(ids, bestOf)
+}
@@ -0,0 +1,246 @@
+package tournament
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+// Kinds of turn worth showing an entrant
+const (
+	// EvidenceNoResponse is a turn where the snake's latency was 0 or
+	// missing, which the engine reports when the snake didn't answer
+	EvidenceNoResponse = "no-response"
+	// EvidenceTimeout is a turn answered at or after the game's timeout
+	EvidenceTimeout = "timeout"
+	// EvidenceRisk is a turn answered close to the timeout
+	EvidenceRisk = "timeout-risk"
+)
+
+// Evidence is one turn backing a flag in a telemetry report
+type Evidence struct {
+	GameID    string  `json:"gameId"`
+	Turn      int32   `json:"turn"`
+	Kind      string  `json:"kind"`
+	LatencyMs float64 `json:"latencyMs"`
+	TimeoutMs int32   `json:"timeoutMs"`
+}
+
+// EntryTelemetry is how one entry's server behaved over every game it
+// played
+type EntryTelemetry struct {
+	Participant string  `json:"participant"`
+	Name        string  `json:"name"`
+	Games       int     `json:"games"`
+	Turns       int     `json:"turns"`
+	AvgLatency  float64 `json:"avgLatencyMs"`
+	P95Latency  float64 `json:"p95LatencyMs"`
+	MaxLatency  float64 `json:"maxLatencyMs"`
+	// RiskTurns, Timeouts and NoResponses count turns of each evidence
+	// kind
+	RiskTurns   int `json:"riskTurns"`
+	Timeouts    int `json:"timeouts"`
+	NoResponses int `json:"noResponses"`
+	// LongestOutage is the most turns in a row without a response
+	LongestOutage int `json:"longestOutage"`
+	// Deaths counts how the entry was eliminated
+	Deaths map[bsgf.DeathCause]int `json:"deaths,omitempty"`
+	// Slow and Flaky are set when the thresholds in TelemetryOptions are
+	// crossed
+	Slow  bool `json:"slow,omitempty"`
+	Flaky bool `json:"flaky,omitempty"`
+	// Evidence lists the worst turns, up to TelemetryOptions.Evidence
+	Evidence []Evidence `json:"evidence,omitempty"`
+}
+
+type TelemetryOptions struct {
+	// Risk is the fraction of the timeout that counts as a timeout risk. 0
+	// means 0.8.
+	Risk float64
+	// SlowP95 flags entries whose 95th percentile latency is at least this
+	// fraction of the timeout. 0 means 0.8.
+	SlowP95 float64
+	// FlakyRate flags entries that timed out or didn't respond on at least
+	// this fraction of their turns. 0 means 0.01.
+	FlakyRate float64
+	// Evidence is how many turns to keep per entry. 0 means 10.
+	Evidence int
+}
+
+func (opts *TelemetryOptions) defaults() {
+	if opts.Risk <= 0 {
+		opts.Risk = 0.8
+	}
+	if opts.SlowP95 <= 0 {
+		opts.SlowP95 = 0.8
+	}
+	if opts.FlakyRate <= 0 {
+		opts.FlakyRate = 0.01
+	}
+	if opts.Evidence <= 0 {
+		opts.Evidence = 10
+	}
+}
+
+type telemetry struct {
+	EntryTelemetry
+	latencies []float64
+	timeouts  []float64
+	outage    int
+}
+
+// Telemetry reports on every entry over every game recorded in the
+// tournament, loading the games from s. Entries are sorted with flagged
+// ones first, then by the share of turns they had trouble with.
+func (t *Tournament) Telemetry(s store.Store, opts TelemetryOptions) ([]EntryTelemetry, error) {
+	opts.defaults()
+	byEntry := make(map[string]*telemetry)
+	for _, e := range t.Entries {
+		byEntry[e.ID] = &telemetry{EntryTelemetry: EntryTelemetry{Participant: e.ID, Name: e.Name}}
+	}
+	for _, m := range t.Matches {
+		games, err := t.Games(s, m.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, game := range games {
+			if len(game.Frames) == 0 {
+				continue
+			}
+			for i := range game.Frames[0].Snakes {
+				s := &game.Frames[0].Snakes[i]
+				id, ok := t.EntryOf(m, s)
+				if !ok || byEntry[id] == nil {
+					continue
+				}
+				byEntry[id].add(game, s.ID, opts)
+			}
+		}
+	}
+	report := make([]EntryTelemetry, 0, len(byEntry))
+	for _, e := range t.Entries {
+		report = append(report, byEntry[e.ID].finish(opts))
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		a, b := &report[i], &report[j]
+		if fa, fb := a.Slow || a.Flaky, b.Slow || b.Flaky; fa != fb {
+			return fa
+		}
+		return troubleRate(a) > troubleRate(b)
+	})
+	return report, nil
+}
+
+func troubleRate(e *EntryTelemetry) float64 {
+	if e.Turns == 0 {
+		return 0
+	}
+	return float64(e.RiskTurns+e.Timeouts+e.NoResponses) / float64(e.Turns)
+}
+
+// add counts the turns snakeId played in game
+func (tm *telemetry) add(game *bsgf.ViewGame, snakeId string, opts TelemetryOptions) {
+	tm.Games++
+	timeout := float64(game.Game.Timeout)
+	tm.outage = 0
+	for i := range game.Frames {
+		frame := &game.Frames[i]
+		if frame.Turn == 0 {
+			// nobody has moved yet
+			continue
+		}
+		var s *bsgf.ViewSnake
+		for k := range frame.Snakes {
+			if frame.Snakes[k].ID == snakeId {
+				s = &frame.Snakes[k]
+			}
+		}
+		// the frame a snake dies in still has the latency of its last move
+		if s == nil || (!s.Alive() && s.Death.Turn != frame.Turn) {
+			continue
+		}
+		if !s.Alive() {
+			if tm.Deaths == nil {
+				tm.Deaths = make(map[bsgf.DeathCause]int)
+			}
+			tm.Deaths[s.Death.Cause]++
+		}
+		tm.Turns++
+		ms, err := strconv.ParseFloat(s.Latency, 64)
+		kind := ""
+		switch {
+		case err != nil || ms <= 0:
+			kind = EvidenceNoResponse
+			tm.NoResponses++
+			tm.outage++
+			if tm.outage > tm.LongestOutage {
+				tm.LongestOutage = tm.outage
+			}
+		case timeout > 0 && ms >= timeout:
+			kind = EvidenceTimeout
+			tm.Timeouts++
+		case timeout > 0 && ms >= opts.Risk*timeout:
+			kind = EvidenceRisk
+			tm.RiskTurns++
+		}
+		if kind != EvidenceNoResponse {
+			tm.outage = 0
+			tm.latencies = append(tm.latencies, ms)
+			if timeout > 0 {
+				tm.timeouts = append(tm.timeouts, ms/timeout)
+			}
+		}
+		if kind != "" {
+			tm.Evidence = append(tm.Evidence, Evidence{GameID: game.Game.ID, Turn: frame.Turn, Kind: kind, LatencyMs: ms, TimeoutMs: game.Game.Timeout})
+		}
+	}
+}
+
+// evidenceRank orders evidence from the most to the least serious
+var evidenceRank = map[string]int{EvidenceNoResponse: 0, EvidenceTimeout: 1, EvidenceRisk: 2}
+
+func (tm *telemetry) finish(opts TelemetryOptions) EntryTelemetry {
+	e := tm.EntryTelemetry
+	if n := len(tm.latencies); n > 0 {
+		sorted := append([]float64(nil), tm.latencies...)
+		sort.Float64s(sorted)
+		sum := 0.0
+		for _, ms := range sorted {
+			sum += ms
+		}
+		e.AvgLatency = sum / float64(n)
+		e.P95Latency = percentile(sorted, 0.95)
+		e.MaxLatency = sorted[n-1]
+	}
+	if len(tm.timeouts) > 0 {
+		sorted := append([]float64(nil), tm.timeouts...)
+		sort.Float64s(sorted)
+		e.Slow = percentile(sorted, 0.95) >= opts.SlowP95
+	}
+	if e.Turns > 0 {
+		e.Flaky = float64(e.Timeouts+e.NoResponses)/float64(e.Turns) >= opts.FlakyRate
+	}
+	sort.SliceStable(e.Evidence, func(i, j int) bool {
+		a, b := e.Evidence[i], e.Evidence[j]
+		if evidenceRank[a.Kind] != evidenceRank[b.Kind] {
+			return evidenceRank[a.Kind] < evidenceRank[b.Kind]
+		}
+		return a.LatencyMs > b.LatencyMs
+	})
+	if len(e.Evidence) > opts.Evidence {
+		e.Evidence = e.Evidence[:opts.Evidence]
+	}
+	return e
+}
+
+// percentile picks the nearest rank from sorted values
+func percentile(sorted []float64, p float64) float64 {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
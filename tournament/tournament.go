@@ -0,0 +1,274 @@
+// Package tournament organizes games into tournaments: entries are seeded
+// into round robin groups and single elimination brackets, matches are
+// scheduled and each played game is linked to its archive by ID. A
+// tournament is saved as one json file between rounds.
+package tournament
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/store"
+)
+
+var (
+	ErrUnknownMatch  = errors.New("unknown match")
+	ErrUnknownEntry  = errors.New("unknown entry")
+	ErrMatchNotReady = errors.New("match not ready")
+)
+
+// Entry is one snake taking part in the tournament
+type Entry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// URL of the snake's server. Snakes in games are matched to entries by
+	// URL when both have one, otherwise by name.
+	URL string `json:"url,omitempty"`
+	// Seed is 1 for the top seed
+	Seed int `json:"seed"`
+}
+
+// Group is a round robin group
+type Group struct {
+	Name    string   `json:"name"`
+	Entries []string `json:"entries"`
+}
+
+// Match is a best-of-N series of games between entries, see Series
+type Match struct {
+	ID    string `json:"id"`
+	Stage string `json:"stage"`
+	// Round starts at 1 in each stage
+	Round int `json:"round"`
+	// Entries are entry IDs, "" while waiting for the winner of an earlier
+	// match
+	Entries []string `json:"entries"`
+	BestOf  int      `json:"bestOf"`
+	GameIDs []string `json:"gameIds"`
+	// Results of the games played so far, by entry ID
+	Results []MatchResult  `json:"results,omitempty"`
+	Wins    map[string]int `json:"wins,omitempty"`
+	Winner  string         `json:"winner,omitempty"`
+	// Bye is set for bracket slots without an opponent, which are won
+	// without playing
+	Bye bool `json:"bye,omitempty"`
+	// Next is the match the winner goes on to, taking NextSlot in its
+	// Entries
+	Next     string `json:"next,omitempty"`
+	NextSlot int    `json:"nextSlot,omitempty"`
+	// Time is when the match is scheduled to start
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// Done is true once the match has a winner
+func (m *Match) Done() bool {
+	return m.Winner != ""
+}
+
+// Ready is true when every entry is known and the match isn't done
+func (m *Match) Ready() bool {
+	if m.Done() {
+		return false
+	}
+	for _, id := range m.Entries {
+		if id == "" {
+			return false
+		}
+	}
+	return true
+}
+
+type Tournament struct {
+	Name    string   `json:"name"`
+	Entries []Entry  `json:"entries"`
+	Groups  []Group  `json:"groups,omitempty"`
+	Matches []*Match `json:"matches"`
+}
+
+// New starts a tournament. Entries without a seed are seeded after the
+// seeded ones, in the order given.
+func New(name string, entries []Entry) (*Tournament, error) {
+	t := &Tournament{Name: name, Matches: []*Match{}}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.ID == "" {
+			return nil, errors.New("entry without an ID")
+		}
+		if seen[e.ID] {
+			return nil, fmt.Errorf("duplicate entry %q", e.ID)
+		}
+		seen[e.ID] = true
+		t.Entries = append(t.Entries, e)
+	}
+	sort.SliceStable(t.Entries, func(i, j int) bool {
+		a, b := t.Entries[i].Seed, t.Entries[j].Seed
+		return a != 0 && (b == 0 || a < b)
+	})
+	for i := range t.Entries {
+		t.Entries[i].Seed = i + 1
+	}
+	return t, nil
+}
+
+// Entry finds an entry by ID
+func (t *Tournament) Entry(id string) (*Entry, error) {
+	for i := range t.Entries {
+		if t.Entries[i].ID == id {
+			return &t.Entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownEntry, id)
+}
+
+// Match finds a match by ID
+func (t *Tournament) Match(id string) (*Match, error) {
+	for _, m := range t.Matches {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownMatch, id)
+}
+
+// Ready lists the matches that can be played now, in the order they were
+// added
+func (t *Tournament) Ready() []*Match {
+	var ready []*Match
+	for _, m := range t.Matches {
+		if m.Ready() {
+			ready = append(ready, m)
+		}
+	}
+	return ready
+}
+
+// EntryOf finds the entry of a snake in one of the match's games
+func (t *Tournament) EntryOf(m *Match, s *bsgf.ViewSnake) (string, bool) {
+	for _, id := range m.Entries {
+		e, err := t.Entry(id)
+		if err != nil {
+			continue
+		}
+		if e.URL != "" && s.URL != "" {
+			// locally recorded snakes have their build in the fragment
+			snakeURL, _, _ := strings.Cut(s.URL, "#")
+			if e.URL == s.URL || e.URL == snakeURL {
+				return id, true
+			}
+			continue
+		}
+		if e.Name == s.Name {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// Record links a played game to a match and adds its result. Once the
+// series is decided the winner goes on to the next match.
+func (t *Tournament) Record(matchId string, game *bsgf.ViewGame) error {
+	m, err := t.Match(matchId)
+	if err != nil {
+		return err
+	}
+	if !m.Ready() {
+		return fmt.Errorf("%w: %s", ErrMatchNotReady, matchId)
+	}
+	for _, id := range m.GameIDs {
+		if id == game.Game.ID {
+			return fmt.Errorf("game %s is already recorded for match %s", game.Game.ID, matchId)
+		}
+	}
+	r, err := ResultOf(game, func(s *bsgf.ViewSnake) (string, bool) { return t.EntryOf(m, s) }, nil)
+	if err != nil {
+		return fmt.Errorf("%w in match %s", err, matchId)
+	}
+	m.GameIDs = append(m.GameIDs, game.Game.ID)
+	m.Results = append(m.Results, *r)
+	s := m.Series()
+	m.Wins = s.Wins
+	if s.Done {
+		t.finish(m, s.Winner)
+	}
+	return nil
+}
+
+// Series aggregates the results recorded for m
+func (m *Match) Series() *SeriesResult {
+	return Series(m.BestOf, m.Results)
+}
+
+// finish sets the winner of m and moves them on to the next match
+func (t *Tournament) finish(m *Match, winner string) {
+	m.Winner = winner
+	if m.Next == "" {
+		return
+	}
+	next, err := t.Match(m.Next)
+	if err == nil {
+		next.Entries[m.NextSlot] = winner
+	}
+}
+
+// Games loads the games played in a match from s
+func (t *Tournament) Games(s store.Store, matchId string) ([]*bsgf.ViewGame, error) {
+	m, err := t.Match(matchId)
+	if err != nil {
+		return nil, err
+	}
+	games := make([]*bsgf.ViewGame, 0, len(m.GameIDs))
+	for _, id := range m.GameIDs {
+		game, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// Load reads a tournament saved with Save
+func Load(path string) (*Tournament, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t Tournament
+	err = json.Unmarshal(data, &t)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tournament: %w", err)
+	}
+	return &t, nil
+}
+
+// Save writes the tournament to path as json, replacing the file in one
+// step so an interrupted save doesn't lose the previous state
+func (t *Tournament) Save(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling tournament: %w", err)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(append(data, '\n'))
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,197 @@
+package tournament_test
+
+import (
+	"fmt"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/tournament"
+)
+
+func entries(n int) []tournament.Entry {
+	es := make([]tournament.Entry, n)
+	for i := range es {
+		es[i] = tournament.Entry{ID: fmt.Sprintf("e%d", i+1), Name: fmt.Sprintf("snake %d", i+1)}
+	}
+	return es
+}
+
+func ids(es []tournament.Entry) []string {
+	out := make([]string, len(es))
+	for i, e := range es {
+		out[i] = e.ID
+	}
+	return out
+}
+
+// gameBetween is a game that winner wins by loser running into a wall
+func gameBetween(id string, winner, loser *tournament.Entry) *bsgf.ViewGame {
+	snakes := func(turn int32) []bsgf.ViewSnake {
+		s := []bsgf.ViewSnake{
+			{ID: "w", Name: winner.Name, URL: winner.URL, Health: 100, Body: []bsgf.ViewCoord{{X: 1, Y: 1}}},
+			{ID: "l", Name: loser.Name, URL: loser.URL, Health: 100, Body: []bsgf.ViewCoord{{X: 0, Y: 0}}},
+		}
+		if turn > 0 {
+			s[1].Death = bsgf.ViewDeath{Cause: bsgf.CauseWallCollision, Turn: turn}
+		}
+		return s
+	}
+	frames := []bsgf.ViewFrame{{Turn: 0, Snakes: snakes(0)}, {Turn: 1, Snakes: snakes(1)}}
+	return &bsgf.ViewGame{
+		Game:       bsgf.ViewGameSettings{ID: id, Width: 3, Height: 3, Status: "complete"},
+		Frames:     frames,
+		FirstFrame: frames[0],
+		LastTurn:   1,
+	}
+}
+
+// playBracket has the better seed win every ready match until none are left
+func playBracket(t *testing.T, tm *tournament.Tournament) {
+	t.Helper()
+	for n := 0; ; n++ {
+		ready := tm.Ready()
+		if len(ready) == 0 {
+			return
+		}
+		m := ready[0]
+		a, _ := tm.Entry(m.Entries[0])
+		b, _ := tm.Entry(m.Entries[1])
+		if b.Seed < a.Seed {
+			a, b = b, a
+		}
+		err := tm.Record(m.ID, gameBetween(fmt.Sprintf("g%d", n), a, b))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBracket(t *testing.T) {
+	for n := 2; n <= 9; n++ {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			tm, err := tournament.New("test", entries(n))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tm.AddBracket(ids(tm.Entries), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			size := 1
+			for size < n {
+				size *= 2
+			}
+			var first []*tournament.Match
+			for _, m := range tm.Matches {
+				if m.Round == 1 {
+					first = append(first, m)
+				}
+			}
+			if len(first) != size/2 {
+				t.Fatalf("expected %d first round matches, got %d", size/2, len(first))
+			}
+			// the top seeds get the byes, one per missing entry
+			byes := make(map[string]bool)
+			for _, m := range first {
+				if m.Bye {
+					byes[m.Winner] = true
+				}
+			}
+			if len(byes) != size-n {
+				t.Errorf("expected %d byes, got %v", size-n, byes)
+			}
+			for i := 1; i <= size-n; i++ {
+				if !byes[fmt.Sprintf("e%d", i)] {
+					t.Errorf("seed %d has no bye, byes are %v", i, byes)
+				}
+			}
+			// the top two start in different halves
+			half := func(id string) int {
+				for k, m := range first {
+					if m.Entries[0] == id || m.Entries[1] == id {
+						return 2 * k / len(first)
+					}
+				}
+				return -1
+			}
+			if n > 2 && half("e1") == half("e2") {
+				t.Error("seeds 1 and 2 are in the same half")
+			}
+			playBracket(t, tm)
+			final := tm.Matches[len(tm.Matches)-1]
+			if final.Winner != "e1" || (final.Entries[0] != "e2" && final.Entries[1] != "e2") {
+				t.Errorf("expected seed 1 to beat seed 2 in the final, got %+v", final)
+			}
+		})
+	}
+}
+
+func TestGroupsAdvance(t *testing.T) {
+	tm, err := tournament.New("test", entries(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tm.AddGroups(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// seeds snake through the groups
+	want := [][]string{{"e1", "e4", "e5", "e8"}, {"e2", "e3", "e6", "e7"}}
+	for i, g := range tm.Groups {
+		if fmt.Sprint(g.Entries) != fmt.Sprint(want[i]) {
+			t.Errorf("group %s is %v, expected %v", g.Name, g.Entries, want[i])
+		}
+	}
+	if len(tm.Matches) != 12 {
+		t.Fatalf("expected 6 matches per group, got %d", len(tm.Matches))
+	}
+	err = tm.Advance(2, 1)
+	if err == nil {
+		t.Fatal("expected an error advancing before the groups are done")
+	}
+	playBracket(t, tm)
+	for _, g := range tm.Groups {
+		table := tm.Standings("group " + g.Name)
+		if table[0].Participant != g.Entries[0] || table[0].MatchWins != 3 {
+			t.Errorf("group %s: expected %s to win every match, got %+v", g.Name, g.Entries[0], table[0])
+		}
+	}
+	err = tm.Advance(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	playBracket(t, tm)
+	final := tm.Matches[len(tm.Matches)-1]
+	if final.Stage != "bracket" || final.Winner != "e1" {
+		t.Errorf("expected e1 to win the bracket, got %+v", final)
+	}
+}
+
+func TestEntryOf(t *testing.T) {
+	tm, err := tournament.New("test", []tournament.Entry{
+		{ID: "a", Name: "Alpha", URL: "http://alpha.test"},
+		{ID: "b", Name: "Beta"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tm.AddBracket([]string{"a", "b"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := tm.Matches[0]
+	for _, test := range []struct {
+		snake bsgf.ViewSnake
+		want  string
+	}{
+		{bsgf.ViewSnake{Name: "renamed", URL: "http://alpha.test"}, "a"},
+		{bsgf.ViewSnake{Name: "Alpha", URL: "http://alpha.test#build=v2"}, "a"},
+		{bsgf.ViewSnake{Name: "Alpha", URL: "http://other.test"}, ""},
+		{bsgf.ViewSnake{Name: "Beta", URL: "http://beta.test"}, "b"},
+	} {
+		id, ok := tm.EntryOf(m, &test.snake)
+		if id != test.want || ok != (test.want != "") {
+			t.Errorf("%+v: expected %q, got %q", test.snake, test.want, id)
+		}
+	}
+}
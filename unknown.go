@@ -0,0 +1,176 @@
+package battlesnakegameformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// DecodeKeepUnknown is like Decode but keeps the json fields that don't
+// match any struct field in the game's Unknown, so an archive from a newer
+// engine can be decoded, edited and encoded again without losing anything.
+// It walks the json twice, so it's slower than Decode.
+func DecodeKeepUnknown(data []byte) (*ViewGame, error) {
+	start := time.Now()
+	game, err := decodeKeepUnknown(data)
+	reportDecode(DecodeFormatArchive, start, len(data), game, err)
+	return game, err
+}
+
+func decodeKeepUnknown(data []byte) (*ViewGame, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating new zip reader: %w", ErrUnsupportedFormat, err)
+	}
+	unknown := make(map[string]map[string]json.RawMessage)
+	var game *ViewGame
+	if isChunkedZip(r) {
+		game, err = decodeChunkedZip(r, ParallelOptions{})
+		if err != nil {
+			return nil, err
+		}
+		err = checkChunked(r, &FieldError{}, unknown)
+	} else {
+		var buf bytes.Buffer
+		err = readArchiveInto(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+		game = &ViewGame{}
+		err = unmarshalJSON(buf.Bytes(), game)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error unmarshalling compressed game: %w", ErrCorruptArchive, err)
+		}
+		Intern(game)
+		err = checkFields(buf.Bytes(), reflect.TypeOf(game), "", &FieldError{}, unknown)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: error collecting unknown fields: %w", ErrCorruptArchive, err)
+	}
+	if len(unknown) > 0 {
+		game.Unknown = unknown
+	}
+	return game, nil
+}
+
+// marshalGame is the archive json for game, with its Unknown fields put back
+func marshalGame(game *ViewGame) ([]byte, error) {
+	contents, err := marshalJSON(game)
+	if err != nil {
+		return nil, err
+	}
+	return withUnknown(contents, game.Unknown)
+}
+
+// withUnknown adds the unknown fields to the objects at their paths in data.
+// Fields that data already has are left as they are, and paths that aren't
+// in data anymore are skipped.
+func withUnknown(data []byte, unknown map[string]map[string]json.RawMessage) ([]byte, error) {
+	if len(unknown) == 0 {
+		return data, nil
+	}
+	// every path on the way to an object with unknown fields
+	needed := make(map[string]bool)
+	for path := range unknown {
+		needed[""] = true
+		for i := 0; i < len(path); i++ {
+			if path[i] == '.' || path[i] == '[' {
+				needed[path[:i]] = true
+			}
+		}
+		needed[path] = true
+	}
+	out, err := spliceUnknown(data, "", needed, unknown)
+	if err != nil {
+		return nil, fmt.Errorf("error adding unknown fields: %w", err)
+	}
+	return out, nil
+}
+
+func spliceUnknown(data json.RawMessage, path string, needed map[string]bool, unknown map[string]map[string]json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(data)
+	if !needed[path] || len(trimmed) == 0 {
+		return data, nil
+	}
+	switch trimmed[0] {
+	case '[':
+		var items []json.RawMessage
+		err := json.Unmarshal(trimmed, &items)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, item := range items {
+			item, err = spliceUnknown(item, fmt.Sprintf("%s[%d]", path, i), needed, unknown)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(item)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		_, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		seen := make(map[string]bool)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected object key, found %v", tok)
+			}
+			var value json.RawMessage
+			err = dec.Decode(&value)
+			if err != nil {
+				return nil, err
+			}
+			value, err = spliceUnknown(value, fieldPath(path, key), needed, unknown)
+			if err != nil {
+				return nil, err
+			}
+			writeField(&buf, len(seen) > 0, key, value)
+			seen[key] = true
+		}
+		fields := unknown[path]
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			if !seen[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeField(&buf, len(seen) > 0, k, fields[k])
+			seen[k] = true
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+	}
+	return data, nil
+}
+
+func writeField(buf *bytes.Buffer, comma bool, key string, value json.RawMessage) {
+	if comma {
+		buf.WriteByte(',')
+	}
+	name, _ := json.Marshal(key)
+	buf.Write(name)
+	buf.WriteByte(':')
+	buf.Write(value)
+}
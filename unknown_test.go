@@ -0,0 +1,58 @@
+package battlesnakegameformat_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+func TestKeepUnknownRoundTrip(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	game.Unknown = map[string]map[string]json.RawMessage{
+		"Game":                {"Source": json.RawMessage(`"league"`)},
+		"Frames[3].Snakes[1]": {"Extra": json.RawMessage(`{"a":1}`)},
+	}
+	got, err := bsgf.DecodeKeepUnknown(encode(t, game))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameGame(t, game, got)
+	if !reflect.DeepEqual(got.Unknown, game.Unknown) {
+		t.Errorf("expected Unknown %v, got %v", game.Unknown, got.Unknown)
+	}
+	plain, err := bsgf.Decode(encode(t, game))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Unknown != nil {
+		t.Errorf("Decode kept unknown fields %v", plain.Unknown)
+	}
+}
+
+func TestKeepUnknownChunked(t *testing.T) {
+	game := testGame(t, bsgf.RulesetStandard)
+	// EncodeChunked doesn't write unknown fields, so add one to the manifest
+	data := rewriteZip(t, encodeChunked(t, game, 4), "chunks.json", func(contents []byte) []byte {
+		return bytes.Replace(contents, []byte(`"game": {`), []byte(`"game": {"Source": "league",`), 1)
+	})
+	got, err := bsgf.DecodeKeepUnknown(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameGame(t, game, got)
+	want := map[string]map[string]json.RawMessage{"Game": {"Source": json.RawMessage(`"league"`)}}
+	if !reflect.DeepEqual(got.Unknown, want) {
+		t.Errorf("expected Unknown %v, got %v", want, got.Unknown)
+	}
+	// the single file layout keeps them
+	again, err := bsgf.DecodeKeepUnknown(encode(t, got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Unknown, want) {
+		t.Errorf("expected Unknown %v after encoding again, got %v", want, again.Unknown)
+	}
+}
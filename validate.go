@@ -0,0 +1,120 @@
+package battlesnakegameformat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationProblem is one broken invariant found by Validate. Turn is -1
+// for problems with the game as a whole.
+type ValidationProblem struct {
+	Turn    int32
+	SnakeID string
+	// Check names the kind of problem, matching the check names used by
+	// the validate package
+	Check   string
+	Message string
+}
+
+func (p ValidationProblem) String() string {
+	if p.SnakeID != "" {
+		return fmt.Sprintf("turn %d: %s: snake %s: %s", p.Turn, p.Check, p.SnakeID, p.Message)
+	}
+	return fmt.Sprintf("turn %d: %s: %s", p.Turn, p.Check, p.Message)
+}
+
+// ValidationError is returned by Validate and lists every problem found
+type ValidationError struct {
+	GameID   string
+	Problems []ValidationProblem
+}
+
+func (e *ValidationError) Error() string {
+	descriptions := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		descriptions[i] = p.String()
+	}
+	return fmt.Sprintf("invalid game %s: %s", e.GameID, strings.Join(descriptions, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidGame
+}
+
+func (e *ValidationError) add(turn int32, snakeId string, check string, format string, args ...interface{}) {
+	e.Problems = append(e.Problems, ValidationProblem{
+		Turn:    turn,
+		SnakeID: snakeId,
+		Check:   check,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks invariants that hold for each frame on its own, without
+// simulating the game: board size, turn numbering, FirstFrame and LastTurn
+// matching the frames, coordinates in bounds, non-empty bodies and unique
+// snake IDs. It returns a *ValidationError when anything is wrong.
+func (game *ViewGame) Validate() error {
+	e := &ValidationError{GameID: game.Game.ID}
+	game.validate(e)
+	if len(e.Problems) > 0 {
+		return e
+	}
+	return nil
+}
+
+func (game *ViewGame) validate(e *ValidationError) {
+	width, height := game.Game.Width, game.Game.Height
+	if width <= 0 || height <= 0 {
+		e.add(-1, "", "board", "invalid board size %dx%d", width, height)
+		return
+	}
+	if len(game.Frames) == 0 {
+		e.add(-1, "", "frames", "game has no frames")
+		return
+	}
+	last := game.Frames[len(game.Frames)-1].Turn
+	if game.LastTurn != last {
+		e.add(-1, "", "turn", "LastTurn is %d but the last frame is turn %d", game.LastTurn, last)
+	}
+	if game.FirstFrame.Turn != game.Frames[0].Turn || len(game.FirstFrame.Snakes) != len(game.Frames[0].Snakes) {
+		e.add(-1, "", "frames", "FirstFrame doesn't match the first frame")
+	}
+	for i := range game.Frames {
+		frame := &game.Frames[i]
+		if frame.Turn != int32(i) {
+			e.add(frame.Turn, "", "turn", "frame %d has turn %d", i, frame.Turn)
+		}
+		for _, p := range frame.Food {
+			if !p.InBounds(width, height) {
+				e.add(frame.Turn, "", "bounds", "food at (%d,%d) is off the board", p.X, p.Y)
+			}
+		}
+		for _, p := range frame.Hazards {
+			if !p.InBounds(width, height) {
+				e.add(frame.Turn, "", "bounds", "hazard at (%d,%d) is off the board", p.X, p.Y)
+			}
+		}
+		seen := make(map[string]bool, len(frame.Snakes))
+		for _, s := range frame.Snakes {
+			if seen[s.ID] {
+				e.add(frame.Turn, s.ID, "snake-id", "duplicate snake ID")
+			}
+			seen[s.ID] = true
+			if len(s.Body) == 0 {
+				e.add(frame.Turn, s.ID, "body", "empty body")
+				continue
+			}
+			if s.Death.Eliminated() {
+				// eliminated snakes may have moved off the board
+				continue
+			}
+			for _, p := range s.Body {
+				if !p.InBounds(width, height) {
+					e.add(frame.Turn, s.ID, "bounds", "body at (%d,%d) is off the board", p.X, p.Y)
+					break
+				}
+			}
+		}
+	}
+}
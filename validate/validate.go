@@ -0,0 +1,215 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+)
+
+// Finding is a single problem found in a game. Turn is -1 for problems with
+// the game as a whole.
+type Finding struct {
+	GameID  string `json:"gameId"`
+	Turn    int32  `json:"turn"`
+	SnakeID string `json:"snakeId,omitempty"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.SnakeID != "" {
+		return fmt.Sprintf("turn %d: %s: snake %s: %s", f.Turn, f.Check, f.SnakeID, f.Message)
+	}
+	return fmt.Sprintf("turn %d: %s: %s", f.Turn, f.Check, f.Message)
+}
+
+// Names of the checks reported in findings
+const (
+	CheckBoard      = "board"
+	CheckFrames     = "frames"
+	CheckTurn       = "turn"
+	CheckBounds     = "bounds"
+	CheckBody       = "body"
+	CheckSnakeID    = "snake-id"
+	CheckMovement   = "movement"
+	CheckHealth     = "health"
+	CheckGrowth     = "growth"
+	CheckDeath      = "death"
+	CheckContinuity = "continuity"
+)
+
+// Game runs both the structural and the replay consistency checks
+func Game(game *bsgf.ViewGame) []Finding {
+	findings := Structure(game)
+	return append(findings, Replay(game)...)
+}
+
+type collector struct {
+	gameId   string
+	findings []Finding
+}
+
+func (c *collector) add(turn int32, snakeId string, check string, format string, args ...interface{}) {
+	c.findings = append(c.findings, Finding{
+		GameID:  c.gameId,
+		Turn:    turn,
+		SnakeID: snakeId,
+		Check:   check,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Structure checks invariants that hold for each frame on its own: board
+// size, turn numbering, coordinates in bounds, non-empty bodies and unique
+// snake IDs. These are the problems (*bsgf.ViewGame).Validate reports.
+func Structure(game *bsgf.ViewGame) []Finding {
+	var e *bsgf.ValidationError
+	if !errors.As(game.Validate(), &e) {
+		return nil
+	}
+	findings := make([]Finding, len(e.Problems))
+	for i, p := range e.Problems {
+		findings[i] = Finding{GameID: e.GameID, Turn: p.Turn, SnakeID: p.SnakeID, Check: p.Check, Message: p.Message}
+	}
+	return findings
+}
+
+// Replay checks that consecutive frames are consistent with the game rules:
+// heads move one step at a time, bodies follow, health only resets when
+// eating, snakes only grow after eating and eliminations are permanent.
+// Constrictor snakes instead grow every turn and never lose health, and in
+// squad games a squad mate eating counts for shared health and length.
+func Replay(game *bsgf.ViewGame) []Finding {
+	c := collector{gameId: game.Game.ID}
+	name := game.Game.Ruleset.RulesetName()
+	wrapped, constrictor := name.Wrapped(), name.Constrictor()
+	settings, _ := game.Game.Ruleset.DecodeSettings()
+	squad, _ := settings.(*bsgf.SquadSettings)
+	for i := 1; i < len(game.Frames); i++ {
+		prev := &game.Frames[i-1]
+		frame := &game.Frames[i]
+		food := make(map[bsgf.ViewCoord]bool, len(prev.Food))
+		for _, p := range prev.Food {
+			food[p] = true
+		}
+		// squads with a snake that ate this turn
+		squadAte := make(map[string]bool)
+		for j := range frame.Snakes {
+			s := &frame.Snakes[j]
+			if s.Squad != "" && len(s.Body) > 0 && food[s.Body[0]] {
+				squadAte[s.Squad] = true
+			}
+		}
+		for j := range prev.Snakes {
+			before := &prev.Snakes[j]
+			after := findSnake(frame, before.ID)
+			if after == nil {
+				c.add(frame.Turn, before.ID, CheckContinuity, "snake disappeared")
+				continue
+			}
+			if before.Death.Eliminated() {
+				if after.Death != before.Death {
+					c.add(frame.Turn, before.ID, CheckDeath, "eliminated snake changed death from %v to %v", before.Death, after.Death)
+				}
+				continue
+			}
+			if after.Death.Eliminated() {
+				if after.Death.Turn != frame.Turn {
+					c.add(frame.Turn, before.ID, CheckDeath, "eliminated on turn %d but first marked dead on turn %d", after.Death.Turn, frame.Turn)
+				}
+				continue
+			}
+			if len(before.Body) == 0 || len(after.Body) == 0 {
+				continue
+			}
+			c.movement(game, frame.Turn, before, after, wrapped)
+			if constrictor {
+				c.constrictorGrowth(frame.Turn, before, after)
+				continue
+			}
+			ate := food[after.Body[0]]
+			grew, healed := ate, ate
+			if squad != nil && squadAte[after.Squad] {
+				grew = grew || squad.SharedLength
+				healed = healed || squad.SharedHealth
+			}
+			c.growth(frame.Turn, before, after, grew)
+			if !healed && after.Health >= before.Health {
+				c.add(frame.Turn, before.ID, CheckHealth, "health went from %d to %d without eating", before.Health, after.Health)
+			}
+		}
+		for j := range frame.Snakes {
+			if findSnake(prev, frame.Snakes[j].ID) == nil {
+				c.add(frame.Turn, frame.Snakes[j].ID, CheckContinuity, "snake appeared mid game")
+			}
+		}
+	}
+	return c.findings
+}
+
+func (c *collector) movement(game *bsgf.ViewGame, turn int32, before, after *bsgf.ViewSnake, wrapped bool) {
+	from, to := before.Body[0], after.Body[0]
+	dx, dy := abs(to.X-from.X), abs(to.Y-from.Y)
+	if wrapped {
+		if dx == game.Game.Width-1 {
+			dx = 1
+		}
+		if dy == game.Game.Height-1 {
+			dy = 1
+		}
+	}
+	if dx+dy != 1 {
+		c.add(turn, before.ID, CheckMovement, "head moved from (%d,%d) to (%d,%d)", from.X, from.Y, to.X, to.Y)
+		return
+	}
+	// every segment behind the head takes the place of the one in front of it
+	for k := 1; k < len(after.Body) && k < len(before.Body); k++ {
+		if after.Body[k] != before.Body[k-1] {
+			c.add(turn, before.ID, CheckBody, "body segment %d didn't follow the head", k)
+			return
+		}
+	}
+}
+
+func (c *collector) growth(turn int32, before, after *bsgf.ViewSnake, ate bool) {
+	diff := len(after.Body) - len(before.Body)
+	switch {
+	case ate && diff != 1:
+		c.add(turn, before.ID, CheckGrowth, "ate food but length changed by %d", diff)
+	case !ate && diff != 0:
+		c.add(turn, before.ID, CheckGrowth, "length changed by %d without eating", diff)
+	}
+}
+
+// constrictorGrowth checks a constrictor snake grew by one segment, which
+// the rules skip when its tail is already stacked after moving
+func (c *collector) constrictorGrowth(turn int32, before, after *bsgf.ViewSnake) {
+	n := len(before.Body)
+	if n < 3 {
+		return
+	}
+	want := 1
+	if before.Body[n-2] == before.Body[n-3] {
+		want = 0
+	}
+	if diff := len(after.Body) - n; diff != want {
+		c.add(turn, before.ID, CheckGrowth, "length changed by %d, expected %d in a constrictor game", diff, want)
+	}
+}
+
+func findSnake(frame *bsgf.ViewFrame, id string) *bsgf.ViewSnake {
+	for i := range frame.Snakes {
+		if frame.Snakes[i].ID == id {
+			return &frame.Snakes[i]
+		}
+	}
+	return nil
+}
+
+func abs(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,162 @@
+package validate_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	bsgf "github.com/jlafayette/battlesnake-game-format-go"
+	"github.com/jlafayette/battlesnake-game-format-go/harness"
+	"github.com/jlafayette/battlesnake-game-format-go/validate"
+)
+
+func play(t *testing.T, ruleset string) *bsgf.ViewGame {
+	t.Helper()
+	players := []harness.Player{
+		{Name: "Alpha", Snake: harness.NewRandomSnake(1)},
+		{Name: "Beta", Snake: harness.NewRandomSnake(2)},
+		{Name: "Gamma", Snake: harness.NewRandomSnake(3)},
+	}
+	game, err := harness.Play(context.Background(), players, harness.PlayOptions{Seed: 7, Ruleset: bsgf.ViewRuleset{Name: ruleset}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func checks(findings []validate.Finding) map[string]bool {
+	found := make(map[string]bool)
+	for _, f := range findings {
+		found[f.Check] = true
+	}
+	return found
+}
+
+func TestGameRulesets(t *testing.T) {
+	for _, ruleset := range []string{"standard", "solo", "royale", "constrictor", "wrapped", "wrapped_constrictor"} {
+		t.Run(ruleset, func(t *testing.T) {
+			for _, f := range validate.Game(play(t, ruleset)) {
+				t.Error(f)
+			}
+		})
+	}
+}
+
+// moved finds a turn where the first snake is alive before and after, with
+// a body long enough to change, and returns it and the snake before it
+func moved(t *testing.T, game *bsgf.ViewGame) (*bsgf.ViewSnake, *bsgf.ViewSnake) {
+	t.Helper()
+	for i := 1; i < len(game.Frames); i++ {
+		before, after := &game.Frames[i-1].Snakes[0], &game.Frames[i].Snakes[0]
+		if !after.Death.Eliminated() && len(before.Body) >= 3 && len(after.Body) == len(before.Body) && after.Health < before.Health {
+			return before, after
+		}
+	}
+	t.Fatal("no turn to tamper with")
+	return nil, nil
+}
+
+func TestReplayTampered(t *testing.T) {
+	tests := []struct {
+		name    string
+		ruleset string
+		check   string
+		tamper  func(before, after *bsgf.ViewSnake)
+	}{
+		{"grew without eating", "standard", validate.CheckGrowth, func(before, after *bsgf.ViewSnake) {
+			after.Body = append(after.Body, after.Body[len(after.Body)-1])
+		}},
+		{"healed without eating", "standard", validate.CheckHealth, func(before, after *bsgf.ViewSnake) {
+			after.Health = before.Health
+		}},
+		{"head jumped", "standard", validate.CheckMovement, func(before, after *bsgf.ViewSnake) {
+			after.Body[0] = before.Body[2]
+		}},
+		{"body left behind", "standard", validate.CheckBody, func(before, after *bsgf.ViewSnake) {
+			after.Body[1] = after.Body[0]
+		}},
+		{"constrictor didn't grow", "constrictor", validate.CheckGrowth, func(before, after *bsgf.ViewSnake) {
+			after.Body = after.Body[:len(after.Body)-1]
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game := play(t, test.ruleset)
+			if test.ruleset == "constrictor" {
+				// constrictor snakes are the same length as before only
+				// when their tail was stacked, so pick any turn
+				before, after := &game.Frames[3].Snakes[0], &game.Frames[4].Snakes[0]
+				test.tamper(before, after)
+			} else {
+				test.tamper(moved(t, game))
+			}
+			if found := checks(validate.Replay(game)); !found[test.check] {
+				t.Errorf("expected a %s finding, got %v", test.check, validate.Replay(game))
+			}
+		})
+	}
+}
+
+// squadGame has two squad mates, where Alpha eats on turn 1 and Beta gains
+// health and length only when they're shared
+func squadGame(settings map[string]string, betaHealth int32, betaBody []bsgf.ViewCoord) *bsgf.ViewGame {
+	ruleset := bsgf.ViewRuleset{Name: string(bsgf.RulesetSquad), Settings: map[string]json.RawMessage{}}
+	for k, v := range settings {
+		ruleset.Settings[k] = json.RawMessage(`"` + v + `"`)
+	}
+	frames := []bsgf.ViewFrame{
+		{Turn: 0, Food: []bsgf.ViewCoord{{X: 2, Y: 0}}, Snakes: []bsgf.ViewSnake{
+			{ID: "a", Squad: "red", Health: 50, Body: []bsgf.ViewCoord{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}}},
+			{ID: "b", Squad: "red", Health: 60, Body: []bsgf.ViewCoord{{X: 4, Y: 4}, {X: 4, Y: 3}, {X: 4, Y: 2}}},
+		}},
+		{Turn: 1, Snakes: []bsgf.ViewSnake{
+			{ID: "a", Squad: "red", Health: 100, Body: []bsgf.ViewCoord{{X: 2, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 1}}},
+			{ID: "b", Squad: "red", Health: betaHealth, Body: betaBody},
+		}},
+	}
+	return &bsgf.ViewGame{
+		Game:       bsgf.ViewGameSettings{ID: "squad", Ruleset: ruleset, Width: 5, Height: 5, Status: "complete"},
+		Frames:     frames,
+		FirstFrame: frames[0],
+		LastTurn:   1,
+	}
+}
+
+func TestReplaySquad(t *testing.T) {
+	moved := []bsgf.ViewCoord{{X: 3, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 3}}
+	grown := append(append([]bsgf.ViewCoord(nil), moved...), bsgf.ViewCoord{X: 4, Y: 3})
+	tests := []struct {
+		name     string
+		settings map[string]string
+		health   int32
+		body     []bsgf.ViewCoord
+		want     string
+	}{
+		{"not shared", nil, 59, moved, ""},
+		{"health not shared", nil, 100, moved, validate.CheckHealth},
+		{"shared health", map[string]string{"sharedHealth": "true"}, 100, moved, ""},
+		{"shared length", map[string]string{"sharedHealth": "true", "sharedLength": "true"}, 100, grown, ""},
+		{"length not shared", map[string]string{"sharedHealth": "true"}, 100, grown, validate.CheckGrowth},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings := validate.Game(squadGame(test.settings, test.health, test.body))
+			if test.want == "" {
+				for _, f := range findings {
+					t.Error(f)
+				}
+			} else if !checks(findings)[test.want] || len(findings) != 1 {
+				t.Errorf("expected one %s finding, got %v", test.want, findings)
+			}
+		})
+	}
+}
+
+func TestStructure(t *testing.T) {
+	game := play(t, "standard")
+	game.Frames[2].Snakes[1].Body[0] = bsgf.ViewCoord{X: game.Game.Width, Y: 0}
+	findings := validate.Structure(game)
+	if len(findings) != 1 || findings[0].Check != validate.CheckBounds || findings[0].Turn != game.Frames[2].Turn {
+		t.Errorf("expected a bounds finding on turn %d, got %v", game.Frames[2].Turn, findings)
+	}
+}